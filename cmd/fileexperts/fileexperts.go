@@ -109,23 +109,33 @@ func initHandleOptions(params paramscmd.Params) []heartbeat.HandleOption {
 	return []heartbeat.HandleOption{
 		heartbeat.WithFormatting(),
 		heartbeat.WithEntityModifier(),
+		project.WithPathTranslation(params.Heartbeat.Project.PathTranslations),
+		project.WithFileSettings(),
 		filter.WithFiltering(filter.Config{
 			Exclude:                    params.Heartbeat.Filter.Exclude,
 			Include:                    params.Heartbeat.Filter.Include,
 			IncludeOnlyWithProjectFile: params.Heartbeat.Filter.IncludeOnlyWithProjectFile,
 		}),
 		apikey.WithReplacing(apikey.Config{
-			DefaultAPIKey: params.API.Key,
-			MapPatterns:   params.API.KeyPatterns,
+			AllowProjectFile: params.API.AllowProjectFileKey,
+			DefaultAPIKey:    params.API.Key,
+			MapPatterns:      params.API.KeyPatterns,
 		}),
 		project.WithDetection(project.Config{
+			GitRemoteAliases:     params.Heartbeat.Project.GitRemoteAliases,
+			GitRemoteNames:       params.Heartbeat.Project.GitRemoteNames,
+			GitWorkTrees:         params.Heartbeat.Project.GitWorkTrees,
 			HideProjectNames:     params.Heartbeat.Sanitize.HideProjectNames,
+			Hostname:             params.API.Hostname,
 			MapPatterns:          params.Heartbeat.Project.MapPatterns,
+			MapRules:             params.Heartbeat.Project.MapRules,
 			ProjectFromGitRemote: params.Heartbeat.Project.ProjectFromGitRemote,
 			Submodule: project.Submodule{
 				DisabledPatterns: params.Heartbeat.Project.SubmodulesDisabled,
 				MapPatterns:      params.Heartbeat.Project.SubmoduleMapPatterns,
 			},
+			WorkspaceDetection:     params.Heartbeat.Project.WorkspaceDetection,
+			WorkspaceProjectFormat: params.Heartbeat.Project.WorkspaceFormat,
 		}),
 		project.WithFiltering(project.FilterConfig{
 			ExcludeUnknownProject: params.Heartbeat.Filter.ExcludeUnknownProject,
@@ -0,0 +1,210 @@
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	paramscmd "github.com/wakatime/wakatime-cli/cmd/params"
+	"github.com/wakatime/wakatime-cli/pkg/exitcode"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/output"
+	"github.com/wakatime/wakatime-cli/pkg/wakaerror"
+
+	"github.com/spf13/viper"
+)
+
+// RunDryRun executes the heartbeat command without sending heartbeats
+// to the api and prints the processed heartbeats instead.
+func RunDryRun(ctx context.Context, v *viper.Viper) (int, error) {
+	out, err := DryRun(ctx, v)
+	if err != nil {
+		if errwaka, ok := err.(wakaerror.Error); ok {
+			return errwaka.ExitCode(), fmt.Errorf("dry run failed: %w", errwaka)
+		}
+
+		return exitcode.ErrGeneric, fmt.Errorf("dry run failed: %w", err)
+	}
+
+	logger := log.Extract(ctx)
+	logger.Debugln("successfully processed heartbeat(s) in dry run")
+
+	fmt.Println(out)
+
+	return exitcode.Success, nil
+}
+
+// DryRun runs the complete heartbeat processing pipeline, but instead of sending
+// the heartbeats to the api, it renders the resulting heartbeats including
+// the ones dropped while processing them.
+func DryRun(ctx context.Context, v *viper.Viper) (string, error) {
+	params, err := LoadParams(ctx, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to load command parameters: %w", err)
+	}
+
+	var rejected []paramscmd.ExtraHeartbeatError
+
+	if params.Heartbeat.ExtraHeartbeatsNDJSON {
+		params.Heartbeat.ExtraHeartbeats, rejected = readExtraHeartbeatsStream(ctx, os.Stdin)
+	}
+
+	hh, dropped, err := process(ctx, params, buildHeartbeats(ctx, params))
+	if err != nil {
+		return "", err
+	}
+
+	return renderDryRun(hh, dropped, rejected, params.Heartbeat.Output)
+}
+
+// process runs the heartbeat processing pipeline with decision recording enabled
+// and returns the heartbeats, which would have been sent to the api, and the
+// heartbeats dropped while processing them.
+func process(
+	ctx context.Context,
+	params paramscmd.Params,
+	heartbeats []heartbeat.Heartbeat,
+) ([]heartbeat.Heartbeat, []heartbeat.Dropped, error) {
+	logger := log.Extract(ctx)
+
+	setLogFields(ctx, params)
+	logger.Debugf("params: %s", params)
+
+	ctx, recorder := heartbeat.WithRecorder(ctx)
+	sender := &dryRunSender{}
+
+	handle := heartbeat.NewHandle(sender, initHandleOptions(params)...)
+
+	if _, err := handle(ctx, heartbeats); err != nil {
+		return nil, nil, err
+	}
+
+	return sender.heartbeats, recorder.Dropped(), nil
+}
+
+// dryRunSender is a heartbeat.Sender keeping the heartbeats instead of sending them.
+type dryRunSender struct {
+	heartbeats []heartbeat.Heartbeat
+}
+
+// SendHeartbeats implements heartbeat.Sender interface.
+func (s *dryRunSender) SendHeartbeats(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+	s.heartbeats = append(s.heartbeats, hh...)
+
+	results := make([]heartbeat.Result, len(hh))
+	for i, h := range hh {
+		results[i] = heartbeat.Result{
+			Status:    201,
+			Heartbeat: h,
+		}
+	}
+
+	return results, nil
+}
+
+// dryRunHeartbeat is the json representation of a heartbeat in dry run output.
+type dryRunHeartbeat struct {
+	heartbeat.Heartbeat
+	Decisions     []heartbeat.Decision `json:"decisions,omitempty"`
+	DroppedReason string               `json:"dropped_reason,omitempty"`
+}
+
+func renderDryRun(
+	hh []heartbeat.Heartbeat,
+	dropped []heartbeat.Dropped,
+	rejected []paramscmd.ExtraHeartbeatError,
+	out output.Output,
+) (string, error) {
+	if out == output.JSONOutput || out == output.RawJSONOutput {
+		result := struct {
+			Heartbeats []dryRunHeartbeat               `json:"heartbeats"`
+			Dropped    []dryRunHeartbeat               `json:"dropped"`
+			Rejected   []paramscmd.ExtraHeartbeatError `json:"rejected,omitempty"`
+		}{
+			Heartbeats: []dryRunHeartbeat{},
+			Dropped:    []dryRunHeartbeat{},
+			Rejected:   rejected,
+		}
+
+		for _, h := range hh {
+			result.Heartbeats = append(result.Heartbeats, dryRunHeartbeat{
+				Heartbeat: h,
+				Decisions: h.Decisions,
+			})
+		}
+
+		for _, d := range dropped {
+			result.Dropped = append(result.Dropped, dryRunHeartbeat{
+				Heartbeat:     d.Heartbeat,
+				Decisions:     d.Heartbeat.Decisions,
+				DroppedReason: d.Reason,
+			})
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal json dry run result: %s", err)
+		}
+
+		return string(data), nil
+	}
+
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "STATUS\tENTITY\tPROJECT\tBRANCH\tLANGUAGE\tCATEGORY\tNOTES")
+
+	for _, h := range hh {
+		fmt.Fprintln(w, dryRunRow("send", h, notes(h.Decisions)))
+	}
+
+	for _, d := range dropped {
+		fmt.Fprintln(w, dryRunRow("dropped", d.Heartbeat, append(notes(d.Heartbeat.Decisions), d.Reason)))
+	}
+
+	for _, r := range rejected {
+		fmt.Fprintf(w, "rejected\tline %d\t-\t-\t-\t-\t%s\n", r.Line, r.Error)
+	}
+
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("failed to render dry run result: %s", err)
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func dryRunRow(status string, h heartbeat.Heartbeat, notes []string) string {
+	return strings.Join([]string{
+		status,
+		h.Entity,
+		valueOrDash(h.Project),
+		valueOrDash(h.Branch),
+		valueOrDash(h.Language),
+		h.Category.String(),
+		strings.Join(notes, "; "),
+	}, "\t")
+}
+
+func notes(decisions []heartbeat.Decision) []string {
+	var result []string
+
+	for _, d := range decisions {
+		result = append(result, fmt.Sprintf("%s: %s", d.Stage, d.Message))
+	}
+
+	return result
+}
+
+func valueOrDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+
+	return *s
+}
@@ -0,0 +1,157 @@
+package heartbeat_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	cmdheartbeat "github.com/wakatime/wakatime-cli/cmd/heartbeat"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	resetSingleton(t)
+
+	v := viper.New()
+	v.Set("category", "debugging")
+	v.Set("entity", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("output", "json")
+	v.Set("project", "wakatime-cli")
+	v.Set("time", 1585598059.1)
+
+	out, err := cmdheartbeat.DryRun(context.Background(), v)
+	require.NoError(t, err)
+
+	var result struct {
+		Heartbeats []struct {
+			Category  string `json:"category"`
+			Entity    string `json:"entity"`
+			Language  string `json:"language"`
+			Project   string `json:"project"`
+			Decisions []struct {
+				Stage     string `json:"stage"`
+				Rule      string `json:"rule"`
+				ConfigKey string `json:"config_key"`
+				Field     string `json:"field"`
+				Value     string `json:"value"`
+			} `json:"decisions"`
+		} `json:"heartbeats"`
+		Dropped []any `json:"dropped"`
+	}
+
+	err = json.Unmarshal([]byte(out), &result)
+	require.NoError(t, err)
+
+	require.Len(t, result.Heartbeats, 1)
+	assert.Empty(t, result.Dropped)
+
+	h := result.Heartbeats[0]
+
+	assert.Equal(t, "debugging", h.Category)
+	assert.Contains(t, h.Entity, "testdata/main.go")
+	assert.Equal(t, "Go", h.Language)
+	assert.Equal(t, "wakatime-cli", h.Project)
+
+	require.NotEmpty(t, h.Decisions)
+	assert.Equal(t, "project", h.Decisions[0].Stage)
+	assert.Equal(t, "project override", h.Decisions[0].Rule)
+	assert.Equal(t, "project", h.Decisions[0].ConfigKey)
+	assert.Equal(t, "project", h.Decisions[0].Field)
+	assert.Equal(t, "wakatime-cli", h.Decisions[0].Value)
+}
+
+func TestDryRun_Dropped(t *testing.T) {
+	resetSingleton(t)
+
+	v := viper.New()
+	v.Set("entity", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("exclude", "main.go$")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("time", 1585598059.1)
+
+	out, err := cmdheartbeat.DryRun(context.Background(), v)
+	require.NoError(t, err)
+
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 2)
+
+	assert.Equal(t, []string{"STATUS", "ENTITY", "PROJECT", "BRANCH", "LANGUAGE", "CATEGORY", "NOTES"}, strings.Fields(lines[0]))
+	assert.True(t, strings.HasPrefix(lines[1], "dropped "))
+	assert.Contains(t, lines[1], "testdata/main.go")
+	assert.True(t, strings.HasSuffix(lines[1], `filter by pattern: skipping because matches exclude pattern "(?i)main.go$"`))
+}
+
+func TestDryRun_ExtraHeartbeatsNDJSON(t *testing.T) {
+	resetSingleton(t)
+	setStdin(t, strings.Join([]string{
+		`{"category": "coding", "entity": "testdata/main.go", "entity_type": "file", "project": "billing", "time": 1585598060}`,
+		`{"category": "coding", "entity": "testdata/main.py", "entity_type": "file", "project": "billing", "time": "invalid"}`,
+		`{"category": "debugging", "entity": "testdata/main.py", "entity_type": "file", "project": "billing", "time": 1585598061}`,
+	}, "\n"))
+
+	v := viper.New()
+	v.Set("category", "debugging")
+	v.Set("entity", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("extra-heartbeats", true)
+	v.Set("extra-heartbeats-format", "ndjson")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("output", "json")
+	v.Set("project", "wakatime-cli")
+	v.Set("time", 1585598059.1)
+
+	out, err := cmdheartbeat.DryRun(context.Background(), v)
+	require.NoError(t, err)
+
+	var result struct {
+		Heartbeats []struct {
+			Entity  string `json:"entity"`
+			Project string `json:"project"`
+		} `json:"heartbeats"`
+		Rejected []struct {
+			Line int `json:"line"`
+		} `json:"rejected"`
+	}
+
+	err = json.Unmarshal([]byte(out), &result)
+	require.NoError(t, err)
+
+	require.Len(t, result.Heartbeats, 3)
+	assert.Equal(t, "wakatime-cli", result.Heartbeats[0].Project)
+	assert.Equal(t, "billing", result.Heartbeats[1].Project)
+	assert.True(t, strings.HasSuffix(result.Heartbeats[2].Entity, "testdata/main.py"))
+
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, 2, result.Rejected[0].Line)
+}
+
+// setStdin replaces stdin with a pipe providing data until the end of the test.
+func setStdin(t *testing.T, data string) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	origStdin := os.Stdin
+	os.Stdin = r
+
+	t.Cleanup(func() {
+		os.Stdin = origStdin
+
+		r.Close()
+	})
+
+	go func() {
+		_, _ = w.WriteString(data)
+
+		w.Close()
+	}()
+}
@@ -0,0 +1,162 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/exitcode"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/output"
+	"github.com/wakatime/wakatime-cli/pkg/wakaerror"
+
+	"github.com/spf13/viper"
+)
+
+// RunExplain executes the explain command, which prints the decisions taken
+// while processing a heartbeat for the entity passed in via --explain.
+func RunExplain(ctx context.Context, v *viper.Viper) (int, error) {
+	out, err := Explain(ctx, v)
+	if err != nil {
+		if errwaka, ok := err.(wakaerror.Error); ok {
+			return errwaka.ExitCode(), fmt.Errorf("explain failed: %w", errwaka)
+		}
+
+		return exitcode.ErrGeneric, fmt.Errorf("explain failed: %w", err)
+	}
+
+	logger := log.Extract(ctx)
+	logger.Debugln("successfully explained heartbeat")
+
+	fmt.Println(out)
+
+	return exitcode.Success, nil
+}
+
+// Explain runs the complete heartbeat processing pipeline for a single heartbeat
+// without sending it to the api and renders the trace of decisions taken by the
+// filter, sanitize, project and plugin stages. If extra heartbeats contain the
+// explained entity, the first of them is explained instead of the heartbeat
+// built from command line parameters.
+func Explain(ctx context.Context, v *viper.Viper) (string, error) {
+	params, err := LoadParams(ctx, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to load command parameters: %w", err)
+	}
+
+	extraHeartbeats := params.Heartbeat.ExtraHeartbeats
+	if params.Heartbeat.ExtraHeartbeatsNDJSON {
+		extraHeartbeats, _ = readExtraHeartbeatsStream(ctx, os.Stdin)
+	}
+
+	// only the explained entity is of interest
+	params.Heartbeat.ExtraHeartbeats = nil
+
+	heartbeats := buildHeartbeats(ctx, params)
+
+	// an extra heartbeat of the entity is explained with its own metadata
+	// instead of the one passed in via command line
+	for _, h := range extraHeartbeats {
+		if h.Entity == params.Heartbeat.Entity {
+			heartbeats = []heartbeat.Heartbeat{newExtraHeartbeat(h, heartbeats[0].UserAgent)}
+			break
+		}
+	}
+
+	hh, dropped, err := process(ctx, params, heartbeats)
+	if err != nil {
+		return "", err
+	}
+
+	result := explanation{Status: "send"}
+
+	switch {
+	case len(hh) > 0:
+		result.Heartbeat = hh[0]
+	case len(dropped) > 0:
+		result.Heartbeat = dropped[0].Heartbeat
+		result.Status = "dropped"
+		result.DroppedReason = dropped[0].Reason
+	default:
+		return "", fmt.Errorf("heartbeat for entity %q was neither sent nor dropped", params.Heartbeat.Entity)
+	}
+
+	result.Decisions = result.Heartbeat.Decisions
+	if result.Decisions == nil {
+		result.Decisions = []heartbeat.Decision{}
+	}
+
+	return renderExplanation(result, params.Heartbeat.Output)
+}
+
+// explanation is the json representation of the explain command output.
+type explanation struct {
+	Status        string               `json:"status"`
+	DroppedReason string               `json:"dropped_reason,omitempty"`
+	Decisions     []heartbeat.Decision `json:"decisions"`
+	Heartbeat     heartbeat.Heartbeat  `json:"heartbeat"`
+}
+
+func renderExplanation(e explanation, out output.Output) (string, error) {
+	if out == output.JSONOutput || out == output.RawJSONOutput {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal json explanation: %s", err)
+		}
+
+		return string(data), nil
+	}
+
+	var lines []string
+
+	lines = append(lines, fmt.Sprintf("entity: %s", e.Heartbeat.Entity))
+
+	if e.Status == "dropped" {
+		lines = append(lines, fmt.Sprintf("status: dropped (%s)", e.DroppedReason))
+	} else {
+		lines = append(lines, "status: send")
+	}
+
+	lines = append(lines, "decisions:")
+
+	if len(e.Decisions) == 0 {
+		lines = append(lines, "  none")
+	}
+
+	for i, d := range e.Decisions {
+		lines = append(lines, fmt.Sprintf("  %d. %s", i+1, explainDecision(d)))
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("project: %s", valueOrDash(e.Heartbeat.Project)),
+		fmt.Sprintf("branch: %s", valueOrDash(e.Heartbeat.Branch)),
+		fmt.Sprintf("language: %s", valueOrDash(e.Heartbeat.Language)),
+		fmt.Sprintf("category: %s", e.Heartbeat.Category),
+	)
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// explainDecision formats a single decision as one line of text.
+func explainDecision(d heartbeat.Decision) string {
+	s := fmt.Sprintf("[%s] %s", d.Stage, d.Rule)
+
+	if d.ConfigKey != "" {
+		s += fmt.Sprintf(" (%s)", d.ConfigKey)
+	}
+
+	if d.Pattern != "" {
+		s += fmt.Sprintf(" pattern %q", d.Pattern)
+	}
+
+	s += ": " + d.Message
+
+	if d.Field != "" {
+		s += fmt.Sprintf(" [%s=%q]", d.Field, d.Value)
+	}
+
+	return s
+}
@@ -0,0 +1,131 @@
+package heartbeat_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	cmdheartbeat "github.com/wakatime/wakatime-cli/cmd/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	resetSingleton(t)
+
+	v := viper.New()
+	v.Set("explain", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("hide-branch-names", "true")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("output", "json")
+	v.Set("project", "wakatime-cli")
+	v.Set("time", 1585598059.1)
+
+	out, err := cmdheartbeat.Explain(context.Background(), v)
+	require.NoError(t, err)
+
+	var result struct {
+		Status    string               `json:"status"`
+		Decisions []heartbeat.Decision `json:"decisions"`
+		Heartbeat struct {
+			Entity  string  `json:"entity"`
+			Project string  `json:"project"`
+			Branch  *string `json:"branch"`
+		} `json:"heartbeat"`
+	}
+
+	err = json.Unmarshal([]byte(out), &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "send", result.Status)
+	assert.Contains(t, result.Heartbeat.Entity, "testdata/main.go")
+	assert.Equal(t, "wakatime-cli", result.Heartbeat.Project)
+	assert.Nil(t, result.Heartbeat.Branch)
+
+	assert.Contains(t, result.Decisions, heartbeat.Decision{
+		Stage:     "project",
+		Rule:      "project override",
+		ConfigKey: "project",
+		Field:     "project",
+		Value:     "wakatime-cli",
+		Message:   "project set by project override",
+	})
+	assert.Contains(t, result.Decisions, heartbeat.Decision{
+		Stage:     "sanitize",
+		Rule:      "hide branch names",
+		ConfigKey: "settings.hide_branch_names",
+		Pattern:   ".*",
+		Field:     "branch",
+		Message:   "branch removed",
+	})
+}
+
+func TestExplain_Dropped(t *testing.T) {
+	resetSingleton(t)
+
+	v := viper.New()
+	v.Set("explain", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("exclude", "main.go$")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("time", 1585598059.1)
+
+	out, err := cmdheartbeat.Explain(context.Background(), v)
+	require.NoError(t, err)
+
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 8)
+
+	assert.True(t, strings.HasSuffix(lines[0], "testdata/main.go"))
+	assert.Equal(t,
+		`status: dropped (filter by pattern: skipping because matches exclude pattern "(?i)main.go$")`,
+		lines[1],
+	)
+	assert.Equal(t, "decisions:", lines[2])
+	assert.Equal(t,
+		`  1. [filter] exclude (exclude) pattern "(?i)main.go$": skipped because matches exclude pattern`,
+		lines[3],
+	)
+	assert.Equal(t, "category: coding", lines[7])
+}
+
+func TestExplain_ExtraHeartbeatsNDJSON(t *testing.T) {
+	resetSingleton(t)
+	setStdin(t, strings.Join([]string{
+		`{"category": "coding", "entity": "testdata/main.py", "entity_type": "file", "project": "other", "time": 1585598060}`,
+		`{"category": "building", "entity": "testdata/main.go", "entity_type": "file", "project": "billing", "time": 1585598061}`,
+	}, "\n"))
+
+	v := viper.New()
+	v.Set("explain", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("extra-heartbeats", true)
+	v.Set("extra-heartbeats-format", "ndjson")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("output", "json")
+	v.Set("project", "wakatime-cli")
+	v.Set("time", 1585598059.1)
+
+	out, err := cmdheartbeat.Explain(context.Background(), v)
+	require.NoError(t, err)
+
+	var result struct {
+		Status    string `json:"status"`
+		Heartbeat struct {
+			Category string `json:"category"`
+			Project  string `json:"project"`
+		} `json:"heartbeat"`
+	}
+
+	err = json.Unmarshal([]byte(out), &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "send", result.Status)
+	assert.Equal(t, "building", result.Heartbeat.Category)
+	assert.Equal(t, "billing", result.Heartbeat.Project)
+}
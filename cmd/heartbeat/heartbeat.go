@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	_ "github.com/wakatime/wakatime-cli/pkg/lexer" // force to load all lexers
 	"github.com/wakatime/wakatime-cli/pkg/log"
 	"github.com/wakatime/wakatime-cli/pkg/offline"
+	"github.com/wakatime/wakatime-cli/pkg/plugin"
 	"github.com/wakatime/wakatime-cli/pkg/project"
 	"github.com/wakatime/wakatime-cli/pkg/remote"
 	"github.com/wakatime/wakatime-cli/pkg/wakaerror"
@@ -82,11 +84,17 @@ func SendHeartbeats(ctx context.Context, v *viper.Viper, queueFilepath string) e
 	setLogFields(ctx, params)
 	logger.Debugf("params: %s", params)
 
-	if RateLimited(RateLimitParams{
+	rateLimited := RateLimited(RateLimitParams{
 		Disabled:   params.Offline.Disabled,
 		LastSentAt: params.Offline.LastSentAt,
 		Timeout:    params.Offline.RateLimit,
-	}) {
+	})
+
+	if params.Heartbeat.ExtraHeartbeatsNDJSON {
+		return sendHeartbeatsStream(ctx, v, params, os.Stdin, os.Stdout, queueFilepath, rateLimited)
+	}
+
+	if rateLimited {
 		if err = offlinecmd.SaveHeartbeats(ctx, v, nil, queueFilepath); err == nil {
 			return nil
 		}
@@ -116,20 +124,7 @@ func SendHeartbeats(ctx context.Context, v *viper.Viper, queueFilepath string) e
 		heartbeats = heartbeats[:offline.SendLimit]
 	}
 
-	handleOpts := initHandleOptions(params)
-
-	if !params.Offline.Disabled {
-		handleOpts = append(handleOpts, offline.WithQueue(queueFilepath))
-	}
-
-	handleOpts = append(handleOpts, backoff.WithBackoff(backoff.Config{
-		V:        v,
-		At:       params.API.BackoffAt,
-		Retries:  params.API.BackoffRetries,
-		HasProxy: params.API.ProxyURL != "",
-	}))
-
-	apiClient, err := apicmd.NewClientWithoutAuth(ctx, params.API)
+	handle, err := newSendHandle(ctx, v, params, queueFilepath)
 	if err != nil {
 		if !params.Offline.Disabled {
 			if err := offlinecmd.SaveHeartbeats(ctx, v, heartbeats, queueFilepath); err != nil {
@@ -137,10 +132,9 @@ func SendHeartbeats(ctx context.Context, v *viper.Viper, queueFilepath string) e
 			}
 		}
 
-		return fmt.Errorf("failed to initialize api client: %w", err)
+		return err
 	}
 
-	handle := heartbeat.NewHandle(apiClient, handleOpts...)
 	results, err := handle(ctx, heartbeats)
 
 	// wait for offline queue save to finish
@@ -165,6 +159,37 @@ func SendHeartbeats(ctx context.Context, v *viper.Viper, queueFilepath string) e
 	return nil
 }
 
+// newSendHandle initializes the heartbeat handle sending heartbeats to the api,
+// which queues heartbeats offline if sending fails.
+func newSendHandle(
+	ctx context.Context,
+	v *viper.Viper,
+	params paramscmd.Params,
+	queueFilepath string,
+) (heartbeat.Handle, error) {
+	handleOpts := initHandleOptions(params)
+
+	if !params.Offline.Disabled {
+		handleOpts = append(handleOpts, offline.WithQueue(queueFilepath))
+	}
+
+	handleOpts = append(handleOpts, heartbeat.WithCounting(!params.Offline.Disabled))
+
+	handleOpts = append(handleOpts, backoff.WithBackoff(backoff.Config{
+		V:        v,
+		At:       params.API.BackoffAt,
+		Retries:  params.API.BackoffRetries,
+		HasProxy: params.API.ProxyURL != "",
+	}))
+
+	apiClient, err := apicmd.NewClientWithoutAuth(ctx, params.API)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize api client: %w", err)
+	}
+
+	return heartbeat.NewHandle(apiClient, handleOpts...), nil
+}
+
 // LoadParams loads params from viper.Viper instance. Returns ErrAuth
 // if failed to retrieve api key.
 func LoadParams(ctx context.Context, v *viper.Viper) (paramscmd.Params, error) {
@@ -239,6 +264,7 @@ func buildHeartbeats(ctx context.Context, params paramscmd.Params) []heartbeat.H
 	heartbeats = append(heartbeats, heartbeat.New(
 		params.Heartbeat.Project.BranchAlternate,
 		params.Heartbeat.Category,
+		params.Heartbeat.IsCategorySet,
 		params.Heartbeat.CursorPosition,
 		params.Heartbeat.Entity,
 		params.Heartbeat.EntityType,
@@ -264,63 +290,96 @@ func buildHeartbeats(ctx context.Context, params paramscmd.Params) []heartbeat.H
 		logger.Debugf("include %d extra heartbeat(s) from stdin", len(params.Heartbeat.ExtraHeartbeats))
 
 		for _, h := range params.Heartbeat.ExtraHeartbeats {
-			heartbeats = append(heartbeats, heartbeat.New(
-				h.BranchAlternate,
-				h.Category,
-				h.CursorPosition,
-				h.Entity,
-				h.EntityType,
-				h.IsUnsavedEntity,
-				h.IsWrite,
-				h.Language,
-				h.LanguageAlternate,
-				h.LineAdditions,
-				h.LineDeletions,
-				h.LineNumber,
-				h.Lines,
-				h.LocalFile,
-				h.ProjectAlternate,
-				h.ProjectFromGitRemote,
-				h.ProjectOverride,
-				h.ProjectPathOverride,
-				h.Time,
-				userAgent,
-			))
+			heartbeats = append(heartbeats, newExtraHeartbeat(h, userAgent))
 		}
 	}
 
 	return heartbeats
 }
 
+// newExtraHeartbeat creates a heartbeat from an extra heartbeat read from stdin.
+func newExtraHeartbeat(h heartbeat.Heartbeat, userAgent string) heartbeat.Heartbeat {
+	return heartbeat.New(
+		h.BranchAlternate,
+		h.Category,
+		h.IsCategorySet,
+		h.CursorPosition,
+		h.Entity,
+		h.EntityType,
+		h.IsUnsavedEntity,
+		h.IsWrite,
+		h.Language,
+		h.LanguageAlternate,
+		h.LineAdditions,
+		h.LineDeletions,
+		h.LineNumber,
+		h.Lines,
+		h.LocalFile,
+		h.ProjectAlternate,
+		h.ProjectFromGitRemote,
+		h.ProjectOverride,
+		h.ProjectPathOverride,
+		h.Time,
+		userAgent,
+	)
+}
+
 func initHandleOptions(params paramscmd.Params) []heartbeat.HandleOption {
 	return []heartbeat.HandleOption{
 		heartbeat.WithFormatting(),
 		heartbeat.WithEntityModifier(),
+		project.WithPathTranslation(params.Heartbeat.Project.PathTranslations),
+		project.WithFileSettings(),
 		filter.WithFiltering(filter.Config{
 			Exclude:                    params.Heartbeat.Filter.Exclude,
 			Include:                    params.Heartbeat.Filter.Include,
 			IncludeOnlyWithProjectFile: params.Heartbeat.Filter.IncludeOnlyWithProjectFile,
 		}),
+		language.WithAttributes(language.AttributesConfig{
+			Documentation: params.Heartbeat.Filter.LinguistDocumentation,
+			Generated:     params.Heartbeat.Filter.LinguistGenerated,
+			Vendored:      params.Heartbeat.Filter.LinguistVendored,
+		}),
 		remote.WithDetection(),
+		language.WithContentSniffing(language.SniffConfig{
+			Binary:    params.Heartbeat.Filter.ContentBinary,
+			Generated: params.Heartbeat.Filter.ContentGenerated,
+			Minified:  params.Heartbeat.Filter.ContentMinified,
+		}),
 		apikey.WithReplacing(apikey.Config{
-			DefaultAPIKey: params.API.Key,
-			MapPatterns:   params.API.KeyPatterns,
+			AllowProjectFile: params.API.AllowProjectFileKey,
+			DefaultAPIKey:    params.API.Key,
+			MapPatterns:      params.API.KeyPatterns,
 		}),
 		filestats.WithDetection(),
 		language.WithDetection(language.Config{
-			GuessLanguage: params.Heartbeat.GuessLanguage,
+			GuessLanguage:     params.Heartbeat.GuessLanguage,
+			MapPatterns:       params.Heartbeat.LanguageMapPatterns,
+			SecondaryLanguage: params.Heartbeat.SecondaryLanguage,
 		}),
 		deps.WithDetection(deps.Config{
 			FilePatterns: params.Heartbeat.Sanitize.HideFileNames,
 		}),
 		project.WithDetection(project.Config{
+			GitRemoteAliases:     params.Heartbeat.Project.GitRemoteAliases,
+			GitRemoteNames:       params.Heartbeat.Project.GitRemoteNames,
+			GitWorkTrees:         params.Heartbeat.Project.GitWorkTrees,
 			HideProjectNames:     params.Heartbeat.Sanitize.HideProjectNames,
+			Hostname:             params.API.Hostname,
 			MapPatterns:          params.Heartbeat.Project.MapPatterns,
+			MapRules:             params.Heartbeat.Project.MapRules,
+			PreferJujutsu:        params.Heartbeat.Project.PreferJujutsu,
 			ProjectFromGitRemote: params.Heartbeat.Project.ProjectFromGitRemote,
 			Submodule: project.Submodule{
 				DisabledPatterns: params.Heartbeat.Project.SubmodulesDisabled,
 				MapPatterns:      params.Heartbeat.Project.SubmoduleMapPatterns,
 			},
+			WorkspaceDetection:     params.Heartbeat.Project.WorkspaceDetection,
+			WorkspaceProjectFormat: params.Heartbeat.Project.WorkspaceFormat,
+		}),
+		plugin.WithEnrichment(plugin.Config{
+			Plugins: params.Heartbeat.Plugin.Plugins,
+			Timeout: params.Heartbeat.Plugin.Timeout,
 		}),
 		project.WithFiltering(project.FilterConfig{
 			ExcludeUnknownProject: params.Heartbeat.Filter.ExcludeUnknownProject,
@@ -196,6 +196,64 @@ func TestSendHeartbeats_RateLimited(t *testing.T) {
 	assert.Zero(t, numCalls)
 }
 
+func TestSendHeartbeats_RateLimited_Plugins(t *testing.T) {
+	resetSingleton(t)
+
+	testServerURL, router, tearDown := setupTestServer()
+	defer tearDown()
+
+	var numCalls int
+
+	router.HandleFunc("/users/current/heartbeats.bulk", func(_ http.ResponseWriter, _ *http.Request) {
+		// Should not be called
+		numCalls++
+	})
+
+	tmpFile, err := os.CreateTemp(t.TempDir(), "wakatime-config")
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	tmpFileInternal, err := os.CreateTemp(t.TempDir(), "wakatime-internal-config")
+	require.NoError(t, err)
+
+	defer tmpFileInternal.Close()
+
+	offlineQueueFile, err := os.CreateTemp(t.TempDir(), "offline-queue-file")
+	require.NoError(t, err)
+
+	defer offlineQueueFile.Close()
+
+	v := viper.New()
+	v.SetDefault("sync-offline-activity", 1000)
+	v.Set("api-url", testServerURL)
+	v.Set("category", "debugging")
+	v.Set("entity", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("project", "wakatime-cli")
+	v.Set("plugins.ticket", "testdata/plugin_ticket.sh")
+	v.Set("time", 1585598059.1)
+	v.Set("timeout", 5)
+	v.Set("heartbeat-rate-limit-seconds", 500)
+	v.Set("config", tmpFile.Name())
+	v.Set("internal-config", tmpFileInternal.Name())
+	v.Set("offline-queue-file", offlineQueueFile.Name())
+	v.Set("internal.heartbeats_last_sent_at", time.Now().Add(-time.Minute).Format(time.RFC3339))
+
+	err = cmdheartbeat.SendHeartbeats(context.Background(), v, offlineQueueFile.Name())
+	require.NoError(t, err)
+
+	assert.Zero(t, numCalls)
+
+	queued, err := offline.ReadHeartbeats(context.Background(), offlineQueueFile.Name(), 10)
+	require.NoError(t, err)
+
+	require.Len(t, queued, 1)
+	assert.Equal(t, "JIRA-123", *queued[0].Branch)
+	assert.Equal(t, "billing", *queued[0].Project)
+}
+
 func TestSendHeartbeats_WithFiltering_Exclude(t *testing.T) {
 	resetSingleton(t)
 
@@ -598,6 +656,65 @@ func TestSendHeartbeats_NonExistingEntity(t *testing.T) {
 	assert.Contains(t, string(output), "skipping because of non-existing file")
 }
 
+func TestSendHeartbeats_PathTranslation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping because of unix container paths")
+	}
+
+	resetSingleton(t)
+
+	testServerURL, router, tearDown := setupTestServer()
+	defer tearDown()
+
+	hostFolder, err := filepath.Abs("testdata")
+	require.NoError(t, err)
+
+	var numCalls int
+
+	router.HandleFunc("/users/current/heartbeats.bulk", func(w http.ResponseWriter, req *http.Request) {
+		numCalls++
+
+		var entity struct {
+			Entity string `json:"entity"`
+		}
+
+		err := json.NewDecoder(req.Body).Decode(&[]any{&entity})
+		require.NoError(t, err)
+
+		assert.Equal(t, filepath.Join(hostFolder, "main.go"), entity.Entity)
+
+		w.WriteHeader(http.StatusCreated)
+
+		f, err := os.Open("testdata/api_heartbeats_response.json")
+		require.NoError(t, err)
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		require.NoError(t, err)
+	})
+
+	v := viper.New()
+	v.SetDefault("sync-offline-activity", 1000)
+	v.Set("api-url", testServerURL)
+	v.Set("entity", "/workspaces/wakatime-cli/main.go")
+	v.Set("entity-type", "file")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("path_translations./workspaces/wakatime-cli", hostFolder)
+	v.Set("plugin", "plugin")
+	v.Set("time", 1585598059.1)
+	v.Set("timeout", 5)
+
+	offlineQueueFile, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+
+	defer offlineQueueFile.Close()
+
+	err = cmdheartbeat.SendHeartbeats(context.Background(), v, offlineQueueFile.Name())
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return numCalls == 1 }, time.Second, 50*time.Millisecond)
+}
+
 func TestSendHeartbeats_ExtraHeartbeatsIsUnsavedEntity(t *testing.T) {
 	resetSingleton(t)
 
@@ -857,6 +974,236 @@ func TestSendHeartbeats_NonExistingExtraHeartbeatsEntity(t *testing.T) {
 	assert.Contains(t, string(output), "skipping because of non-existing file")
 }
 
+func TestSendHeartbeats_ExtraHeartbeatsNDJSON(t *testing.T) {
+	resetSingleton(t)
+
+	testServerURL, router, tearDown := setupTestServer()
+	defer tearDown()
+
+	var chunkSizes []int
+
+	ctx := context.Background()
+
+	router.HandleFunc("/users/current/heartbeats.bulk", func(w http.ResponseWriter, req *http.Request) {
+		var heartbeats []any
+
+		err := json.NewDecoder(req.Body).Decode(&heartbeats)
+		require.NoError(t, err)
+
+		chunkSizes = append(chunkSizes, len(heartbeats))
+
+		responses := make([]string, len(heartbeats))
+		for i := range responses {
+			responses[i] = `[{"data": {}}, 201]`
+		}
+
+		// send response
+		w.WriteHeader(http.StatusCreated)
+
+		_, err = fmt.Fprintf(w, `{"responses": [%s]}`, strings.Join(responses, ","))
+		require.NoError(t, err)
+	})
+
+	inr, inw, err := os.Pipe()
+	require.NoError(t, err)
+
+	defer func() {
+		inr.Close()
+		inw.Close()
+	}()
+
+	outr, outw, err := os.Pipe()
+	require.NoError(t, err)
+
+	defer func() {
+		outr.Close()
+		outw.Close()
+	}()
+
+	origStdin, origStdout := os.Stdin, os.Stdout
+
+	defer func() {
+		os.Stdin = origStdin
+		os.Stdout = origStdout
+	}()
+
+	os.Stdin = inr
+	os.Stdout = outw
+
+	data, err := os.ReadFile("testdata/extra_heartbeats.ndjson")
+	require.NoError(t, err)
+
+	go func() {
+		_, err := inw.Write(data)
+		require.NoError(t, err)
+
+		inw.Close()
+	}()
+
+	tmpDir := t.TempDir()
+
+	logFile, err := os.CreateTemp(tmpDir, "")
+	require.NoError(t, err)
+
+	defer logFile.Close()
+
+	v := viper.New()
+	v.SetDefault("sync-offline-activity", 0)
+	v.Set("api-url", testServerURL)
+	v.Set("entity", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("extra-heartbeats", true)
+	v.Set("extra-heartbeats-format", "ndjson")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("log-file", logFile.Name())
+	v.Set("project", "wakatime-cli")
+	v.Set("time", 1585598059.1)
+
+	logger, err := cmd.SetupLogging(ctx, v)
+	require.NoError(t, err)
+
+	defer logger.Flush()
+
+	ctx = log.ToContext(ctx, logger)
+
+	offlineQueueFile, err := os.CreateTemp(tmpDir, "")
+	require.NoError(t, err)
+
+	defer offlineQueueFile.Close()
+
+	err = cmdheartbeat.SendHeartbeats(ctx, v, offlineQueueFile.Name())
+	require.NoError(t, err)
+
+	outw.Close()
+
+	output, err := io.ReadAll(outr)
+	require.NoError(t, err)
+
+	// main heartbeat and 28 valid extra heartbeats
+	assert.Equal(t, []int{25, 4}, chunkSizes)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, lines, 2)
+
+	assert.JSONEq(t,
+		`{"line": 10, "error": "failed to convert time to float64: strconv.ParseFloat: parsing \"invalid\": invalid syntax"}`,
+		lines[0],
+	)
+	assert.Contains(t, lines[1], `"line":20`)
+	assert.Contains(t, lines[1], "failed to json decode")
+
+	offlineCount, err := offline.CountHeartbeats(ctx, offlineQueueFile.Name())
+	require.NoError(t, err)
+
+	assert.Zero(t, offlineCount)
+
+	logs, err := io.ReadAll(logFile)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(logs), "rejected 2 extra heartbeat(s)")
+}
+
+func TestSendHeartbeats_ExtraHeartbeatsNDJSON_OfflineDisabled(t *testing.T) {
+	resetSingleton(t)
+
+	testServerURL, router, tearDown := setupTestServer()
+	defer tearDown()
+
+	var chunkSizes []int
+
+	ctx := context.Background()
+
+	router.HandleFunc("/users/current/heartbeats.bulk", func(w http.ResponseWriter, req *http.Request) {
+		var heartbeats []any
+
+		err := json.NewDecoder(req.Body).Decode(&heartbeats)
+		require.NoError(t, err)
+
+		chunkSizes = append(chunkSizes, len(heartbeats))
+
+		// first chunk fails
+		if len(chunkSizes) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		responses := make([]string, len(heartbeats))
+		for i := range responses {
+			responses[i] = `[{"data": {}}, 201]`
+		}
+
+		w.WriteHeader(http.StatusCreated)
+
+		_, err = fmt.Fprintf(w, `{"responses": [%s]}`, strings.Join(responses, ","))
+		require.NoError(t, err)
+	})
+
+	data, err := os.ReadFile("testdata/extra_heartbeats.ndjson")
+	require.NoError(t, err)
+
+	setStdin(t, string(data))
+
+	outr, outw, err := os.Pipe()
+	require.NoError(t, err)
+
+	defer func() {
+		outr.Close()
+		outw.Close()
+	}()
+
+	origStdout := os.Stdout
+
+	defer func() {
+		os.Stdout = origStdout
+	}()
+
+	os.Stdout = outw
+
+	tmpDir := t.TempDir()
+
+	logFile, err := os.CreateTemp(tmpDir, "")
+	require.NoError(t, err)
+
+	defer logFile.Close()
+
+	tmpFileInternal, err := os.CreateTemp(tmpDir, "wakatime-internal-config")
+	require.NoError(t, err)
+
+	defer tmpFileInternal.Close()
+
+	v := viper.New()
+	v.SetDefault("sync-offline-activity", 0)
+	v.Set("api-url", testServerURL)
+	v.Set("disable-offline", true)
+	v.Set("entity", "testdata/main.go")
+	v.Set("entity-type", "file")
+	v.Set("extra-heartbeats", true)
+	v.Set("extra-heartbeats-format", "ndjson")
+	v.Set("internal-config", tmpFileInternal.Name())
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("log-file", logFile.Name())
+	v.Set("project", "wakatime-cli")
+	v.Set("time", 1585598059.1)
+
+	logger, err := cmd.SetupLogging(ctx, v)
+	require.NoError(t, err)
+
+	defer logger.Flush()
+
+	ctx = log.ToContext(ctx, logger)
+
+	err = cmdheartbeat.SendHeartbeats(ctx, v, "")
+	require.Error(t, err)
+
+	// second chunk is sent although the first one failed
+	assert.Equal(t, []int{25, 4}, chunkSizes)
+
+	logs, err := io.ReadAll(logFile)
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(logs), "saving to offline db disabled")
+}
+
 func TestSendHeartbeats_ErrAuth_UnsetAPIKey(t *testing.T) {
 	resetSingleton(t)
 
@@ -0,0 +1,173 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	offlinecmd "github.com/wakatime/wakatime-cli/cmd/offline"
+	paramscmd "github.com/wakatime/wakatime-cli/cmd/params"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/offline"
+
+	"github.com/spf13/viper"
+)
+
+// sendHeartbeatsStream sends the main heartbeat together with extra heartbeats
+// read from in as newline delimited json. Extra heartbeats are processed in
+// chunks of `offline.SendLimit` while reading. Rejected lines are reported to out
+// as newline delimited json. If rate limited, or once sending failed, remaining
+// heartbeats are saved to the offline queue. With the offline queue disabled,
+// remaining heartbeats are still sent after a failed chunk.
+func sendHeartbeatsStream(
+	ctx context.Context,
+	v *viper.Viper,
+	params paramscmd.Params,
+	in io.Reader,
+	out io.Writer,
+	queueFilepath string,
+	rateLimited bool,
+) error {
+	logger := log.Extract(ctx)
+
+	var (
+		handle  heartbeat.Handle
+		sendErr error
+	)
+
+	if !rateLimited {
+		var err error
+
+		handle, err = newSendHandle(ctx, v, params, queueFilepath)
+		if err != nil {
+			if params.Offline.Disabled {
+				// heartbeats can neither be sent nor queued
+				return err
+			}
+
+			sendErr = err
+		}
+	}
+
+	userAgent := heartbeat.UserAgent(ctx, params.API.Plugin)
+	reader := paramscmd.NewExtraHeartbeatsReader(in)
+
+	// first chunk contains the main heartbeat
+	heartbeats := buildHeartbeats(ctx, params)
+
+	var numRejected int
+
+	for {
+		extraHeartbeats, rejected, readErr := reader.Read(offline.SendLimit - len(heartbeats))
+
+		for _, r := range rejected {
+			data, err := json.Marshal(r)
+			if err != nil {
+				logger.Errorf("failed to json encode rejected extra heartbeat: %s", err)
+				continue
+			}
+
+			fmt.Fprintln(out, string(data))
+		}
+
+		numRejected += len(rejected)
+
+		for _, h := range extraHeartbeats {
+			heartbeats = append(heartbeats, newExtraHeartbeat(h, userAgent))
+		}
+
+		if len(heartbeats) > 0 {
+			if handle == nil {
+				if err := offlinecmd.SaveHeartbeats(ctx, v, heartbeats, queueFilepath); err != nil {
+					logger.Errorf("failed to save heartbeats to offline queue: %s", err)
+				}
+			} else if err := sendChunk(ctx, handle, heartbeats); err != nil {
+				sendErr = err
+
+				// failed chunk was queued offline by handle, queue the rest without trying to send
+				if !params.Offline.Disabled {
+					handle = nil
+				}
+			}
+		}
+
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) {
+				logger.Errorf("failed to read extra heartbeats: %s", readErr)
+			}
+
+			break
+		}
+
+		heartbeats = nil
+	}
+
+	if numRejected > 0 {
+		logger.Warnf("rejected %d extra heartbeat(s)", numRejected)
+	}
+
+	if sendErr != nil {
+		return sendErr
+	}
+
+	if rateLimited {
+		return nil
+	}
+
+	if err := ResetRateLimit(ctx, v); err != nil {
+		logger.Errorf("failed to reset rate limit: %s", err)
+	}
+
+	return nil
+}
+
+// sendChunk sends a single chunk of heartbeats and logs errors returned by the api.
+func sendChunk(ctx context.Context, handle heartbeat.Handle, heartbeats []heartbeat.Heartbeat) error {
+	logger := log.Extract(ctx)
+
+	results, err := handle(ctx, heartbeats)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if len(result.Errors) > 0 {
+			logger.Warnln(strings.Join(result.Errors, " "))
+		}
+	}
+
+	return nil
+}
+
+// readExtraHeartbeatsStream reads all extra heartbeats from in as newline
+// delimited json. Rejected lines are returned along with them.
+func readExtraHeartbeatsStream(
+	ctx context.Context,
+	in io.Reader,
+) ([]heartbeat.Heartbeat, []paramscmd.ExtraHeartbeatError) {
+	reader := paramscmd.NewExtraHeartbeatsReader(in)
+
+	var (
+		heartbeats []heartbeat.Heartbeat
+		rejected   []paramscmd.ExtraHeartbeatError
+	)
+
+	for {
+		extraHeartbeats, rejectedChunk, err := reader.Read(offline.SendLimit)
+
+		heartbeats = append(heartbeats, extraHeartbeats...)
+		rejected = append(rejected, rejectedChunk...)
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Extract(ctx).Errorf("failed to read extra heartbeats: %s", err)
+			}
+
+			return heartbeats, rejected
+		}
+	}
+}
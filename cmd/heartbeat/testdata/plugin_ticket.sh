@@ -0,0 +1,3 @@
+#!/bin/sh
+cat > /dev/null
+echo '[{"branch":"JIRA-123","project":"billing"}]'
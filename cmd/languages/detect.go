@@ -0,0 +1,138 @@
+package languages
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/exitcode"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/language"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/output"
+
+	"github.com/spf13/viper"
+)
+
+// detection is the json representation of the detect-language command output.
+type detection struct {
+	Entity     string      `json:"entity"`
+	Language   *string     `json:"language"`
+	Candidates []candidate `json:"candidates"`
+}
+
+// candidate is the json representation of a chroma lexer considered during
+// language detection.
+type candidate struct {
+	Lexer    string  `json:"lexer"`
+	Language string  `json:"language"`
+	Weight   float32 `json:"weight"`
+	Priority float32 `json:"priority"`
+}
+
+// RunDetect executes the detect-language command, which prints the detected
+// language of the file passed in via --detect-language along with the chroma
+// lexers considered.
+func RunDetect(ctx context.Context, v *viper.Viper) (int, error) {
+	out, err := Detect(ctx, v)
+	if err != nil {
+		return exitcode.ErrGeneric, fmt.Errorf("detect language failed: %w", err)
+	}
+
+	fmt.Println(out)
+
+	return exitcode.Success, nil
+}
+
+// Detect detects the language of a file the same way as for heartbeats, including
+// the plugin's language, [languages] map patterns and linguist-language
+// attributes, and renders it along with the chroma lexers matching its filename,
+// their weights and priorities. The first candidate is the one picked by
+// filename matching.
+func Detect(ctx context.Context, v *viper.Viper) (string, error) {
+	params, err := LoadParams(ctx, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to load command parameters: %w", err)
+	}
+
+	if params.DetectFilepath == "" {
+		return "", errors.New("file path cannot be empty")
+	}
+
+	if _, err := os.Stat(params.DetectFilepath); err != nil {
+		return "", fmt.Errorf("failed to access file %q: %s", params.DetectFilepath, err)
+	}
+
+	result := detection{
+		Entity:     params.DetectFilepath,
+		Candidates: []candidate{},
+	}
+
+	lang, err := language.Resolve(ctx, heartbeat.Heartbeat{
+		Entity:            params.DetectFilepath,
+		EntityType:        heartbeat.FileType,
+		Language:          params.Language,
+		LanguageAlternate: params.LanguageAlternate,
+	}, language.Config{
+		GuessLanguage: params.GuessLanguage,
+		MapPatterns:   params.MapPatterns,
+	})
+	if err != nil {
+		log.Extract(ctx).Debugf("failed to detect language: %s", err)
+	} else {
+		result.Language = &lang
+	}
+
+	for _, c := range language.Candidates(ctx, params.DetectFilepath) {
+		result.Candidates = append(result.Candidates, candidate{
+			Lexer:    c.Lexer,
+			Language: c.Language.String(),
+			Weight:   c.Weight,
+			Priority: c.Priority,
+		})
+	}
+
+	return renderDetection(result, params.Output)
+}
+
+func renderDetection(d detection, out output.Output) (string, error) {
+	if out == output.JSONOutput || out == output.RawJSONOutput {
+		data, err := json.Marshal(d)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal json detection: %s", err)
+		}
+
+		return string(data), nil
+	}
+
+	lang := "-"
+	if d.Language != nil {
+		lang = *d.Language
+	}
+
+	lines := []string{
+		fmt.Sprintf("entity: %s", d.Entity),
+		fmt.Sprintf("language: %s", lang),
+		"candidates:",
+	}
+
+	if len(d.Candidates) == 0 {
+		lines = append(lines, "  none")
+	}
+
+	for i, c := range d.Candidates {
+		lines = append(lines, fmt.Sprintf(
+			"  %d. %s (language: %s, weight: %.2f, priority: %.2f)",
+			i+1,
+			c.Lexer,
+			c.Language,
+			c.Weight,
+			c.Priority,
+		))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,158 @@
+package languages_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/cmd/languages"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	v := viper.New()
+	v.Set("detect-language", "testdata/main.pl")
+
+	out, err := languages.Detect(context.Background(), v)
+	require.NoError(t, err)
+
+	assert.Equal(t, "entity: testdata/main.pl\n"+
+		"language: Perl\n"+
+		"candidates:\n"+
+		"  1. Perl (language: Perl, weight: 0.90, priority: 0.01)\n"+
+		"  2. Perl6 (language: Perl6, weight: 0.80, priority: 0.00)\n"+
+		"  3. Raku (language: Raku, weight: 0.00, priority: 0.00)\n"+
+		"  4. Prolog (language: Prolog, weight: 0.00, priority: 0.00)", out)
+}
+
+func TestDetect_JSON(t *testing.T) {
+	v := viper.New()
+	v.Set("detect-language", "testdata/main.pl")
+	v.Set("output", "json")
+
+	out, err := languages.Detect(context.Background(), v)
+	require.NoError(t, err)
+
+	var result struct {
+		Entity     string  `json:"entity"`
+		Language   *string `json:"language"`
+		Candidates []struct {
+			Lexer    string  `json:"lexer"`
+			Language string  `json:"language"`
+			Weight   float32 `json:"weight"`
+			Priority float32 `json:"priority"`
+		} `json:"candidates"`
+	}
+
+	err = json.Unmarshal([]byte(out), &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "testdata/main.pl", result.Entity)
+	require.NotNil(t, result.Language)
+	assert.Equal(t, "Perl", *result.Language)
+	require.Len(t, result.Candidates, 4)
+	assert.Equal(t, "Perl", result.Candidates[0].Lexer)
+	assert.Equal(t, float32(0.9), result.Candidates[0].Weight)
+	assert.Equal(t, float32(0.01), result.Candidates[0].Priority)
+}
+
+func TestDetect_Resolution(t *testing.T) {
+	tests := map[string]struct {
+		Language          string
+		LanguageAlternate string
+		MapPattern        string
+		Attributes        string
+		Filename          string
+		Expected          string
+	}{
+		"plugin language": {
+			Language:   "Raku",
+			MapPattern: "PHP",
+			Filename:   "main.pl",
+			Expected:   "Raku",
+		},
+		"map pattern": {
+			MapPattern: "PHP",
+			Attributes: "*.pl linguist-language=Prolog\n",
+			Filename:   "main.pl",
+			Expected:   "PHP",
+		},
+		"linguist-language": {
+			Attributes: "*.pl linguist-language=Prolog\n",
+			Filename:   "main.pl",
+			Expected:   "Prolog",
+		},
+		"alternate language": {
+			LanguageAlternate: "Golang",
+			Filename:          "unknown.xyz",
+			Expected:          "Golang",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			err := os.Mkdir(filepath.Join(tmpDir, ".git"), 0700)
+			require.NoError(t, err)
+
+			if test.Attributes != "" {
+				err = os.WriteFile(filepath.Join(tmpDir, ".gitattributes"), []byte(test.Attributes), 0600)
+				require.NoError(t, err)
+			}
+
+			fp := filepath.Join(tmpDir, test.Filename)
+
+			err = os.WriteFile(fp, []byte("print 1;\n"), 0600)
+			require.NoError(t, err)
+
+			v := viper.New()
+			v.Set("detect-language", fp)
+			v.Set("output", "json")
+			v.Set("language", test.Language)
+			v.Set("alternate-language", test.LanguageAlternate)
+
+			if test.MapPattern != "" {
+				v.Set("languages.*.pl", test.MapPattern)
+			}
+
+			out, err := languages.Detect(context.Background(), v)
+			require.NoError(t, err)
+
+			var result struct {
+				Language *string `json:"language"`
+			}
+
+			err = json.Unmarshal([]byte(out), &result)
+			require.NoError(t, err)
+
+			require.NotNil(t, result.Language)
+			assert.Equal(t, test.Expected, *result.Language)
+		})
+	}
+}
+
+func TestDetect_Undetected(t *testing.T) {
+	v := viper.New()
+	v.Set("detect-language", "testdata/unknown.xyz")
+
+	out, err := languages.Detect(context.Background(), v)
+	require.NoError(t, err)
+
+	assert.Equal(t, "entity: testdata/unknown.xyz\nlanguage: -\ncandidates:\n  none", out)
+}
+
+func TestDetect_NonExistingFile(t *testing.T) {
+	v := viper.New()
+	v.Set("detect-language", "testdata/nonexisting.pl")
+
+	_, err := languages.Detect(context.Background(), v)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), `failed to access file "testdata/nonexisting.pl"`)
+}
@@ -0,0 +1,142 @@
+package languages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	paramscmd "github.com/wakatime/wakatime-cli/cmd/params"
+	"github.com/wakatime/wakatime-cli/pkg/exitcode"
+	"github.com/wakatime/wakatime-cli/pkg/language"
+	"github.com/wakatime/wakatime-cli/pkg/output"
+	"github.com/wakatime/wakatime-cli/pkg/vipertools"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+// Params contains languages command parameters.
+type Params struct {
+	// DetectFilepath is the file whose language is detected.
+	DetectFilepath string
+	GuessLanguage  bool
+	// Language is the language sent by the plugin, which takes precedence.
+	Language          *string
+	LanguageAlternate string
+	MapPatterns       []language.MapPattern
+	Output            output.Output
+}
+
+// languageInfo is the json representation of a language of the list-languages
+// command output.
+type languageInfo struct {
+	Name   string   `json:"name"`
+	Chroma []string `json:"chroma"`
+	Vim    []string `json:"vim"`
+}
+
+// RunList executes the list-languages command, which prints all languages
+// known to language detection.
+func RunList(ctx context.Context, v *viper.Viper) (int, error) {
+	out, err := List(ctx, v)
+	if err != nil {
+		return exitcode.ErrGeneric, fmt.Errorf("list languages failed: %w", err)
+	}
+
+	fmt.Println(out)
+
+	return exitcode.Success, nil
+}
+
+// List returns the rendered list of all languages with the names of the
+// chroma lexers and vim file types detected as them.
+func List(ctx context.Context, v *viper.Viper) (string, error) {
+	params, err := LoadParams(ctx, v)
+	if err != nil {
+		return "", fmt.Errorf("failed to load command parameters: %w", err)
+	}
+
+	var infos []languageInfo
+
+	for _, info := range language.List(ctx) {
+		infos = append(infos, languageInfo{
+			Name:   info.Name,
+			Chroma: emptyIfNil(info.Chroma),
+			Vim:    emptyIfNil(info.Vim),
+		})
+	}
+
+	if params.Output == output.JSONOutput || params.Output == output.RawJSONOutput {
+		data, err := json.Marshal(infos)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal json languages: %s", err)
+		}
+
+		return string(data), nil
+	}
+
+	lines := make([]string, 0, len(infos))
+
+	for _, info := range infos {
+		var aliases []string
+
+		if len(info.Chroma) > 0 {
+			aliases = append(aliases, "chroma: "+strings.Join(info.Chroma, ", "))
+		}
+
+		if len(info.Vim) > 0 {
+			aliases = append(aliases, "vim: "+strings.Join(info.Vim, ", "))
+		}
+
+		if len(aliases) == 0 {
+			lines = append(lines, info.Name)
+			continue
+		}
+
+		lines = append(lines, fmt.Sprintf("%s (%s)", info.Name, strings.Join(aliases, "; ")))
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// LoadParams loads languages command params from viper.Viper instance.
+func LoadParams(ctx context.Context, v *viper.Viper) (Params, error) {
+	fp, err := homedir.Expand(strings.TrimSpace(vipertools.GetString(v, "detect-language")))
+	if err != nil {
+		return Params{}, fmt.Errorf("failed expanding detect-language: %s", err)
+	}
+
+	var out output.Output
+
+	if outputStr := vipertools.GetString(v, "output"); outputStr != "" {
+		parsed, err := output.Parse(outputStr)
+		if err != nil {
+			return Params{}, fmt.Errorf("failed to parse output: %s", err)
+		}
+
+		out = parsed
+	}
+
+	var lang *string
+	if l := vipertools.GetString(v, "language"); l != "" {
+		lang = &l
+	}
+
+	return Params{
+		DetectFilepath:    fp,
+		GuessLanguage:     vipertools.FirstNonEmptyBool(v, "guess-language", "settings.guess_language"),
+		Language:          lang,
+		LanguageAlternate: vipertools.GetString(v, "alternate-language"),
+		MapPatterns:       paramscmd.LoadLanguageMapPatterns(ctx, v),
+		Output:            out,
+	}, nil
+}
+
+func emptyIfNil(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+
+	return ss
+}
@@ -0,0 +1,82 @@
+package languages_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/cmd/languages"
+	"github.com/wakatime/wakatime-cli/pkg/output"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	v := viper.New()
+	v.Set("list-languages", true)
+
+	out, err := languages.List(context.Background(), v)
+	require.NoError(t, err)
+
+	lines := strings.Split(out, "\n")
+
+	assert.Contains(t, lines, "Go (chroma: Go, Go HTML Template, Go Template, Go Text Template)")
+	assert.Contains(t, lines, "VB.NET (chroma: VB.net; vim: vb)")
+}
+
+func TestList_JSON(t *testing.T) {
+	v := viper.New()
+	v.Set("list-languages", true)
+	v.Set("output", "json")
+
+	out, err := languages.List(context.Background(), v)
+	require.NoError(t, err)
+
+	var infos []struct {
+		Name   string   `json:"name"`
+		Chroma []string `json:"chroma"`
+		Vim    []string `json:"vim"`
+	}
+
+	err = json.Unmarshal([]byte(out), &infos)
+	require.NoError(t, err)
+
+	assert.Contains(t, infos, struct {
+		Name   string   `json:"name"`
+		Chroma []string `json:"chroma"`
+		Vim    []string `json:"vim"`
+	}{
+		Name:   "C#",
+		Chroma: []string{"C#"},
+		Vim:    []string{"cs"},
+	})
+}
+
+func TestLoadParams(t *testing.T) {
+	v := viper.New()
+	v.Set("detect-language", " testdata/main.pl ")
+	v.Set("output", "raw-json")
+	v.Set("settings.guess_language", true)
+
+	params, err := languages.LoadParams(context.Background(), v)
+	require.NoError(t, err)
+
+	assert.Equal(t, languages.Params{
+		DetectFilepath: "testdata/main.pl",
+		GuessLanguage:  true,
+		Output:         output.RawJSONOutput,
+	}, params)
+}
+
+func TestLoadParams_InvalidOutput(t *testing.T) {
+	v := viper.New()
+	v.Set("output", "xml")
+
+	_, err := languages.LoadParams(context.Background(), v)
+	require.Error(t, err)
+
+	assert.EqualError(t, err, `failed to parse output: invalid output "xml"`)
+}
@@ -0,0 +1,3 @@
+use strict;
+my $name = "world";
+print "hello $name\n";
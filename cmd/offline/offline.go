@@ -13,6 +13,7 @@ import (
 	"github.com/wakatime/wakatime-cli/pkg/language"
 	"github.com/wakatime/wakatime-cli/pkg/log"
 	"github.com/wakatime/wakatime-cli/pkg/offline"
+	"github.com/wakatime/wakatime-cli/pkg/plugin"
 	"github.com/wakatime/wakatime-cli/pkg/project"
 	"github.com/wakatime/wakatime-cli/pkg/remote"
 
@@ -45,7 +46,7 @@ func SaveHeartbeats(ctx context.Context, v *viper.Viper, heartbeats []heartbeat.
 
 	handleOpts := initHandleOptions(params)
 
-	handleOpts = append(handleOpts, offline.WithQueue(queueFilepath))
+	handleOpts = append(handleOpts, offline.WithQueue(queueFilepath), heartbeat.WithCounting(true))
 
 	sender := offline.Noop{}
 	handle := heartbeat.NewHandle(sender, handleOpts...)
@@ -83,6 +84,7 @@ func buildHeartbeats(ctx context.Context, params paramscmd.Params) []heartbeat.H
 	heartbeats = append(heartbeats, heartbeat.New(
 		params.Heartbeat.Project.BranchAlternate,
 		params.Heartbeat.Category,
+		params.Heartbeat.IsCategorySet,
 		params.Heartbeat.CursorPosition,
 		params.Heartbeat.Entity,
 		params.Heartbeat.EntityType,
@@ -111,6 +113,7 @@ func buildHeartbeats(ctx context.Context, params paramscmd.Params) []heartbeat.H
 			heartbeats = append(heartbeats, heartbeat.New(
 				h.BranchAlternate,
 				h.Category,
+				h.IsCategorySet,
 				h.CursorPosition,
 				h.Entity,
 				h.EntityType,
@@ -140,27 +143,53 @@ func initHandleOptions(params paramscmd.Params) []heartbeat.HandleOption {
 	return []heartbeat.HandleOption{
 		heartbeat.WithFormatting(),
 		heartbeat.WithEntityModifier(),
+		project.WithPathTranslation(params.Heartbeat.Project.PathTranslations),
+		project.WithFileSettings(),
 		filter.WithFiltering(filter.Config{
 			Exclude:                    params.Heartbeat.Filter.Exclude,
 			Include:                    params.Heartbeat.Filter.Include,
 			IncludeOnlyWithProjectFile: params.Heartbeat.Filter.IncludeOnlyWithProjectFile,
 		}),
+		language.WithAttributes(language.AttributesConfig{
+			Documentation: params.Heartbeat.Filter.LinguistDocumentation,
+			Generated:     params.Heartbeat.Filter.LinguistGenerated,
+			Vendored:      params.Heartbeat.Filter.LinguistVendored,
+		}),
 		remote.WithDetection(),
+		language.WithContentSniffing(language.SniffConfig{
+			Binary:    params.Heartbeat.Filter.ContentBinary,
+			Generated: params.Heartbeat.Filter.ContentGenerated,
+			Minified:  params.Heartbeat.Filter.ContentMinified,
+		}),
 		filestats.WithDetection(),
 		language.WithDetection(language.Config{
-			GuessLanguage: params.Heartbeat.GuessLanguage,
+			GuessLanguage:     params.Heartbeat.GuessLanguage,
+			MapPatterns:       params.Heartbeat.LanguageMapPatterns,
+			SecondaryLanguage: params.Heartbeat.SecondaryLanguage,
 		}),
 		deps.WithDetection(deps.Config{
 			FilePatterns: params.Heartbeat.Sanitize.HideFileNames,
 		}),
 		project.WithDetection(project.Config{
+			GitRemoteAliases:     params.Heartbeat.Project.GitRemoteAliases,
+			GitRemoteNames:       params.Heartbeat.Project.GitRemoteNames,
+			GitWorkTrees:         params.Heartbeat.Project.GitWorkTrees,
 			HideProjectNames:     params.Heartbeat.Sanitize.HideProjectNames,
+			Hostname:             params.API.Hostname,
 			MapPatterns:          params.Heartbeat.Project.MapPatterns,
+			MapRules:             params.Heartbeat.Project.MapRules,
+			PreferJujutsu:        params.Heartbeat.Project.PreferJujutsu,
 			ProjectFromGitRemote: params.Heartbeat.Project.ProjectFromGitRemote,
 			Submodule: project.Submodule{
 				DisabledPatterns: params.Heartbeat.Project.SubmodulesDisabled,
 				MapPatterns:      params.Heartbeat.Project.SubmoduleMapPatterns,
 			},
+			WorkspaceDetection:     params.Heartbeat.Project.WorkspaceDetection,
+			WorkspaceProjectFormat: params.Heartbeat.Project.WorkspaceFormat,
+		}),
+		plugin.WithEnrichment(plugin.Config{
+			Plugins: params.Heartbeat.Plugin.Plugins,
+			Timeout: params.Heartbeat.Plugin.Timeout,
 		}),
 		project.WithFiltering(project.FilterConfig{
 			ExcludeUnknownProject: params.Heartbeat.Filter.ExcludeUnknownProject,
@@ -13,6 +13,7 @@ import (
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/log"
 	"github.com/wakatime/wakatime-cli/pkg/offline"
+	"github.com/wakatime/wakatime-cli/pkg/project"
 	"github.com/wakatime/wakatime-cli/pkg/wakaerror"
 
 	"github.com/spf13/viper"
@@ -107,9 +108,11 @@ func syncOfflineActivityLegacy(ctx context.Context, v *viper.Viper, queueFilepat
 
 	handle := heartbeat.NewHandle(apiClient,
 		offline.WithSync(queueFilepath, paramOffline.SyncMax),
+		project.WithFileAPIKey(),
 		apikey.WithReplacing(apikey.Config{
-			DefaultAPIKey: paramAPI.Key,
-			MapPatterns:   paramAPI.KeyPatterns,
+			AllowProjectFile: paramAPI.AllowProjectFileKey,
+			DefaultAPIKey:    paramAPI.Key,
+			MapPatterns:      paramAPI.KeyPatterns,
 		}),
 	)
 
@@ -144,9 +147,11 @@ func SyncOfflineActivity(ctx context.Context, v *viper.Viper, queueFilepath stri
 
 	handle := heartbeat.NewHandle(apiClient,
 		offline.WithSync(queueFilepath, paramOffline.SyncMax),
+		project.WithFileAPIKey(),
 		apikey.WithReplacing(apikey.Config{
-			DefaultAPIKey: paramAPI.Key,
-			MapPatterns:   paramAPI.KeyPatterns,
+			AllowProjectFile: paramAPI.AllowProjectFileKey,
+			DefaultAPIKey:    paramAPI.Key,
+			MapPatterns:      paramAPI.KeyPatterns,
 		}),
 	)
 
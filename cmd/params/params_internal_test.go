@@ -2,13 +2,22 @@ package params
 
 import (
 	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/ini"
+	"github.com/wakatime/wakatime-cli/pkg/language"
+	"github.com/wakatime/wakatime-cli/pkg/plugin"
+	"github.com/wakatime/wakatime-cli/pkg/project"
 	"github.com/wakatime/wakatime-cli/pkg/regex"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -96,3 +105,515 @@ func TestSafeTimeParse_Err(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadPluginParams(t *testing.T) {
+	v := viper.New()
+	v.Set("plugins.ticket", "/usr/local/bin/ticket-from-branch --prefix JIRA")
+	v.Set("plugins.owner", `"C:\Program Files\Owner\service-owner.exe"`)
+	v.Set("settings.plugins_timeout", 5)
+
+	params, err := loadPluginParams(v)
+	require.NoError(t, err)
+
+	assert.Equal(t, PluginParams{
+		Plugins: []plugin.Plugin{
+			{
+				Name:    "owner",
+				Command: []string{`C:\Program Files\Owner\service-owner.exe`},
+			},
+			{
+				Name:    "ticket",
+				Command: []string{"/usr/local/bin/ticket-from-branch", "--prefix", "JIRA"},
+			},
+		},
+		Timeout: 5 * time.Second,
+	}, params)
+}
+
+func TestLoadPluginParams_Default(t *testing.T) {
+	params, err := loadPluginParams(viper.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, PluginParams{
+		Timeout: plugin.DefaultTimeoutSecs * time.Second,
+	}, params)
+}
+
+func TestLoadPluginParams_InvalidTimeout(t *testing.T) {
+	v := viper.New()
+	v.Set("settings.plugins_timeout", -1)
+
+	_, err := loadPluginParams(v)
+	assert.EqualError(t, err, "plugins_timeout must be a positive integer number, got -1")
+}
+
+func TestLoadPluginParams_UnquotedPathWithSpaces(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "Program Files")
+
+	err := os.Mkdir(dir, 0700)
+	require.NoError(t, err)
+
+	fp := filepath.Join(dir, "ticket.exe")
+
+	err = os.WriteFile(fp, []byte{}, 0600)
+	require.NoError(t, err)
+
+	v := viper.New()
+	v.Set("plugins.ticket", fp)
+
+	params, err := loadPluginParams(v)
+	require.NoError(t, err)
+
+	require.Len(t, params.Plugins, 1)
+	assert.Equal(t, []string{fp}, params.Plugins[0].Command)
+}
+
+func TestLoadPluginParams_UnterminatedQuote(t *testing.T) {
+	v := viper.New()
+	v.Set("plugins.ticket", `"C:\Program Files\ticket.exe --prefix JIRA`)
+
+	_, err := loadPluginParams(v)
+	assert.EqualError(t, err, `failed to parse command for plugin "ticket": unterminated quote "\""`)
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := map[string]struct {
+		Command  string
+		Expected []string
+	}{
+		"empty": {
+			Command: "  ",
+		},
+		"arguments": {
+			Command:  "/usr/local/bin/ticket --prefix JIRA",
+			Expected: []string{"/usr/local/bin/ticket", "--prefix", "JIRA"},
+		},
+		"windows path with spaces": {
+			Command:  `"C:\Program Files\Ticket\ticket.exe" --prefix JIRA`,
+			Expected: []string{`C:\Program Files\Ticket\ticket.exe`, "--prefix", "JIRA"},
+		},
+		"single quoted argument": {
+			Command:  `ticket --format '%s: %s'`,
+			Expected: []string{"ticket", "--format", "%s: %s"},
+		},
+		"empty quoted argument": {
+			Command:  `ticket ""`,
+			Expected: []string{"ticket", ""},
+		},
+		"quoted part of argument": {
+			Command:  `ticket --dir="/home/user/my projects"`,
+			Expected: []string{"ticket", "--dir=/home/user/my projects"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			args, err := splitCommand(test.Command)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Expected, args)
+		})
+	}
+}
+
+func TestExtraHeartbeatsReader(t *testing.T) {
+	input := strings.Join([]string{
+		`{"entity": "/tmp/main.go", "category": "coding", "type": "file", "time": 1585598059}`,
+		``,
+		`{"entity": "/tmp/main.go", "type": "invalid", "time": 1585598060}`,
+		`{"entity": "/tmp/main.py", "type": "file", "timestamp": 1585598061}`,
+		`not json`,
+		`{"entity": "/tmp/main.rs", "type": "file", "time": 1585598062}`,
+	}, "\n")
+
+	r := NewExtraHeartbeatsReader(strings.NewReader(input))
+
+	heartbeats, rejected, err := r.Read(2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []heartbeat.Heartbeat{
+		{
+			Category:      heartbeat.CodingCategory,
+			Entity:        "/tmp/main.go",
+			EntityType:    heartbeat.FileType,
+			IsCategorySet: true,
+			Time:          1585598059,
+		},
+		{
+			Entity:     "/tmp/main.py",
+			EntityType: heartbeat.FileType,
+			Time:       1585598061,
+		},
+	}, heartbeats)
+	assert.Equal(t, []ExtraHeartbeatError{
+		{
+			Line:  3,
+			Error: `invalid entity type "invalid"`,
+		},
+	}, rejected)
+
+	heartbeats, rejected, err = r.Read(2)
+	require.ErrorIs(t, err, io.EOF)
+
+	assert.Equal(t, []heartbeat.Heartbeat{
+		{
+			Entity:     "/tmp/main.rs",
+			EntityType: heartbeat.FileType,
+			Time:       1585598062,
+		},
+	}, heartbeats)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, 5, rejected[0].Line)
+	assert.Contains(t, rejected[0].Error, "failed to json decode")
+}
+
+func TestExtraHeartbeatsReader_LineTooLong(t *testing.T) {
+	input := strings.Join([]string{
+		`{"entity": "/tmp/main.go", "type": "file", "time": 1585598059}`,
+		`{"entity": "` + strings.Repeat("a", maxExtraHeartbeatLineSize) + `"}`,
+		`{"entity": "/tmp/main.py", "type": "file", "time": 1585598061}`,
+	}, "\n")
+
+	r := NewExtraHeartbeatsReader(strings.NewReader(input))
+
+	heartbeats, rejected, err := r.Read(10)
+	require.ErrorIs(t, err, io.EOF)
+
+	assert.Equal(t, []heartbeat.Heartbeat{
+		{
+			Entity:     "/tmp/main.go",
+			EntityType: heartbeat.FileType,
+			Time:       1585598059,
+		},
+		{
+			Entity:     "/tmp/main.py",
+			EntityType: heartbeat.FileType,
+			Time:       1585598061,
+		},
+	}, heartbeats)
+	assert.Equal(t, []ExtraHeartbeatError{
+		{
+			Line:  2,
+			Error: "line exceeds maximum size of 1048576 bytes",
+		},
+	}, rejected)
+}
+
+func TestLoadHeartbeatParams_Category(t *testing.T) {
+	tests := map[string]struct {
+		Category string
+		Set      bool
+	}{
+		"default": {},
+		"coding": {
+			Category: "coding",
+			Set:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := viper.New()
+			v.Set("category", test.Category)
+			v.Set("entity", "/tmp/main.go")
+
+			params, err := LoadHeartbeatParams(context.Background(), v)
+			require.NoError(t, err)
+
+			assert.Equal(t, heartbeat.CodingCategory, params.Category)
+			assert.Equal(t, test.Set, params.IsCategorySet)
+		})
+	}
+}
+
+func TestLoadHeartbeatParams_ExtraHeartbeatsNDJSON(t *testing.T) {
+	v := viper.New()
+	v.Set("entity", "/tmp/main.go")
+	v.Set("extra-heartbeats", true)
+	v.Set("extra-heartbeats-format", "ndjson")
+
+	params, err := LoadHeartbeatParams(context.Background(), v)
+	require.NoError(t, err)
+
+	assert.True(t, params.ExtraHeartbeatsNDJSON)
+	assert.Nil(t, params.ExtraHeartbeats)
+}
+
+func TestLoadHeartbeatParams_ExtraHeartbeatsInvalidFormat(t *testing.T) {
+	v := viper.New()
+	v.Set("entity", "/tmp/main.go")
+	v.Set("extra-heartbeats", true)
+	v.Set("extra-heartbeats-format", "xml")
+
+	_, err := LoadHeartbeatParams(context.Background(), v)
+	require.EqualError(t, err, `invalid extra heartbeats format "xml"`)
+}
+
+func TestLoadPathTranslations(t *testing.T) {
+	v := viper.New()
+	v.Set("path_translations./workspaces", "/home/user/projects")
+	v.Set("path_translations./workspaces/wakatime-cli", "/home/user/wakatime-cli")
+	v.Set("path_translations./mnt/c", `C:\`)
+
+	translations := loadPathTranslations(v)
+
+	assert.Equal(t, []project.PathTranslation{
+		{From: "/workspaces/wakatime-cli", To: "/home/user/wakatime-cli"},
+		{From: "/workspaces", To: "/home/user/projects"},
+		{From: "/mnt/c", To: `C:\`},
+	}, translations)
+}
+
+func TestLoadPathTranslations_ConfigFile(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), ".wakatime.cfg")
+
+	err := os.WriteFile(fp, []byte("[path_translations]\n/mnt/c/Users/user = C:\\Users\\user\n"), 0600)
+	require.NoError(t, err)
+
+	v := viper.New()
+
+	err = ini.ReadInConfig(v, fp)
+	require.NoError(t, err)
+
+	translated, ok := project.TranslatePath("/mnt/c/Users/user/projects/main.go", loadPathTranslations(v))
+	require.True(t, ok)
+
+	assert.Equal(t, `C:\Users\user\projects\main.go`, translated)
+}
+
+func TestLoadGitWorkTrees(t *testing.T) {
+	v := viper.New()
+	v.Set("git_work_trees./home/user", "/home/user/.dotfiles")
+	v.Set("git_work_trees./etc", "/srv/git/etc.git")
+
+	workTrees := loadGitWorkTrees(v)
+
+	assert.Equal(t, []project.GitWorkTree{
+		{WorkTree: "/etc", GitDir: "/srv/git/etc.git"},
+		{WorkTree: "/home/user", GitDir: "/home/user/.dotfiles"},
+	}, workTrees)
+}
+
+func TestParseGitRemoteNames(t *testing.T) {
+	tests := map[string]struct {
+		Value    string
+		Expected []string
+	}{
+		"empty": {
+			Expected: []string{},
+		},
+		"single": {
+			Value:    "upstream",
+			Expected: []string{"upstream"},
+		},
+		"comma separated": {
+			Value:    "upstream, origin",
+			Expected: []string{"upstream", "origin"},
+		},
+		"multiline": {
+			Value:    "\n  upstream\n  company\n",
+			Expected: []string{"upstream", "company"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, parseGitRemoteNames(test.Value))
+		})
+	}
+}
+
+func TestLoadGitRemoteAliases(t *testing.T) {
+	v := viper.New()
+	v.Set("git_remote_aliases.alan/wakatime-cli", "wakatime/wakatime-cli")
+	v.Set("git_remote_aliases.Acme/WakaTime-CLI", " wakatime/wakatime-cli ")
+	v.Set("git_remote_aliases.empty/alias", "")
+
+	aliases := loadGitRemoteAliases(v)
+
+	assert.Equal(t, map[string]string{
+		"alan/wakatime-cli": "wakatime/wakatime-cli",
+		"acme/wakatime-cli": "wakatime/wakatime-cli",
+	}, aliases)
+}
+
+func TestLoadProjectMapRules(t *testing.T) {
+	v := viper.New()
+	v.Set("projectmap_rules.acme.remote", `github\.com[:/]acme/([^/]+?)(\.git)?$`)
+	v.Set("projectmap_rules.acme.env.ci_project_namespace", "^acme$")
+	v.Set("projectmap_rules.acme.project", "acme/{remote[0]}")
+	v.Set("projectmap_rules.release.branch", "^release/")
+	v.Set("projectmap_rules.release.hostname", "^work-")
+	v.Set("projectmap_rules.release.path", "/projects/")
+	v.Set("projectmap_rules.release.project", "release")
+	v.Set("projectmap_rules.invalid.remote", "github.com/(acme")
+	v.Set("projectmap_rules.invalid.project", "invalid")
+	v.Set("projectmap_rules.unknown.color", "blue")
+	v.Set("projectmap_rules.unknown.project", "unknown")
+	v.Set("projectmap_rules.noproject.branch", "^main$")
+
+	rules := loadProjectMapRules(context.Background(), v)
+
+	require.Len(t, rules, 2)
+
+	assert.Equal(t, "acme", rules[0].Name)
+	assert.Equal(t, "acme/{remote[0]}", rules[0].Project)
+	assert.Equal(t, `(?i)github\.com[:/]acme/([^/]+?)(\.git)?$`, rules[0].Remote.String())
+	assert.Nil(t, rules[0].Branch)
+	require.Len(t, rules[0].Env, 1)
+	assert.Equal(t, "(?i)^acme$", rules[0].Env["ci_project_namespace"].String())
+
+	assert.Equal(t, "release", rules[1].Name)
+	assert.Equal(t, "release", rules[1].Project)
+	assert.Equal(t, "(?i)^release/", rules[1].Branch.String())
+	assert.Equal(t, "(?i)^work-", rules[1].Hostname.String())
+	assert.Equal(t, "(?i)/projects/", rules[1].Path.String())
+	assert.Nil(t, rules[1].Remote)
+}
+
+func TestLoadLanguageMapPatterns(t *testing.T) {
+	v := viper.New()
+	v.Set("languages.*.inc", "PHP")
+	v.Set("languages.templates/**/*.tpl", "Smarty")
+	v.Set("languages./projects/legacy/**/*.h", "C++")
+	v.Set(`languages./^.*/views/.*\.html$/`, "HTML")
+	v.Set("languages.*.xyz", "unknown-language")
+	v.Set("languages./views/(/", "HTML")
+
+	patterns := LoadLanguageMapPatterns(context.Background(), v)
+
+	require.Len(t, patterns, 4)
+
+	assert.Equal(t, heartbeat.LanguageCPP, patterns[0].Language)
+	assert.Equal(t, heartbeat.LanguageHTML, patterns[1].Language)
+	assert.Equal(t, heartbeat.LanguageSmarty, patterns[2].Language)
+	assert.Equal(t, heartbeat.LanguagePHP, patterns[3].Language)
+}
+
+func TestGlobToRegex(t *testing.T) {
+	tests := map[string]struct {
+		Glob      string
+		Matches   []string
+		NoMatches []string
+	}{
+		"file name": {
+			Glob:      "*.inc",
+			Matches:   []string{"config.inc", "/path/to/config.inc", `C:\path\to\config.inc`},
+			NoMatches: []string{"config.inc.php", "/path/to.inc/config"},
+		},
+		"relative": {
+			Glob:      "templates/**/*.tpl",
+			Matches:   []string{"/app/templates/index.tpl", "/app/templates/admin/users/list.tpl"},
+			NoMatches: []string{"/app/mytemplates/index.tpl", "/app/templates/index.html"},
+		},
+		"absolute": {
+			Glob:      "/projects/legacy/**/*.h",
+			Matches:   []string{"/projects/legacy/main.h", "/projects/legacy/src/lib/util.h"},
+			NoMatches: []string{"/home/projects/legacy/main.h", "/projects/other/main.h"},
+		},
+		"single character": {
+			Glob:      "file?.txt",
+			Matches:   []string{"/path/file1.txt"},
+			NoMatches: []string{"/path/file10.txt", "/path/file.txt"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rgx := regexp.MustCompile(globToRegex(test.Glob))
+
+			for _, fp := range test.Matches {
+				assert.True(t, rgx.MatchString(fp), fp)
+			}
+
+			for _, fp := range test.NoMatches {
+				assert.False(t, rgx.MatchString(fp), fp)
+			}
+		})
+	}
+}
+
+func TestParseAttributeAction(t *testing.T) {
+	building := heartbeat.BuildingCategory
+	writingDocs := heartbeat.WritingDocsCategory
+
+	tests := map[string]struct {
+		Input    string
+		Expected language.AttributeAction
+	}{
+		"empty": {
+			Input:    " ",
+			Expected: language.AttributeAction{},
+		},
+		"skip": {
+			Input:    "Skip",
+			Expected: language.AttributeAction{Skip: true},
+		},
+		"category": {
+			Input:    "building",
+			Expected: language.AttributeAction{Category: &building},
+		},
+		"category with space": {
+			Input:    " writing docs ",
+			Expected: language.AttributeAction{Category: &writingDocs},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			action, err := parseAttributeAction(test.Input)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Expected, action)
+		})
+	}
+}
+
+func TestParseAttributeAction_Invalid(t *testing.T) {
+	_, err := parseAttributeAction("ignore")
+	require.Error(t, err)
+}
+
+func TestLoadFilterParams_Content(t *testing.T) {
+	v := viper.New()
+	v.Set("settings.content_binary", "skip")
+	v.Set("settings.content_generated", "building")
+
+	params, err := loadFilterParams(context.Background(), v)
+	require.NoError(t, err)
+
+	building := heartbeat.BuildingCategory
+
+	assert.Equal(t, language.AttributeAction{Skip: true}, params.ContentBinary)
+	assert.Equal(t, language.AttributeAction{Category: &building}, params.ContentGenerated)
+	assert.Equal(t, language.AttributeAction{}, params.ContentMinified)
+}
+
+func TestLoadFilterParams_PatternConfigKey(t *testing.T) {
+	v := viper.New()
+	v.Set("exclude", []string{"^/tmp/"})
+	v.Set("settings.exclude", []string{"\\.min\\.js$"})
+	v.Set("settings.ignore", []string{"^/var/"})
+	v.Set("settings.include", []string{"^/tmp/keep/"})
+
+	params, err := loadFilterParams(context.Background(), v)
+	require.NoError(t, err)
+
+	var exclude []string
+	for _, pattern := range params.Exclude {
+		exclude = append(exclude, pattern.ConfigKey)
+	}
+
+	assert.Equal(t, []string{"exclude", "settings.exclude", "settings.ignore"}, exclude)
+
+	require.Len(t, params.Include, 1)
+	assert.Equal(t, "settings.include", params.Include[0].ConfigKey)
+}
+
+func TestLoadFilterParams_ContentInvalid(t *testing.T) {
+	v := viper.New()
+	v.Set("settings.content_minified", "ignore")
+
+	_, err := loadFilterParams(context.Background(), v)
+	require.ErrorContains(t, err, "failed to parse content_minified param")
+}
@@ -90,8 +90,21 @@ func setFlags(cmd *cobra.Command, v *viper.Viper) {
 		"Writes value to a config key, then exits. Expects two arguments, key and value.",
 	)
 	flags.Int("cursorpos", 0, "Optional cursor position in the current file.")
+	flags.String(
+		"detect-language",
+		"",
+		"Prints the language detected for the given file, honoring --language, --alternate-language,"+
+			" [languages] and .gitattributes like for heartbeats, along with the candidate lexers, their"+
+			" weights and priorities, then exits. Use --output to print them as json.",
+	)
 	flags.Bool("disable-offline", false, "Disables offline time logging instead of queuing logged time.")
 	flags.Bool("disableoffline", false, "(deprecated) Disables offline time logging instead of queuing logged time.")
+	flags.Bool(
+		"dry-run",
+		false,
+		"Processes heartbeats like when sending them, but prints the resulting heartbeats instead of"+
+			" sending them to the api. Use --output to print them as json.",
+	)
 	flags.String(
 		"entity",
 		"",
@@ -113,7 +126,21 @@ func setFlags(cmd *cobra.Command, v *viper.Viper) {
 		false,
 		"When set, any activity where the project cannot be detected will be ignored.",
 	)
+	flags.String(
+		"explain",
+		"",
+		"Explains for the given entity why it would be excluded, hidden or assigned to a project, by"+
+			" printing every filter, sanitize and project decision taken while processing its heartbeat."+
+			" Use --output to print them as json.",
+	)
 	flags.Bool("extra-heartbeats", false, "Reads extra heartbeats from STDIN as a JSON array until EOF.")
+	flags.String(
+		"extra-heartbeats-format",
+		"json",
+		"Format of extra heartbeats read from STDIN. Can be \"json\" for a single JSON array or \"ndjson\""+
+			" for one heartbeat JSON object per line. With \"ndjson\" heartbeats are sent in chunks while reading"+
+			" and rejected lines are reported on STDOUT as JSON.",
+	)
 	flags.String(
 		"file",
 		"",
@@ -177,6 +204,12 @@ func setFlags(cmd *cobra.Command, v *viper.Viper) {
 			" can be provided manually for performance, accuracy, or when using --local-file.")
 	flags.Int("line-additions", 0, "Optional number of lines added since last heartbeat in the current file.")
 	flags.Int("line-deletions", 0, "Optional number of lines deleted since last heartbeat in the current file.")
+	flags.Bool(
+		"list-languages",
+		false,
+		"Prints all languages with their chroma lexer names and vim file types, then exits."+
+			" Use --output to print them as json.",
+	)
 	flags.String(
 		"local-file",
 		"",
@@ -211,7 +244,8 @@ func setFlags(cmd *cobra.Command, v *viper.Viper) {
 	flags.String(
 		"output",
 		"",
-		"Format output. Can be \"text\", \"json\" or \"raw-json\". Defaults to \"text\".",
+		"Format output. Can be \"text\", \"json\" or \"raw-json\". Defaults to \"text\". When sending"+
+			" heartbeats, json prints a summary of sent, queued, filtered and synced heartbeats.",
 	)
 	flags.String("plugin", "", "Optional text editor plugin name and version for User-Agent header.")
 	flags.Int("print-offline-heartbeats", offline.PrintMaxDefault, "Prints offline heartbeats to stdout.")
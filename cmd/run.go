@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	stdlog "log"
@@ -16,6 +17,7 @@ import (
 	"github.com/wakatime/wakatime-cli/cmd/configwrite"
 	"github.com/wakatime/wakatime-cli/cmd/fileexperts"
 	cmdheartbeat "github.com/wakatime/wakatime-cli/cmd/heartbeat"
+	"github.com/wakatime/wakatime-cli/cmd/languages"
 	"github.com/wakatime/wakatime-cli/cmd/logfile"
 	cmdoffline "github.com/wakatime/wakatime-cli/cmd/offline"
 	"github.com/wakatime/wakatime-cli/cmd/offlinecount"
@@ -28,10 +30,10 @@ import (
 	"github.com/wakatime/wakatime-cli/pkg/exitcode"
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/ini"
-	"github.com/wakatime/wakatime-cli/pkg/lexer"
 	"github.com/wakatime/wakatime-cli/pkg/log"
 	"github.com/wakatime/wakatime-cli/pkg/metrics"
 	"github.com/wakatime/wakatime-cli/pkg/offline"
+	"github.com/wakatime/wakatime-cli/pkg/output"
 	"github.com/wakatime/wakatime-cli/pkg/vipertools"
 	"github.com/wakatime/wakatime-cli/pkg/wakaerror"
 
@@ -75,11 +77,6 @@ func RunE(cmd *cobra.Command, v *viper.Viper) error {
 	// save logger to context
 	ctx = log.ToContext(ctx, logger)
 
-	// register all custom lexers
-	if err := lexer.RegisterAll(); err != nil {
-		logger.Fatalf("failed to register custom lexers: %s", err)
-	}
-
 	// start profiling if enabled
 	if logger.IsMetricsEnabled() {
 		shutdown, err := metrics.StartProfiling(ctx)
@@ -134,10 +131,34 @@ func RunE(cmd *cobra.Command, v *viper.Viper) error {
 		return RunCmd(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors(), fileexperts.Run)
 	}
 
+	if v.GetBool("list-languages") {
+		logger.Debugln("command: list-languages")
+
+		return RunCmd(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors(), languages.RunList)
+	}
+
+	if v.IsSet("detect-language") {
+		logger.Debugln("command: detect-language")
+
+		return RunCmd(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors(), languages.RunDetect)
+	}
+
+	if v.IsSet("explain") {
+		logger.Debugln("command: explain")
+
+		return RunCmd(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors(), cmdheartbeat.RunExplain)
+	}
+
+	if v.IsSet("entity") && v.GetBool("dry-run") {
+		logger.Debugln("command: heartbeat dry run")
+
+		return RunCmd(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors(), cmdheartbeat.RunDryRun)
+	}
+
 	if v.IsSet("entity") {
 		logger.Debugln("command: heartbeat")
 
-		return RunCmdWithOfflineSync(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors(), cmdheartbeat.Run)
+		return runHeartbeatCmd(ctx, v, logger.IsVerboseEnabled(), logger.SendDiagsOnErrors())
 	}
 
 	if v.IsSet("sync-offline-activity") {
@@ -161,8 +182,12 @@ func RunE(cmd *cobra.Command, v *viper.Viper) error {
 	logger.Warnf("one of the following parameters has to be provided: %s", strings.Join([]string{
 		"--config-read",
 		"--config-write",
+		"--detect-language",
+		"--dry-run",
 		"--entity",
+		"--explain",
 		"--file-experts",
+		"--list-languages",
 		"--offline-count",
 		"--print-offline-heartbeats",
 		"--sync-offline-activity",
@@ -275,6 +300,32 @@ func RunCmdWithOfflineSync(ctx context.Context, v *viper.Viper, verbose bool, se
 	return runCmd(ctx, v, verbose, sendDiagsOnErrors, offlinesync.RunWithRateLimiting)
 }
 
+// runHeartbeatCmd runs the heartbeat command followed by offline sync. With json
+// output, a summary of the outcome is printed to stdout afterwards, also if running
+// the command failed.
+func runHeartbeatCmd(ctx context.Context, v *viper.Viper, verbose bool, sendDiagsOnErrors bool) error {
+	out, err := output.Parse(vipertools.GetString(v, "output"))
+	if err != nil || (out != output.JSONOutput && out != output.RawJSONOutput) {
+		return RunCmdWithOfflineSync(ctx, v, verbose, sendDiagsOnErrors, cmdheartbeat.Run)
+	}
+
+	ctx, summary := heartbeat.WithSummary(ctx)
+
+	errRun := RunCmdWithOfflineSync(ctx, v, verbose, sendDiagsOnErrors, cmdheartbeat.Run)
+
+	data, err := json.Marshal(summary.Result())
+	if err != nil {
+		logger := log.Extract(ctx)
+		logger.Errorf("failed to marshal json summary: %s", err)
+
+		return errRun
+	}
+
+	fmt.Println(string(data))
+
+	return errRun
+}
+
 // runCmd contains the main logic of RunCmd.
 // It will send diagnostic on any errors or panics.
 // On panic, it will send diagnostic and exit with ErrGeneric exit code.
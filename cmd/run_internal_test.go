@@ -14,6 +14,7 @@ import (
 
 	cmdheartbeat "github.com/wakatime/wakatime-cli/cmd/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/exitcode"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/ini"
 	"github.com/wakatime/wakatime-cli/pkg/log"
 	"github.com/wakatime/wakatime-cli/pkg/version"
@@ -398,6 +399,138 @@ func TestRunCmd_BackoffNotLogged(t *testing.T) {
 	assert.Empty(t, string(output))
 }
 
+func TestRunHeartbeatCmd_JSONOutput(t *testing.T) {
+	offlineQueueFile, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+
+	defer offlineQueueFile.Close()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	defer func() {
+		r.Close()
+		w.Close()
+	}()
+
+	origStdout := os.Stdout
+
+	defer func() { os.Stdout = origStdout }()
+
+	os.Stdout = w
+
+	v := viper.New()
+	v.Set("entity", "/path/to/file")
+	v.Set("exclude", "^/path/")
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("offline-queue-file", offlineQueueFile.Name())
+	v.Set("output", "json")
+	v.SetDefault("sync-offline-activity", 24)
+
+	err = runHeartbeatCmd(context.Background(), v, false, false)
+	require.NoError(t, err)
+
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"sent": 0,
+		"queued": 0,
+		"filtered": 1,
+		"rejected": 0,
+		"failed": 0,
+		"synced": 0,
+		"heartbeats": [
+			{
+				"entity": "/path/to/file",
+				"project": null,
+				"language": null,
+				"branch": null,
+				"status": "filtered"
+			}
+		]
+	}`, string(out))
+}
+
+func TestRunHeartbeatCmd_JSONOutput_OfflineDisabled(t *testing.T) {
+	testServerURL, router, tearDown := setupTestServer()
+	defer tearDown()
+
+	var numCalls int
+
+	router.HandleFunc("/users/current/heartbeats.bulk", func(w http.ResponseWriter, _ *http.Request) {
+		numCalls++
+
+		f, err := os.Open("testdata/api_heartbeats_response.json")
+		require.NoError(t, err)
+
+		defer f.Close()
+
+		w.WriteHeader(http.StatusCreated)
+
+		_, err = io.Copy(w, f)
+		require.NoError(t, err)
+	})
+
+	tmpDir := t.TempDir()
+
+	offlineQueueFile, err := os.CreateTemp(tmpDir, "")
+	require.NoError(t, err)
+
+	defer offlineQueueFile.Close()
+
+	entity, err := os.CreateTemp(tmpDir, "")
+	require.NoError(t, err)
+
+	defer entity.Close()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	defer func() {
+		r.Close()
+		w.Close()
+	}()
+
+	origStdout := os.Stdout
+
+	defer func() { os.Stdout = origStdout }()
+
+	os.Stdout = w
+
+	v := viper.New()
+	v.Set("api-url", testServerURL)
+	v.Set("disable-offline", true)
+	v.Set("entity", entity.Name())
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("offline-queue-file", offlineQueueFile.Name())
+	v.Set("output", "json")
+
+	err = runHeartbeatCmd(context.Background(), v, false, false)
+	require.NoError(t, err)
+
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	var result heartbeat.SummaryResult
+
+	err = json.Unmarshal(out, &result)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, numCalls)
+	assert.Equal(t, 1, result.Sent)
+	assert.Zero(t, result.Queued)
+	assert.Zero(t, result.Failed)
+
+	require.Len(t, result.Heartbeats, 1)
+	assert.Equal(t, entity.Name(), result.Heartbeats[0].Entity)
+	assert.Equal(t, heartbeat.SummaryStatusSent, result.Heartbeats[0].Status)
+}
+
 func TestParseConfigFiles(t *testing.T) {
 	v := viper.New()
 	v.Set("config", "testdata/.wakatime.cfg")
@@ -14,7 +14,9 @@ import (
 
 	"github.com/wakatime/wakatime-cli/cmd/today"
 	"github.com/wakatime/wakatime-cli/pkg/api"
+	"github.com/wakatime/wakatime-cli/pkg/lexer"
 
+	"github.com/alecthomas/chroma/v2"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -179,6 +181,47 @@ func TestToday_ErrAuth_UnsetAPIKey(t *testing.T) {
 	assert.Equal(t, "failed to load API parameters: api key not found or empty", err.Error())
 }
 
+// BenchmarkToday measures the --today command, which doesn't detect languages,
+// with and without the registration of custom lexers it used to pay on startup.
+// Lexers are registered to a new registry to leave the global registry as is.
+func BenchmarkToday(b *testing.B) {
+	testServerURL, router, tearDown := setupTestServer()
+	defer tearDown()
+
+	router.HandleFunc("/users/current/statusbar/today", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		f, err := os.Open("testdata/api_statusbar_today_response.json")
+		require.NoError(b, err)
+		defer f.Close()
+
+		_, err = io.Copy(w, f)
+		require.NoError(b, err)
+	})
+
+	v := viper.New()
+	v.SetDefault("sync-offline-activity", 1000)
+	v.Set("key", "00000000-0000-4000-8000-000000000000")
+	v.Set("api-url", testServerURL)
+
+	b.Run("without lexer registration", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, err := today.Today(context.Background(), v)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("with lexer registration", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			err := lexer.RegisterTo(chroma.NewLexerRegistry())
+			require.NoError(b, err)
+
+			_, err = today.Today(context.Background(), v)
+			require.NoError(b, err)
+		}
+	})
+}
+
 func setupTestServer() (string, *http.ServeMux, func()) {
 	router := http.NewServeMux()
 	srv := httptest.NewServer(router)
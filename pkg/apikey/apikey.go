@@ -10,6 +10,10 @@ import (
 
 // Config contains apikey project detection configurations.
 type Config struct {
+	// AllowProjectFile enables the api key declared in a .wakatime-project file.
+	// It's disabled by default, because a project file committed to a repository
+	// would otherwise redirect activity to another account.
+	AllowProjectFile bool
 	// DefaultAPIKey contains the default api key.
 	DefaultAPIKey string
 	// Patterns contains the overridden api key per path.
@@ -34,6 +38,20 @@ func WithReplacing(config Config) heartbeat.HandleOption {
 			logger.Debugln("execute api key replacing")
 
 			for n, h := range hh {
+				// api key of the .wakatime-project file takes precedence
+				if h.ProjectSettings != nil && h.ProjectSettings.APIKey != "" {
+					if config.AllowProjectFile {
+						hh[n].APIKey = h.ProjectSettings.APIKey
+
+						continue
+					}
+
+					logger.Debugf(
+						"ignoring api key of project file for %q, enable with allow_project_file_api_key setting",
+						h.Entity,
+					)
+				}
+
 				result, ok := MatchPattern(ctx, h.Entity, config.MapPatterns)
 				if ok {
 					hh[n].APIKey = result
@@ -67,6 +67,61 @@ func TestWithReplacing(t *testing.T) {
 	}, result)
 }
 
+func TestWithReplacing_ProjectSettings(t *testing.T) {
+	tests := map[string]struct {
+		AllowProjectFile bool
+		Expected         string
+	}{
+		"allowed": {
+			AllowProjectFile: true,
+			Expected:         "00000000-0000-4000-8000-000000000002",
+		},
+		"not allowed": {
+			Expected: "00000000-0000-4000-8000-000000000001",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := apikey.Config{
+				AllowProjectFile: test.AllowProjectFile,
+				DefaultAPIKey:    "00000000-0000-4000-8000-000000000000",
+				MapPatterns: []apikey.MapPattern{
+					{
+						APIKey: "00000000-0000-4000-8000-000000000001",
+						Regex:  regex.NewRegexpWrap(regexp.MustCompile(`.workdir.`)),
+					},
+				},
+			}
+
+			settings := &heartbeat.ProjectSettings{
+				APIKey: "00000000-0000-4000-8000-000000000002",
+			}
+
+			opt := apikey.WithReplacing(config)
+			h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+				assert.Equal(t, []heartbeat.Heartbeat{
+					{
+						APIKey:          test.Expected,
+						Entity:          "/workdir/main.go",
+						ProjectSettings: settings,
+					},
+				}, hh)
+
+				return nil, nil
+			})
+
+			_, err := h(context.Background(), []heartbeat.Heartbeat{
+				{
+					Entity:          "/workdir/main.go",
+					ProjectSettings: settings,
+				},
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestApiKey_MatchPattern(t *testing.T) {
 	wd, err := os.Getwd()
 	require.NoError(t, err)
@@ -52,6 +52,11 @@ func WithDetection(c Config) heartbeat.HandleOption {
 					continue
 				}
 
+				if !h.ContentKind.IsText() {
+					logger.Debugf("file %q has %s content. Dependencies won't be detected", h.Entity, h.ContentKind)
+					continue
+				}
+
 				if heartbeat.ShouldSanitize(ctx, heartbeat.SanitizeCheck{
 					Entity:              h.Entity,
 					ProjectPath:         h.ProjectPath,
@@ -118,6 +118,41 @@ func TestWithDetection_LocalFile(t *testing.T) {
 	}, result)
 }
 
+func TestWithDetection_GeneratedContent(t *testing.T) {
+	opt := deps.WithDetection(deps.Config{})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		assert.Equal(t, []heartbeat.Heartbeat{
+			{
+				ContentKind: heartbeat.GeneratedContent,
+				Entity:      "testdata/golang_minimal.go",
+				EntityType:  heartbeat.FileType,
+				Language:    heartbeat.PointerTo("Go"),
+			},
+		}, hh)
+
+		return []heartbeat.Result{
+			{
+				Status: 201,
+			},
+		}, nil
+	})
+
+	result, err := h(context.Background(), []heartbeat.Heartbeat{{
+		ContentKind: heartbeat.GeneratedContent,
+		Entity:      "testdata/golang_minimal.go",
+		EntityType:  heartbeat.FileType,
+		Language:    heartbeat.PointerTo("Go"),
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []heartbeat.Result{
+		{
+			Status: 201,
+		},
+	}, result)
+}
+
 func TestWithDetection_NonFileType(t *testing.T) {
 	opt := deps.WithDetection(deps.Config{})
 
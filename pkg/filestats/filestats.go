@@ -42,6 +42,11 @@ func WithDetection() heartbeat.HandleOption {
 					continue
 				}
 
+				if !h.ContentKind.IsText() {
+					logger.Debugf("file %q has %s content. Lines won't be counted", h.Entity, h.ContentKind)
+					continue
+				}
+
 				filepath := h.Entity
 				if h.LocalFile != "" {
 					filepath = h.LocalFile
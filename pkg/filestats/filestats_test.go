@@ -85,6 +85,30 @@ func TestWithDetection_RemoteFile(t *testing.T) {
 	}, result)
 }
 
+func TestWithDetection_NonTextContent(t *testing.T) {
+	opt := filestats.WithDetection()
+	handle := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		assert.Equal(t, []heartbeat.Heartbeat{
+			{
+				ContentKind: heartbeat.MinifiedContent,
+				EntityType:  heartbeat.FileType,
+				Entity:      "testdata/first.txt",
+			},
+		}, hh)
+
+		return []heartbeat.Result{}, nil
+	})
+
+	_, err := handle(context.Background(), []heartbeat.Heartbeat{
+		{
+			ContentKind: heartbeat.MinifiedContent,
+			EntityType:  heartbeat.FileType,
+			Entity:      "testdata/first.txt",
+		},
+	})
+	require.NoError(t, err)
+}
+
 func TestWithDetection_MaxFileSizeExceeded(t *testing.T) {
 	f, err := os.CreateTemp(t.TempDir(), "")
 	require.NoError(t, err)
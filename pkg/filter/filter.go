@@ -13,11 +13,18 @@ import (
 
 // Config contains filtering configurations.
 type Config struct {
-	Exclude                    []regex.Regex
-	Include                    []regex.Regex
+	Exclude                    []Pattern
+	Include                    []Pattern
 	IncludeOnlyWithProjectFile bool
 }
 
+// Pattern is an include or exclude pattern. ConfigKey is the flag or config
+// key the pattern was set by, which is recorded in the filter decisions.
+type Pattern struct {
+	regex.Regex
+	ConfigKey string
+}
+
 // WithFiltering initializes and returns a heartbeat handle option, which
 // can be used in a heartbeat processing pipeline to filter heartbeats following
 // the provided configurations.
@@ -30,9 +37,10 @@ func WithFiltering(config Config) heartbeat.HandleOption {
 			var filtered []heartbeat.Heartbeat
 
 			for _, h := range hh {
-				err := Filter(ctx, h, config)
+				err := filter(ctx, &h, config)
 				if err != nil {
 					logger.Debugln(err.Error())
+					heartbeat.RecordDropped(ctx, h, err.Error())
 
 					continue
 				}
@@ -65,8 +73,21 @@ func WithLengthValidator() heartbeat.HandleOption {
 // Filter determines, following the passed in configurations, if a heartbeat
 // should be skipped.
 func Filter(ctx context.Context, h heartbeat.Heartbeat, config Config) error {
+	return filter(ctx, &h, config)
+}
+
+// filter contains the main logic of Filter and records the decisions taken on the heartbeat.
+func filter(ctx context.Context, h *heartbeat.Heartbeat, config Config) error {
+	include, exclude := config.Include, config.Exclude
+
+	// patterns of the .wakatime-project file are checked first
+	if h.ProjectSettings != nil {
+		include = append(projectFilePatterns(h.ProjectSettings.Include, "include"), include...)
+		exclude = append(projectFilePatterns(h.ProjectSettings.Exclude, "exclude"), exclude...)
+	}
+
 	// filter by pattern
-	if err := filterByPattern(ctx, h.Entity, config.Include, config.Exclude); err != nil {
+	if err := filterByPattern(ctx, h, include, exclude); err != nil {
 		return fmt.Errorf("filter by pattern: %s", err)
 	}
 
@@ -78,24 +99,55 @@ func Filter(ctx context.Context, h heartbeat.Heartbeat, config Config) error {
 	return nil
 }
 
+// projectFilePatterns returns the patterns of the settings of a .wakatime-project
+// file, set by the passed in key.
+func projectFilePatterns(patterns []regex.Regex, key string) []Pattern {
+	converted := make([]Pattern, len(patterns))
+
+	for i, pattern := range patterns {
+		converted[i] = Pattern{
+			Regex:     pattern,
+			ConfigKey: project.WakaTimeProjectFile + " settings." + key,
+		}
+	}
+
+	return converted
+}
+
 // filterByPattern determines if a heartbeat should be skipped by checking an
 // entity against include and exclude patterns. Include will override exclude.
 // Returns Err to signal to the caller to skip the heartbeat.
-func filterByPattern(ctx context.Context, entity string, include, exclude []regex.Regex) error {
-	if entity == "" {
+func filterByPattern(ctx context.Context, h *heartbeat.Heartbeat, include, exclude []Pattern) error {
+	if h.Entity == "" {
 		return nil
 	}
 
 	// filter by include pattern
 	for _, pattern := range include {
-		if pattern.MatchString(ctx, entity) {
+		if pattern.MatchString(ctx, h.Entity) {
+			heartbeat.RecordDecision(ctx, h, heartbeat.Decision{
+				Stage:     "filter",
+				Rule:      "include",
+				ConfigKey: pattern.ConfigKey,
+				Pattern:   pattern.String(),
+				Message:   "kept because matches include pattern",
+			})
+
 			return nil
 		}
 	}
 
 	// filter by  exclude pattern
 	for _, pattern := range exclude {
-		if pattern.MatchString(ctx, entity) {
+		if pattern.MatchString(ctx, h.Entity) {
+			heartbeat.RecordDecision(ctx, h, heartbeat.Decision{
+				Stage:     "filter",
+				Rule:      "exclude",
+				ConfigKey: pattern.ConfigKey,
+				Pattern:   pattern.String(),
+				Message:   "skipped because matches exclude pattern",
+			})
+
 			return fmt.Errorf("skipping because matches exclude pattern %q", pattern.String())
 		}
 	}
@@ -107,7 +159,7 @@ func filterByPattern(ctx context.Context, entity string, include, exclude []rege
 // the existence of the passed in filepath, and optionally by checking if a
 // wakatime project file can be detected in the filepath directory tree.
 // Returns an error to signal to the caller to skip the heartbeat.
-func filterFileEntity(ctx context.Context, h heartbeat.Heartbeat, config Config) error {
+func filterFileEntity(ctx context.Context, h *heartbeat.Heartbeat, config Config) error {
 	if h.EntityType != heartbeat.FileType {
 		return nil
 	}
@@ -127,6 +179,12 @@ func filterFileEntity(ctx context.Context, h heartbeat.Heartbeat, config Config)
 
 	// skip files that don't exist on disk
 	if _, err := os.Stat(entity); os.IsNotExist(err) {
+		heartbeat.RecordDecision(ctx, h, heartbeat.Decision{
+			Stage:   "filter",
+			Rule:    "non-existing file",
+			Message: "skipped because file does not exist on disk",
+		})
+
 		return fmt.Errorf("skipping because of non-existing file %q", entity)
 	}
 
@@ -134,6 +192,13 @@ func filterFileEntity(ctx context.Context, h heartbeat.Heartbeat, config Config)
 	if config.IncludeOnlyWithProjectFile {
 		_, ok := project.FindFileOrDirectory(ctx, entity, project.WakaTimeProjectFile)
 		if !ok {
+			heartbeat.RecordDecision(ctx, h, heartbeat.Decision{
+				Stage:     "filter",
+				Rule:      "include only with project file",
+				ConfigKey: "settings.include_only_with_project_file",
+				Message:   "skipped because of missing .wakatime-project file in parent path",
+			})
+
 			return fmt.Errorf("skipping because missing .wakatime-project file in parent path")
 		}
 	}
@@ -118,11 +118,11 @@ func TestFilter_IncludeMatchOverwritesExcludeMatch(t *testing.T) {
 	h.Entity = tmpFile.Name()
 
 	err = filter.Filter(context.Background(), h, filter.Config{
-		Exclude: []regex.Regex{
-			regex.MustCompile(".*main.go$"),
+		Exclude: []filter.Pattern{
+			{Regex: regex.MustCompile(".*main.go$")},
 		},
-		Include: []regex.Regex{
-			regex.MustCompile(".*/tmp/.*"),
+		Include: []filter.Pattern{
+			{Regex: regex.MustCompile(".*/tmp/.*")},
 		},
 	})
 	require.NoError(t, err)
@@ -138,14 +138,113 @@ func TestFilter_ErrMatchesExcludePattern(t *testing.T) {
 	h.Entity = tmpFile.Name()
 
 	err = filter.Filter(context.Background(), h, filter.Config{
-		Exclude: []regex.Regex{
-			regex.MustCompile("^.*exclude-this-file.*$"),
+		Exclude: []filter.Pattern{
+			{Regex: regex.MustCompile("^.*exclude-this-file.*$")},
 		},
 	})
 
 	assert.EqualError(t, err, "filter by pattern: skipping because matches exclude pattern \"^.*exclude-this-file.*$\"")
 }
 
+func TestFilter_ProjectSettings(t *testing.T) {
+	tmpFile, err := os.CreateTemp(t.TempDir(), "exclude-this-file")
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	tests := map[string]struct {
+		Config   filter.Config
+		Settings heartbeat.ProjectSettings
+		Err      string
+	}{
+		"exclude": {
+			Settings: heartbeat.ProjectSettings{
+				Exclude: []regex.Regex{regex.MustCompile("^.*exclude-this-file.*$")},
+			},
+			Err: "filter by pattern: skipping because matches exclude pattern \"^.*exclude-this-file.*$\"",
+		},
+		"include overwrites config exclude": {
+			Config: filter.Config{
+				Exclude: []filter.Pattern{{Regex: regex.MustCompile("^.*exclude-this-file.*$")}},
+			},
+			Settings: heartbeat.ProjectSettings{
+				Include: []regex.Regex{regex.MustCompile("exclude-this-file")},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := testHeartbeat()
+			h.Entity = tmpFile.Name()
+			h.ProjectSettings = &test.Settings
+
+			err := filter.Filter(context.Background(), h, test.Config)
+			if test.Err != "" {
+				assert.EqualError(t, err, test.Err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithFiltering_PatternConfigKey(t *testing.T) {
+	tmpFile, err := os.CreateTemp(t.TempDir(), "include-this-file")
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	tests := map[string]struct {
+		Config    filter.Config
+		Settings  *heartbeat.ProjectSettings
+		ConfigKey string
+	}{
+		"flag": {
+			Config: filter.Config{
+				Include: []filter.Pattern{{Regex: regex.MustCompile("include-this-file"), ConfigKey: "include"}},
+			},
+			ConfigKey: "include",
+		},
+		"config file": {
+			Config: filter.Config{
+				Include: []filter.Pattern{{Regex: regex.MustCompile("include-this-file"), ConfigKey: "settings.include"}},
+			},
+			ConfigKey: "settings.include",
+		},
+		"project file": {
+			Settings: &heartbeat.ProjectSettings{
+				Include: []regex.Regex{regex.MustCompile("include-this-file")},
+			},
+			ConfigKey: ".wakatime-project settings.include",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := testHeartbeat()
+			h.Entity = tmpFile.Name()
+			h.ProjectSettings = test.Settings
+
+			opt := filter.WithFiltering(test.Config)
+			handle := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+				require.Len(t, hh, 1)
+				require.Len(t, hh[0].Decisions, 1)
+
+				assert.Equal(t, test.ConfigKey, hh[0].Decisions[0].ConfigKey)
+
+				return nil, nil
+			})
+
+			ctx, _ := heartbeat.WithRecorder(context.Background())
+
+			_, err := handle(ctx, []heartbeat.Heartbeat{h})
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestFilter_ErrNonExistingFile(t *testing.T) {
 	h := testHeartbeat()
 
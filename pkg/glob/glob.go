@@ -0,0 +1,112 @@
+package glob
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Match reports whether the slash separated path name matches the glob
+// pattern. See Regex for the supported pattern syntax.
+func Match(pattern, name string) bool {
+	rgx, err := regexp.Compile("^" + Regex(pattern) + "$")
+	if err != nil {
+		return false
+	}
+
+	return rgx.MatchString(name)
+}
+
+// Regex converts a glob pattern into an unanchored regular expression matching
+// slash separated paths. * and ? match any characters except a slash, [...]
+// matches a character class and a backslash escapes the next character. A **
+// path element matches any number of folders, including none. Elsewhere, ** is
+// treated as a regular *.
+func Regex(pattern string) string {
+	return toRegex(pattern, "/")
+}
+
+// RegexAnySeparator is like Regex, but the returned regular expression
+// matches both slash and backslash separated paths.
+func RegexAnySeparator(pattern string) string {
+	return toRegex(pattern, `/\\`)
+}
+
+func toRegex(pattern, separators string) string {
+	var (
+		b      strings.Builder
+		sep    = "[" + separators + "]"
+		notSep = "[^" + separators + "]"
+	)
+
+	for i := 0; i < len(pattern); i++ {
+		atStart := i == 0 || pattern[i-1] == '/'
+
+		switch c := pattern[i]; {
+		case atStart && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*" + sep + ")?")
+			i += 2
+		case atStart && pattern[i:] == "**":
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString(notSep + "*")
+
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+		case c == '?':
+			b.WriteString(notSep)
+		case c == '/':
+			b.WriteString(sep)
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '[':
+			class, n, ok := characterClass(pattern[i:])
+			if !ok {
+				b.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+
+			b.WriteString(class)
+			i += n - 1
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+
+	return b.String()
+}
+
+// characterClass converts the character class at the start of s into a
+// regular expression character class. Returns the number of bytes consumed
+// and false, if s doesn't start with a complete character class.
+func characterClass(s string) (string, int, bool) {
+	var b strings.Builder
+
+	b.WriteString("[")
+
+	i := 1
+
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		b.WriteString("^")
+		i++
+	}
+
+	for start := i; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ']' && i > start:
+			b.WriteString("]")
+			return b.String(), i + 1, true
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteString(regexp.QuoteMeta(s[i : i+1]))
+		case c == '\\' || c == '[':
+			b.WriteString(`\` + string(c))
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", 0, false
+}
@@ -0,0 +1,103 @@
+package glob_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/glob"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := map[string]struct {
+		Pattern  string
+		Name     string
+		Expected bool
+	}{
+		"star": {
+			Pattern:  "*.go",
+			Name:     "main.go",
+			Expected: true,
+		},
+		"star does not match slash": {
+			Pattern: "*.go",
+			Name:    "cmd/main.go",
+		},
+		"question mark": {
+			Pattern:  "file?.txt",
+			Name:     "file1.txt",
+			Expected: true,
+		},
+		"leading double star": {
+			Pattern:  "**/vendor/*.js",
+			Name:     "web/static/vendor/jquery.js",
+			Expected: true,
+		},
+		"leading double star matches no folder": {
+			Pattern:  "**/vendor/*.js",
+			Name:     "vendor/jquery.js",
+			Expected: true,
+		},
+		"double star in between": {
+			Pattern:  "docs/**/*.md",
+			Name:     "docs/api/v1/index.md",
+			Expected: true,
+		},
+		"double star in between matches no folder": {
+			Pattern:  "docs/**/*.md",
+			Name:     "docs/index.md",
+			Expected: true,
+		},
+		"trailing double star": {
+			Pattern:  "packages/**",
+			Name:     "packages/app/src/main.ts",
+			Expected: true,
+		},
+		"trailing double star does not match folder itself": {
+			Pattern: "packages/**",
+			Name:    "packages",
+		},
+		"double star within element is a regular star": {
+			Pattern: "src/a**.go",
+			Name:    "src/a/b.go",
+		},
+		"character class": {
+			Pattern:  "file[0-9].txt",
+			Name:     "file1.txt",
+			Expected: true,
+		},
+		"negated character class": {
+			Pattern: "file[!0-9].txt",
+			Name:    "file1.txt",
+		},
+		"escaped star": {
+			Pattern: `file\*.txt`,
+			Name:    "file1.txt",
+		},
+		"regex meta characters": {
+			Pattern:  "(a+b).txt",
+			Name:     "(a+b).txt",
+			Expected: true,
+		},
+		"unclosed character class": {
+			Pattern:  "file[.txt",
+			Name:     "file[.txt",
+			Expected: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, glob.Match(test.Pattern, test.Name))
+		})
+	}
+}
+
+func TestRegexAnySeparator(t *testing.T) {
+	rgx := regexp.MustCompile("^" + glob.RegexAnySeparator("templates/**/*.tpl") + "$")
+
+	assert.True(t, rgx.MatchString("templates/partials/header.tpl"))
+	assert.True(t, rgx.MatchString(`templates\partials\header.tpl`))
+	assert.False(t, rgx.MatchString(`templates\header.txt`))
+}
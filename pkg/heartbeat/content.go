@@ -0,0 +1,45 @@
+package heartbeat
+
+// ContentKind represents the kind of content of a file, detected by sniffing
+// its content.
+type ContentKind int
+
+const (
+	// TextContent means the file contains regular text. This is the default value.
+	TextContent ContentKind = iota
+	// BinaryContent means the file contains binary data.
+	BinaryContent
+	// GeneratedContent means the file is generated by a tool.
+	GeneratedContent
+	// MinifiedContent means the file contains minified code.
+	MinifiedContent
+)
+
+const (
+	binaryContentString    = "binary"
+	generatedContentString = "generated"
+	minifiedContentString  = "minified"
+	textContentString      = "text"
+)
+
+// String implements fmt.Stringer interface.
+func (c ContentKind) String() string {
+	switch c {
+	case BinaryContent:
+		return binaryContentString
+	case GeneratedContent:
+		return generatedContentString
+	case MinifiedContent:
+		return minifiedContentString
+	case TextContent:
+		return textContentString
+	default:
+		return ""
+	}
+}
+
+// IsText returns true if the file contains regular text, whose lines and
+// dependencies are worth parsing.
+func (c ContentKind) IsText() bool {
+	return c == TextContent
+}
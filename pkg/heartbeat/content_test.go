@@ -0,0 +1,31 @@
+package heartbeat_test
+
+import (
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentKind_String(t *testing.T) {
+	tests := map[string]heartbeat.ContentKind{
+		"binary":    heartbeat.BinaryContent,
+		"generated": heartbeat.GeneratedContent,
+		"minified":  heartbeat.MinifiedContent,
+		"text":      heartbeat.TextContent,
+	}
+
+	for value, kind := range tests {
+		t.Run(value, func(t *testing.T) {
+			assert.Equal(t, value, kind.String())
+		})
+	}
+}
+
+func TestContentKind_IsText(t *testing.T) {
+	assert.True(t, heartbeat.TextContent.IsText())
+	assert.False(t, heartbeat.BinaryContent.IsText())
+	assert.False(t, heartbeat.GeneratedContent.IsText())
+	assert.False(t, heartbeat.MinifiedContent.IsText())
+}
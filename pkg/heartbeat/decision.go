@@ -0,0 +1,97 @@
+package heartbeat
+
+import (
+	"context"
+	"sync"
+)
+
+// Decision describes a decision taken for a heartbeat by a stage of the
+// processing pipeline, e.g. a filter rule which matched or the detector
+// which set the project.
+type Decision struct {
+	// Stage is the pipeline stage which took the decision, e.g. filter or sanitize.
+	Stage string `json:"stage"`
+	// Rule is the type of rule applied, e.g. exclude or git-detector.
+	Rule string `json:"rule"`
+	// ConfigKey is the config key or command line parameter of the rule, if any.
+	ConfigKey string `json:"config_key,omitempty"`
+	// Pattern is the regular expression which matched, if any.
+	Pattern string `json:"pattern,omitempty"`
+	// Field is the heartbeat field changed by the decision, if any.
+	Field string `json:"field,omitempty"`
+	// Value is the new value of the changed field, if any.
+	Value string `json:"value,omitempty"`
+	// Message is a human readable description of the decision.
+	Message string `json:"message"`
+}
+
+// Dropped is a heartbeat, which was removed from the processing pipeline.
+type Dropped struct {
+	Heartbeat Heartbeat
+	Reason    string
+}
+
+// Recorder collects decisions and dropped heartbeats while processing heartbeats.
+// Recording is disabled unless a recorder was added to the context via WithRecorder.
+type Recorder struct {
+	mu      sync.Mutex
+	dropped []Dropped
+}
+
+type recorderKey struct{}
+
+// WithRecorder returns a new context containing a recorder, which enables
+// recording decisions and dropped heartbeats.
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{}
+
+	return context.WithValue(ctx, recorderKey{}, r), r
+}
+
+// Dropped returns all heartbeats recorded as dropped.
+func (r *Recorder) Dropped() []Dropped {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Dropped(nil), r.dropped...)
+}
+
+// RecordDecision adds a decision to the trace of the heartbeat, if recording is enabled.
+func RecordDecision(ctx context.Context, h *Heartbeat, d Decision) {
+	if _, ok := ctx.Value(recorderKey{}).(*Recorder); !ok {
+		return
+	}
+
+	h.Decisions = append(h.Decisions, d)
+}
+
+// RecordDropped records a heartbeat removed from the processing pipeline
+// with the reason why, if recording is enabled. It's also counted as filtered
+// in the summary, if counting is enabled.
+func RecordDropped(ctx context.Context, h Heartbeat, reason string) {
+	CountFiltered(ctx, h)
+	recordDropped(ctx, h, reason)
+}
+
+// RecordFailed records a heartbeat removed from the processing pipeline because
+// of an error, e.g. a remote file failed to download, if recording is enabled.
+// It's also counted as failed in the summary, if counting is enabled.
+func RecordFailed(ctx context.Context, h Heartbeat, reason string) {
+	CountFailed(ctx, h)
+	recordDropped(ctx, h, reason)
+}
+
+func recordDropped(ctx context.Context, h Heartbeat, reason string) {
+	r, ok := ctx.Value(recorderKey{}).(*Recorder)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dropped = append(r.dropped, Dropped{
+		Heartbeat: h,
+		Reason:    reason,
+	})
+}
@@ -0,0 +1,61 @@
+package heartbeat_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDecision(t *testing.T) {
+	ctx, _ := heartbeat.WithRecorder(context.Background())
+
+	h := heartbeat.Heartbeat{Entity: "/tmp/main.go"}
+
+	heartbeat.RecordDecision(ctx, &h, heartbeat.Decision{
+		Stage:     "filter",
+		Rule:      "include",
+		ConfigKey: "settings.include",
+		Pattern:   "(?i)main.go$",
+		Message:   "keeping because matches include pattern",
+	})
+
+	assert.Equal(t, []heartbeat.Decision{
+		{
+			Stage:     "filter",
+			Rule:      "include",
+			ConfigKey: "settings.include",
+			Pattern:   "(?i)main.go$",
+			Message:   "keeping because matches include pattern",
+		},
+	}, h.Decisions)
+}
+
+func TestRecordDecision_RecordingDisabled(t *testing.T) {
+	h := heartbeat.Heartbeat{Entity: "/tmp/main.go"}
+
+	heartbeat.RecordDecision(context.Background(), &h, heartbeat.Decision{
+		Stage:   "project",
+		Rule:    "git-detector",
+		Message: "project set by git-detector",
+	})
+
+	assert.Nil(t, h.Decisions)
+}
+
+func TestRecordDropped(t *testing.T) {
+	ctx, recorder := heartbeat.WithRecorder(context.Background())
+
+	h := heartbeat.Heartbeat{Entity: "/tmp/main.go"}
+
+	heartbeat.RecordDropped(ctx, h, "skipping because of unknown project")
+
+	assert.Equal(t, []heartbeat.Dropped{
+		{
+			Heartbeat: h,
+			Reason:    "skipping because of unknown project",
+		},
+	}, recorder.Dropped())
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
 	"github.com/wakatime/wakatime-cli/pkg/system"
 	"github.com/wakatime/wakatime-cli/pkg/version"
 
@@ -20,40 +21,64 @@ var remoteAddressRegex = regexp.MustCompile(`(?i)^((ssh|sftp)://)+(?P<credential
 
 // Heartbeat is a structure representing activity for a user on a some entity.
 type Heartbeat struct {
-	APIKey                string     `json:"-"`
-	Branch                *string    `json:"branch,omitempty"`
-	BranchAlternate       string     `json:"-"`
-	Category              Category   `json:"category"`
-	CursorPosition        *int       `json:"cursorpos,omitempty"`
-	Dependencies          []string   `json:"dependencies,omitempty"`
-	Entity                string     `json:"entity"`
-	EntityType            EntityType `json:"type"`
-	IsUnsavedEntity       bool       `json:"-"`
-	IsWrite               *bool      `json:"is_write,omitempty"`
-	Language              *string    `json:"language,omitempty"`
-	LanguageAlternate     string     `json:"-"`
-	LineAdditions         *int       `json:"line_additions,omitempty"`
-	LineDeletions         *int       `json:"line_deletions,omitempty"`
-	LineNumber            *int       `json:"lineno,omitempty"`
-	Lines                 *int       `json:"lines,omitempty"`
-	LocalFile             string     `json:"-"`
-	LocalFileNeedsCleanup bool       `json:"-"`
-	Project               *string    `json:"project,omitempty"`
-	ProjectAlternate      string     `json:"-"`
-	ProjectFromGitRemote  bool       `json:"-"`
-	ProjectOverride       string     `json:"-"`
-	ProjectPath           string     `json:"-"`
-	ProjectPathOverride   string     `json:"-"`
-	ProjectRootCount      *int       `json:"project_root_count,omitempty"`
-	Time                  float64    `json:"time"`
-	UserAgent             string     `json:"user_agent"`
+	APIKey                string           `json:"-"`
+	Branch                *string          `json:"branch,omitempty"`
+	BranchAlternate       string           `json:"-"`
+	Category              Category         `json:"category"`
+	ContentKind           ContentKind      `json:"-"`
+	CursorPosition        *int             `json:"cursorpos,omitempty"`
+	Decisions             []Decision       `json:"-"`
+	Dependencies          []string         `json:"dependencies,omitempty"`
+	Entity                string           `json:"entity"`
+	EntityType            EntityType       `json:"type"`
+	IsCategorySet         bool             `json:"-"`
+	IsUnsavedEntity       bool             `json:"-"`
+	IsWrite               *bool            `json:"is_write,omitempty"`
+	Language              *string          `json:"language,omitempty"`
+	LanguageAlternate     string           `json:"-"`
+	LanguageSecondary     *string          `json:"secondary_language,omitempty"`
+	LineAdditions         *int             `json:"line_additions,omitempty"`
+	LineDeletions         *int             `json:"line_deletions,omitempty"`
+	LineNumber            *int             `json:"lineno,omitempty"`
+	Lines                 *int             `json:"lines,omitempty"`
+	LinguistLanguage      *string          `json:"-"`
+	LocalFile             string           `json:"-"`
+	LocalFileNeedsCleanup bool             `json:"-"`
+	Project               *string          `json:"project,omitempty"`
+	ProjectAlternate      string           `json:"-"`
+	ProjectFromGitRemote  bool             `json:"-"`
+	ProjectOverride       string           `json:"-"`
+	ProjectPath           string           `json:"-"`
+	ProjectPathOverride   string           `json:"-"`
+	ProjectRootCount      *int             `json:"project_root_count,omitempty"`
+	ProjectSettings       *ProjectSettings `json:"-"`
+	Time                  float64          `json:"time"`
+	UserAgent             string           `json:"user_agent"`
+}
+
+// ProjectSettings contains the tracking policy declared in the settings section
+// of a .wakatime-project file, which applies to heartbeats under its folder.
+type ProjectSettings struct {
+	// APIKey overrides the api key.
+	APIKey string
+	// Exclude contains additional patterns to skip heartbeats.
+	Exclude []regex.Regex
+	// File is the path of the .wakatime-project file.
+	File string
+	// HideFileNames contains additional patterns to obfuscate file names.
+	HideFileNames []regex.Regex
+	// Include contains additional patterns to keep heartbeats, overriding exclude.
+	Include []regex.Regex
 }
 
 // New creates a new instance of Heartbeat with formatted entity
-// and local file paths for file type heartbeats.
+// and local file paths for file type heartbeats. isCategorySet signals the
+// category was set by the plugin, so categories detected by the heartbeat
+// processing pipeline don't override it.
 func New(
 	branchAlternate string,
 	category Category,
+	isCategorySet bool,
 	cursorPosition *int,
 	entity string,
 	entityType EntityType,
@@ -79,6 +104,7 @@ func New(
 		CursorPosition:       cursorPosition,
 		Entity:               entity,
 		EntityType:           entityType,
+		IsCategorySet:        isCategorySet,
 		IsUnsavedEntity:      isUnsavedEntity,
 		IsWrite:              isWrite,
 		Language:             language,
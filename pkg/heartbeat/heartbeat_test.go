@@ -22,6 +22,7 @@ func TestNew(t *testing.T) {
 	h := heartbeat.New(
 		"feature/branch",
 		heartbeat.CodingCategory,
+		true,
 		heartbeat.PointerTo(12),
 		"testdata/main.go",
 		heartbeat.FileType,
@@ -49,6 +50,7 @@ func TestNew(t *testing.T) {
 		Category:            heartbeat.CodingCategory,
 		CursorPosition:      heartbeat.PointerTo(12),
 		EntityType:          heartbeat.FileType,
+		IsCategorySet:       true,
 		IsUnsavedEntity:     true,
 		IsWrite:             heartbeat.PointerTo(true),
 		Language:            heartbeat.PointerTo("Go"),
@@ -1595,6 +1595,8 @@ const (
 	LanguageZIL
 	// LanguageZimpl represents the Zimpl programming language.
 	LanguageZimpl
+	// languageEnd marks the end of the languages. It must stay last.
+	languageEnd
 )
 
 const (
@@ -4058,6 +4060,17 @@ func ParseLanguage(s string) (Language, bool) {
 	}
 }
 
+// Languages returns all known languages, except of the unknown language.
+func Languages() []Language {
+	languages := make([]Language, 0, languageEnd-1)
+
+	for l := LanguageUnknown + 1; l < languageEnd; l++ {
+		languages = append(languages, l)
+	}
+
+	return languages
+}
+
 // ParseLanguageFromChroma parses a language from a chroma lexer name.
 // Will return false as second parameter, if language could not be parsed.
 // nolint:gocyclo
@@ -981,6 +981,21 @@ func TestParseLanguageFromChroma_AllLexersSupported(t *testing.T) {
 	}
 }
 
+func TestLanguages(t *testing.T) {
+	languages := heartbeat.Languages()
+
+	assert.Equal(t, heartbeat.Language1CEnterprise, languages[0])
+	assert.Equal(t, heartbeat.LanguageZimpl, languages[len(languages)-1])
+
+	for _, language := range languages {
+		parsed, ok := heartbeat.ParseLanguage(language.String())
+		require.True(t, ok, language.String())
+
+		// go template languages are reported as go
+		assert.Equal(t, language.String(), parsed.String())
+	}
+}
+
 func TestLanguage_MarshalJSON(t *testing.T) {
 	for value, language := range languageTests() {
 		t.Run(value, func(t *testing.T) {
@@ -58,14 +58,26 @@ func Sanitize(ctx context.Context, h Heartbeat, config SanitizeConfig) Heartbeat
 	// project patterns
 	if h.Project != nil {
 		check.Patterns = config.ProjectPatterns
-		if ShouldSanitize(ctx, check) {
+		if pattern, ok := matchingPattern(ctx, check); ok {
 			h = sanitizeMetaData(h)
+
+			RecordDecision(ctx, &h, Decision{
+				Stage:     "sanitize",
+				Rule:      "hide project names",
+				ConfigKey: "settings.hide_project_names",
+				Pattern:   pattern,
+				Message:   "cursor position, line number and lines removed",
+			})
 		}
 	}
 
 	// file patterns
 	check.Patterns = config.FilePatterns
-	if ShouldSanitize(ctx, check) {
+	if h.ProjectSettings != nil {
+		check.Patterns = append(append([]regex.Regex{}, h.ProjectSettings.HideFileNames...), config.FilePatterns...)
+	}
+
+	if pattern, ok := matchingPattern(ctx, check); ok {
 		if h.EntityType == FileType {
 			h.Entity = "HIDDEN" + filepath.Ext(h.Entity)
 		} else {
@@ -81,25 +93,66 @@ func Sanitize(ctx context.Context, h Heartbeat, config SanitizeConfig) Heartbeat
 		}
 
 		h = sanitizeMetaData(h)
+
+		RecordDecision(ctx, &h, Decision{
+			Stage:     "sanitize",
+			Rule:      "hide file names",
+			ConfigKey: "settings.hide_file_names",
+			Pattern:   pattern,
+			Field:     "entity",
+			Value:     h.Entity,
+			Message:   "file name hidden",
+		})
 	}
 
 	// branch patterns
 	if h.Branch != nil {
 		check.Patterns = config.BranchPatterns
-		if ShouldSanitize(ctx, check) {
+		if pattern, ok := matchingPattern(ctx, check); ok {
 			h.Branch = nil
+
+			RecordDecision(ctx, &h, Decision{
+				Stage:     "sanitize",
+				Rule:      "hide branch names",
+				ConfigKey: "settings.hide_branch_names",
+				Pattern:   pattern,
+				Field:     "branch",
+				Message:   "branch removed",
+			})
 		}
 	}
 
 	// dependency patterns
 	if h.Dependencies != nil {
 		check.Patterns = config.DependencyPatterns
-		if ShouldSanitize(ctx, check) {
+		if pattern, ok := matchingPattern(ctx, check); ok {
 			h.Dependencies = nil
+
+			RecordDecision(ctx, &h, Decision{
+				Stage:     "sanitize",
+				Rule:      "hide dependencies",
+				ConfigKey: "settings.hide_dependencies",
+				Pattern:   pattern,
+				Field:     "dependencies",
+				Message:   "dependencies removed",
+			})
 		}
 	}
 
+	entity := h.Entity
+
 	h = hideProjectFolder(h, config.HideProjectFolder)
+	if h.Entity != entity {
+		RecordDecision(ctx, &h, Decision{
+			Stage:     "sanitize",
+			Rule:      "hide project folder",
+			ConfigKey: "settings.hide_project_folder",
+			Field:     "entity",
+			Value:     h.Entity,
+			Message:   "entity made relative to project folder",
+		})
+	}
+
 	h = hideCredentials(h)
 
 	return h
@@ -181,19 +234,27 @@ type SanitizeCheck struct {
 // against the passed in regex patterns to determine, if this heartbeat
 // should be sanitized.
 func ShouldSanitize(ctx context.Context, check SanitizeCheck) bool {
+	_, ok := matchingPattern(ctx, check)
+
+	return ok
+}
+
+// matchingPattern returns the first pattern matching the entity filepath or
+// project path of a heartbeat.
+func matchingPattern(ctx context.Context, check SanitizeCheck) (string, bool) {
 	for _, p := range check.Patterns {
 		if p.MatchString(ctx, check.Entity) {
-			return true
+			return p.String(), true
 		}
 
 		if p.MatchString(ctx, check.ProjectPath) {
-			return true
+			return p.String(), true
 		}
 
 		if p.MatchString(ctx, check.ProjectPathOverride) {
-			return true
+			return p.String(), true
 		}
 	}
 
-	return false
+	return "", false
 }
@@ -146,6 +146,19 @@ func TestSanitize_ObfuscateFile_SkipBranchIfNotMatching(t *testing.T) {
 	}, r)
 }
 
+func TestSanitize_ObfuscateFile_ProjectSettings(t *testing.T) {
+	h := testHeartbeat()
+	h.ProjectSettings = &heartbeat.ProjectSettings{
+		HideFileNames: []regex.Regex{regex.NewRegexpWrap(regexp.MustCompile(".*"))},
+	}
+
+	r := heartbeat.Sanitize(context.Background(), h, heartbeat.SanitizeConfig{})
+
+	assert.Equal(t, "HIDDEN.go", r.Entity)
+	assert.Nil(t, r.Branch)
+	assert.Nil(t, r.Dependencies)
+}
+
 func TestSanitize_ObfuscateFile_NilFields(t *testing.T) {
 	h := testHeartbeat()
 	h.Branch = nil
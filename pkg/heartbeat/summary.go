@@ -0,0 +1,190 @@
+package heartbeat
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// Summary status values of a single heartbeat.
+const (
+	SummaryStatusSent     = "sent"
+	SummaryStatusQueued   = "queued"
+	SummaryStatusFiltered = "filtered"
+	SummaryStatusRejected = "rejected"
+	SummaryStatusFailed   = "failed"
+)
+
+// Summary collects the outcome of a heartbeat command run, e.g. how many
+// heartbeats were sent to the api or queued offline. Counting is disabled
+// unless a summary was added to the context via WithSummary.
+type Summary struct {
+	mu         sync.Mutex
+	sent       int
+	queued     int
+	filtered   int
+	rejected   int
+	failed     int
+	synced     int
+	heartbeats []SummaryHeartbeat
+}
+
+// SummaryHeartbeat contains the outcome of a single heartbeat.
+type SummaryHeartbeat struct {
+	Entity   string  `json:"entity"`
+	Project  *string `json:"project"`
+	Language *string `json:"language"`
+	Branch   *string `json:"branch"`
+	Status   string  `json:"status"`
+}
+
+// SummaryResult is the json representation of a summary.
+type SummaryResult struct {
+	Sent       int                `json:"sent"`
+	Queued     int                `json:"queued"`
+	Filtered   int                `json:"filtered"`
+	Rejected   int                `json:"rejected"`
+	Failed     int                `json:"failed"`
+	Synced     int                `json:"synced"`
+	Heartbeats []SummaryHeartbeat `json:"heartbeats"`
+}
+
+type summaryKey struct{}
+
+// WithCounting initializes and returns a heartbeat handle option, which can be
+// used in a heartbeat processing pipeline right before sending heartbeats to
+// count them in the summary as sent or rejected by the api. Heartbeats, which
+// failed to send, are counted as queued if the pipeline saves them to the
+// offline queue, otherwise as failed.
+func WithCounting(queued bool) HandleOption {
+	return func(next Handle) Handle {
+		return func(ctx context.Context, hh []Heartbeat) ([]Result, error) {
+			countUnsent := CountFailed
+			if queued {
+				countUnsent = CountQueued
+			}
+
+			results, err := next(ctx, hh)
+			if err != nil {
+				countUnsent(ctx, hh...)
+
+				return results, err
+			}
+
+			for n, h := range hh {
+				switch {
+				case n >= len(results):
+					countUnsent(ctx, h)
+				case results[n].Status == http.StatusBadRequest:
+					CountRejected(ctx, h)
+				case results[n].Status < http.StatusOK || results[n].Status > 299:
+					countUnsent(ctx, h)
+				default:
+					CountSent(ctx, h)
+				}
+			}
+
+			return results, nil
+		}
+	}
+}
+
+// WithSummary returns a new context containing a summary, which enables
+// counting the outcome of processed heartbeats.
+func WithSummary(ctx context.Context) (context.Context, *Summary) {
+	s := &Summary{}
+
+	return context.WithValue(ctx, summaryKey{}, s), s
+}
+
+// Result returns the current counts and heartbeats of the summary.
+func (s *Summary) Result() SummaryResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	heartbeats := append([]SummaryHeartbeat{}, s.heartbeats...)
+
+	return SummaryResult{
+		Sent:       s.sent,
+		Queued:     s.queued,
+		Filtered:   s.filtered,
+		Rejected:   s.rejected,
+		Failed:     s.failed,
+		Synced:     s.synced,
+		Heartbeats: heartbeats,
+	}
+}
+
+// CountSent adds heartbeats sent to the api to the summary, if counting is enabled.
+func CountSent(ctx context.Context, hh ...Heartbeat) {
+	count(ctx, SummaryStatusSent, hh)
+}
+
+// CountQueued adds heartbeats saved to the offline queue to the summary, if counting is enabled.
+func CountQueued(ctx context.Context, hh ...Heartbeat) {
+	count(ctx, SummaryStatusQueued, hh)
+}
+
+// CountFiltered adds heartbeats removed from the processing pipeline to the summary,
+// if counting is enabled.
+func CountFiltered(ctx context.Context, hh ...Heartbeat) {
+	count(ctx, SummaryStatusFiltered, hh)
+}
+
+// CountRejected adds heartbeats rejected by the api to the summary, if counting is enabled.
+func CountRejected(ctx context.Context, hh ...Heartbeat) {
+	count(ctx, SummaryStatusRejected, hh)
+}
+
+// CountFailed adds heartbeats lost because of an error, e.g. failed sending
+// without offline queue, to the summary, if counting is enabled.
+func CountFailed(ctx context.Context, hh ...Heartbeat) {
+	count(ctx, SummaryStatusFailed, hh)
+}
+
+// CountSynced adds the number of heartbeats synced from the offline queue to
+// the summary, if counting is enabled.
+func CountSynced(ctx context.Context, n int) {
+	s, ok := ctx.Value(summaryKey{}).(*Summary)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.synced += n
+}
+
+func count(ctx context.Context, status string, hh []Heartbeat) {
+	s, ok := ctx.Value(summaryKey{}).(*Summary)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch status {
+	case SummaryStatusSent:
+		s.sent += len(hh)
+	case SummaryStatusQueued:
+		s.queued += len(hh)
+	case SummaryStatusFiltered:
+		s.filtered += len(hh)
+	case SummaryStatusRejected:
+		s.rejected += len(hh)
+	case SummaryStatusFailed:
+		s.failed += len(hh)
+	}
+
+	for _, h := range hh {
+		s.heartbeats = append(s.heartbeats, SummaryHeartbeat{
+			Entity:   h.Entity,
+			Project:  h.Project,
+			Language: h.Language,
+			Branch:   h.Branch,
+			Status:   status,
+		})
+	}
+}
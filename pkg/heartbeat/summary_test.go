@@ -0,0 +1,137 @@
+package heartbeat_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	ctx, summary := heartbeat.WithSummary(context.Background())
+
+	heartbeat.CountSent(ctx, heartbeat.Heartbeat{
+		Entity:   "/tmp/main.go",
+		Project:  heartbeat.PointerTo("wakatime-cli"),
+		Language: heartbeat.PointerTo("Go"),
+		Branch:   heartbeat.PointerTo("master"),
+	})
+	heartbeat.CountQueued(ctx, heartbeat.Heartbeat{Entity: "/tmp/main.py"})
+	heartbeat.RecordDropped(ctx, heartbeat.Heartbeat{Entity: "/tmp/main.js"}, "skipping because matches exclude pattern")
+	heartbeat.RecordFailed(ctx, heartbeat.Heartbeat{Entity: "ssh://192.168.1.1/main.rs"}, "failed to download remote file")
+	heartbeat.CountSynced(ctx, 3)
+
+	assert.Equal(t, heartbeat.SummaryResult{
+		Sent:     1,
+		Queued:   1,
+		Filtered: 1,
+		Failed:   1,
+		Synced:   3,
+		Heartbeats: []heartbeat.SummaryHeartbeat{
+			{
+				Entity:   "/tmp/main.go",
+				Project:  heartbeat.PointerTo("wakatime-cli"),
+				Language: heartbeat.PointerTo("Go"),
+				Branch:   heartbeat.PointerTo("master"),
+				Status:   heartbeat.SummaryStatusSent,
+			},
+			{
+				Entity: "/tmp/main.py",
+				Status: heartbeat.SummaryStatusQueued,
+			},
+			{
+				Entity: "/tmp/main.js",
+				Status: heartbeat.SummaryStatusFiltered,
+			},
+			{
+				Entity: "ssh://192.168.1.1/main.rs",
+				Status: heartbeat.SummaryStatusFailed,
+			},
+		},
+	}, summary.Result())
+}
+
+func TestWithCounting(t *testing.T) {
+	tests := map[string]struct {
+		Queued   bool
+		Expected []string
+	}{
+		"offline queue": {
+			Queued: true,
+			Expected: []string{
+				heartbeat.SummaryStatusSent,
+				heartbeat.SummaryStatusRejected,
+				heartbeat.SummaryStatusQueued,
+				heartbeat.SummaryStatusQueued,
+			},
+		},
+		"offline disabled": {
+			Expected: []string{
+				heartbeat.SummaryStatusSent,
+				heartbeat.SummaryStatusRejected,
+				heartbeat.SummaryStatusFailed,
+				heartbeat.SummaryStatusFailed,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opt := heartbeat.WithCounting(test.Queued)
+
+			handle := opt(func(_ context.Context, _ []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+				return []heartbeat.Result{
+					{
+						Status: 201,
+					},
+					{
+						Status: 400,
+					},
+					{
+						Status: 500,
+					},
+				}, nil
+			})
+
+			ctx, summary := heartbeat.WithSummary(context.Background())
+
+			_, err := handle(ctx, []heartbeat.Heartbeat{
+				{Entity: "/tmp/main.go"},
+				{Entity: "/tmp/main.py"},
+				{Entity: "/tmp/main.js"},
+				{Entity: "/tmp/main.rs"},
+			})
+			require.NoError(t, err)
+
+			result := summary.Result()
+			require.Len(t, result.Heartbeats, 4)
+
+			for i, status := range test.Expected {
+				assert.Equal(t, status, result.Heartbeats[i].Status)
+			}
+		})
+	}
+}
+
+func TestWithCounting_Err(t *testing.T) {
+	opt := heartbeat.WithCounting(false)
+
+	handle := opt(func(_ context.Context, _ []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		return nil, errors.New("failed")
+	})
+
+	ctx, summary := heartbeat.WithSummary(context.Background())
+
+	_, err := handle(ctx, []heartbeat.Heartbeat{{Entity: "/tmp/main.go"}})
+	require.Error(t, err)
+
+	result := summary.Result()
+
+	assert.Zero(t, result.Sent)
+	assert.Zero(t, result.Queued)
+	assert.Equal(t, 1, result.Failed)
+}
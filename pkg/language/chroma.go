@@ -2,116 +2,106 @@ package language
 
 import (
 	"context"
-	"fmt"
-	"io"
 	"path/filepath"
 	"sort"
 	"strings"
 
-	"github.com/wakatime/wakatime-cli/pkg/file"
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/lexer"
 	"github.com/wakatime/wakatime-cli/pkg/log"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/lexers"
-	"github.com/danwakefield/fnmatch"
 )
 
-// Max file size supporting reading from file. Default is 512Kb.
-const maxFileSize = 512000
-
 // detectChromaCustomized returns the best by filename matching lexer. Best lexer is determined
-// by customized priority.
-// If guessLanguage is true, the file content will be used to detect the language.
+// by customized priority. The returned weight is the weight of analysing the file content,
+// without the same folder file extension adjustments.
 // This is a modified implementation of chroma.lexers.internal.api:Match().
-func detectChromaCustomized(ctx context.Context, fp string, guessLanguage bool) (heartbeat.Language, float32, bool) {
-	logger := log.Extract(ctx)
-
-	_, file := filepath.Split(fp)
-	filename := filepath.Base(file)
-	matched := chroma.PrioritisedLexers{}
-
-	// First, try primary filename matches.
-	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
-		config := lexer.Config()
-		for _, glob := range config.Filenames {
-			if fnmatch.Match(glob, filename, 0) || fnmatch.Match(glob, strings.ToLower(filename), 0) {
-				matched = append(matched, lexer)
-			}
-		}
+func detectChromaCustomized(ctx context.Context, fp string) (heartbeat.Language, float32, bool) {
+	matched := matchLexers(fp)
+	if len(matched) == 0 {
+		return heartbeat.LanguageUnknown, 0, false
 	}
 
-	if len(matched) > 0 {
-		bestLexer, weight := selectByCustomizedPriority(ctx, fp, matched)
-
-		language, ok := heartbeat.ParseLanguageFromChroma(bestLexer.Config().Name)
-		if !ok {
-			logger.Warnf("failed to parse language from chroma lexer name %q", bestLexer.Config().Name)
-			return heartbeat.LanguageUnknown, 0, false
-		}
+	best := weightLexers(ctx, fp, matched)[0]
 
-		return language, weight, true
+	language, ok := heartbeat.ParseLanguageFromChroma(best.Config().Name)
+	if !ok {
+		log.Extract(ctx).Warnf("failed to parse language from chroma lexer name %q", best.Config().Name)
+		return heartbeat.LanguageUnknown, 0, false
 	}
 
-	// Next, try filename aliases.
-	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
-		config := lexer.Config()
-		for _, glob := range config.AliasFilenames {
-			if fnmatch.Match(glob, filename, 0) {
-				matched = append(matched, lexer)
-			}
-		}
-	}
+	return language, best.AnalysedWeight, true
+}
 
-	if len(matched) > 0 {
-		bestLexer, weight := selectByCustomizedPriority(ctx, fp, matched)
+// matchLexers returns the lexers matching the filename of fp. Primary filename
+// matches take precedence over filename aliases. Globs are looked up in an
+// index of the registered lexers instead of matching every glob.
+func matchLexers(fp string) chroma.PrioritisedLexers {
+	_, file := filepath.Split(fp)
+	filename := filepath.Base(file)
 
-		language, ok := heartbeat.ParseLanguageFromChroma(bestLexer.Config().Name)
-		if !ok {
-			logger.Warnf("failed to parse language from chroma lexer name %q", bestLexer.Config().Name)
-			return heartbeat.LanguageUnknown, 0, false
-		}
+	filenames, aliases := loadLexerIndex()
 
-		return language, weight, true
+	// First, try primary filename matches.
+	if matched := filenames.match(filename, strings.ToLower(filename)); len(matched) > 0 {
+		return matched
 	}
 
-	if !guessLanguage {
-		return heartbeat.LanguageUnknown, 0, false
-	}
+	// Next, try filename aliases.
+	return aliases.match(filename)
+}
+
+// detectChromaAnalyse returns the language of the lexer best matching the
+// file content.
+func detectChromaAnalyse(ctx context.Context, fp string) (heartbeat.Language, bool) {
+	logger := log.Extract(ctx)
 
-	// Finally, try matching by file content.
 	head, err := fileHead(ctx, fp)
 	if err != nil {
 		logger.Warnf("failed to load head from file %q: %s", fp, err)
-		return heartbeat.LanguageUnknown, 0, false
+		return heartbeat.LanguageUnknown, false
 	}
 
 	if len(head) == 0 {
-		return heartbeat.LanguageUnknown, 0, false
+		return heartbeat.LanguageUnknown, false
 	}
 
 	if lexer := lexers.Analyse(string(head)); lexer != nil {
 		language, ok := heartbeat.ParseLanguageFromChroma(lexer.Config().Name)
 		if !ok {
 			logger.Warnf("failed to parse language from chroma lexer name %q", lexer.Config().Name)
-			return heartbeat.LanguageUnknown, 0, false
+			return heartbeat.LanguageUnknown, false
 		}
 
-		return language, 0, true
+		return language, true
 	}
 
-	return heartbeat.LanguageUnknown, 0, false
+	return heartbeat.LanguageUnknown, false
+}
+
+// registerLexers registers the custom lexers, if not done yet. Registration is
+// deferred until a language is detected, so commands not detecting languages
+// don't pay for it.
+func registerLexers(ctx context.Context) {
+	if err := lexer.RegisterAll(); err != nil {
+		log.Extract(ctx).Errorf("failed to register custom lexers: %s", err)
+	}
 }
 
-// weightedLexer is a lexer with priority and weight.
+// weightedLexer is a lexer with priority and weight. AnalysedWeight is the
+// weight of analysing the file content only.
 type weightedLexer struct {
 	chroma.Lexer
-	Weight   float32
-	Priority float32
+	Weight         float32
+	AnalysedWeight float32
+	Priority       float32
 }
 
-// selectByCustomizedPriority selects the best matching lexer by customized priority evaluation.
-func selectByCustomizedPriority(ctx context.Context, fp string, lexers chroma.PrioritisedLexers) (chroma.Lexer, float32) {
+// weightLexers weights the lexers by customized priority evaluation. The best
+// matching lexer is returned first.
+func weightLexers(ctx context.Context, fp string, lexers chroma.PrioritisedLexers) []weightedLexer {
 	logger := log.Extract(ctx)
 
 	sort.Slice(lexers, func(i, j int) bool {
@@ -151,9 +141,10 @@ func selectByCustomizedPriority(ctx context.Context, fp string, lexers chroma.Pr
 
 		if p, ok := priority(cfg.Name); ok {
 			weighted = append(weighted, weightedLexer{
-				Lexer:    lexer,
-				Priority: p,
-				Weight:   weight,
+				Lexer:          lexer,
+				Priority:       p,
+				Weight:         weight,
+				AnalysedWeight: weight,
 			})
 
 			continue
@@ -161,9 +152,10 @@ func selectByCustomizedPriority(ctx context.Context, fp string, lexers chroma.Pr
 
 		if cfg.Name == "Matlab" {
 			weighted = append(weighted, weightedLexer{
-				Lexer:    lexer,
-				Priority: cfg.Priority,
-				Weight:   matlabWeight(weight, extensions),
+				Lexer:          lexer,
+				Priority:       cfg.Priority,
+				Weight:         matlabWeight(weight, extensions),
+				AnalysedWeight: weight,
 			})
 
 			continue
@@ -171,18 +163,20 @@ func selectByCustomizedPriority(ctx context.Context, fp string, lexers chroma.Pr
 
 		if cfg.Name == "Objective-C" {
 			weighted = append(weighted, weightedLexer{
-				Lexer:    lexer,
-				Priority: cfg.Priority,
-				Weight:   objectiveCWeight(weight, extensions),
+				Lexer:          lexer,
+				Priority:       cfg.Priority,
+				Weight:         objectiveCWeight(weight, extensions),
+				AnalysedWeight: weight,
 			})
 
 			continue
 		}
 
 		weighted = append(weighted, weightedLexer{
-			Lexer:    lexer,
-			Priority: cfg.Priority,
-			Weight:   weight,
+			Lexer:          lexer,
+			Priority:       cfg.Priority,
+			Weight:         weight,
+			AnalysedWeight: weight,
 		})
 	}
 
@@ -201,33 +195,13 @@ func selectByCustomizedPriority(ctx context.Context, fp string, lexers chroma.Pr
 		return weighted[i].Lexer.Config().Name > weighted[j].Lexer.Config().Name
 	})
 
-	return weighted[0].Lexer, weighted[0].Weight
-}
-
-// fileHead returns the first `maxFileSize` bytes of the file's content.
-func fileHead(ctx context.Context, filepath string) ([]byte, error) {
-	logger := log.Extract(ctx)
-
-	f, err := file.OpenNoLock(filepath) // nolint:gosec
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %s", err)
-	}
-
-	defer func() {
-		if err := f.Close(); err != nil {
-			logger.Debugf("failed to close file '%s': %s", filepath, err)
-		}
-	}()
-
-	data, err := io.ReadAll(io.LimitReader(f, maxFileSize))
-	if err != nil && err != io.EOF {
-		return nil, fmt.Errorf("failed to read bytes from file: %s", err)
-	}
-
-	return data, nil
+	return weighted
 }
 
 // objectiveCWeight determines the weight of objective-c by the provided same folder file extensions.
+// Modelines and content heuristics of .m and .h files are applied first, so this only breaks
+// the tie with matlab when none matched: objective-c wins next to .h files, unless there are
+// .mat files too.
 func objectiveCWeight(weight float32, extensions []string) float32 {
 	var matFileExists bool
 
@@ -255,6 +229,7 @@ func objectiveCWeight(weight float32, extensions []string) float32 {
 }
 
 // matlabWeight determines the weight of matlab by the provided same folder file extensions.
+// See objectiveCWeight for which one wins.
 func matlabWeight(weight float32, extensions []string) float32 {
 	for _, e := range extensions {
 		if e == ".mat" {
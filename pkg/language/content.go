@@ -0,0 +1,220 @@
+package language
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+const (
+	// maxSniffSize is the maximum number of bytes read to sniff the content
+	// of a file. Default is 64Kb.
+	maxSniffSize = 64 * 1024
+	// maxBinarySniffSize is the number of bytes searched for NUL bytes, same
+	// as git does.
+	maxBinarySniffSize = 8000
+	// maxGeneratedHeaderLines is the number of lines searched for a
+	// generated file header.
+	maxGeneratedHeaderLines = 20
+	// minMinifiedSize is the minimum size of a file detected as minified.
+	minMinifiedSize = 1024
+	// minMinifiedLineLength is the minimum average line length of a file
+	// detected as minified.
+	minMinifiedLineLength = 500
+)
+
+// binaryMagicNumbers are the signatures at the start of common binary files.
+// nolint:gochecknoglobals
+var binaryMagicNumbers = [][]byte{
+	[]byte("\x7fELF"),             // elf executable
+	[]byte("\x89PNG\r\n\x1a\n"),   // png image
+	[]byte("\xca\xfe\xba\xbe"),    // java class, mach-o universal binary
+	[]byte("\xcf\xfa\xed\xfe"),    // mach-o 64-bit executable
+	[]byte("\xfe\xed\xfa\xcf"),    // mach-o 64-bit executable, big endian
+	[]byte("\xff\xd8\xff"),        // jpeg image
+	[]byte("\x00asm"),             // webassembly
+	[]byte("\x1f\x8b"),            // gzip archive
+	[]byte("%PDF-"),               // pdf document
+	[]byte("GIF87a"),              // gif image
+	[]byte("GIF89a"),              // gif image
+	[]byte("PK\x03\x04"),          // zip archive, jar, docx
+	[]byte("SQLite format 3\x00"), // sqlite database
+}
+
+// generatedHeaderRegex matches comment lines marking a file as generated, like
+// the Go convention, @generated markers and .NET auto-generated headers.
+var generatedHeaderRegex = regexp.MustCompile(
+	`^\s*(?://+|#+|/\*+|\*|--|;+)\s*(?:Code generated .* DO NOT EDIT\.?|@generated\b|<auto-generated\b)`,
+)
+
+// SniffConfig defines the handling of heartbeats of files detected as binary,
+// generated or minified by sniffing their content.
+type SniffConfig struct {
+	Binary    AttributeAction
+	Generated AttributeAction
+	Minified  AttributeAction
+}
+
+// WithContentSniffing initializes and returns a heartbeat handle option, which
+// can be used in a heartbeat processing pipeline to detect binary, generated
+// and minified files from their content. The kind of content is set on the
+// heartbeat, so later stages skip counting lines and parsing dependencies of
+// them. Heartbeats can be skipped or recategorized by kind of content.
+func WithContentSniffing(config SniffConfig) heartbeat.HandleOption {
+	return func(next heartbeat.Handle) heartbeat.Handle {
+		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			logger := log.Extract(ctx)
+			logger.Debugln("execute content sniffing")
+
+			var filtered []heartbeat.Heartbeat
+
+			for _, h := range hh {
+				if h.EntityType != heartbeat.FileType || h.IsUnsavedEntity {
+					filtered = append(filtered, h)
+					continue
+				}
+
+				fp := h.Entity
+				if h.LocalFile != "" {
+					fp = h.LocalFile
+				}
+
+				h.ContentKind = sniffContent(ctx, fp)
+
+				var (
+					action    AttributeAction
+					configKey string
+				)
+
+				switch h.ContentKind {
+				case heartbeat.BinaryContent:
+					action, configKey = config.Binary, "settings.content_binary"
+				case heartbeat.GeneratedContent:
+					action, configKey = config.Generated, "settings.content_generated"
+				case heartbeat.MinifiedContent:
+					action, configKey = config.Minified, "settings.content_minified"
+				default:
+					filtered = append(filtered, h)
+					continue
+				}
+
+				logger.Debugf("detected %s content in file %q", h.ContentKind, fp)
+
+				if action.Skip {
+					logger.Debugf("skipping because file has %s content", h.ContentKind)
+					heartbeat.RecordDropped(ctx, h, fmt.Sprintf("%s content", h.ContentKind))
+					continue
+				}
+
+				// only a category not set by the plugin or a previous stage is overridden
+				if action.Category != nil && !h.IsCategorySet {
+					h.Category = *action.Category
+					h.IsCategorySet = true
+
+					heartbeat.RecordDecision(ctx, &h, heartbeat.Decision{
+						Stage:     "language",
+						Rule:      h.ContentKind.String() + " content",
+						ConfigKey: configKey,
+						Field:     "category",
+						Value:     h.Category.String(),
+						Message:   fmt.Sprintf("category set by %s content", h.ContentKind),
+					})
+				}
+
+				filtered = append(filtered, h)
+			}
+
+			return next(ctx, filtered)
+		}
+	}
+}
+
+// sniffContent detects whether the file contains binary data, is generated or
+// minified. Files which cannot be read are regarded as text.
+func sniffContent(ctx context.Context, fp string) heartbeat.ContentKind {
+	head, err := readFileHead(ctx, fp, maxSniffSize)
+	if err != nil {
+		log.Extract(ctx).Debugf("failed to read head from file %q: %s", fp, err)
+		return heartbeat.TextContent
+	}
+
+	switch {
+	case isBinary(head):
+		return heartbeat.BinaryContent
+	case isGenerated(head):
+		return heartbeat.GeneratedContent
+	case isMinified(head):
+		return heartbeat.MinifiedContent
+	default:
+		return heartbeat.TextContent
+	}
+}
+
+// isBinary returns true if data starts with the magic number of a binary file
+// format, is a windows executable or contains a NUL byte.
+func isBinary(data []byte) bool {
+	for _, magic := range binaryMagicNumbers {
+		if bytes.HasPrefix(data, magic) {
+			return true
+		}
+	}
+
+	if isPortableExecutable(data) {
+		return true
+	}
+
+	if len(data) > maxBinarySniffSize {
+		data = data[:maxBinarySniffSize]
+	}
+
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// isPortableExecutable returns true if data starts with the MZ header of a
+// windows executable, whose e_lfanew field points to the PE signature. The MZ
+// magic number alone also matches text files starting with "MZ".
+func isPortableExecutable(data []byte) bool {
+	if len(data) < 0x40 || !bytes.HasPrefix(data, []byte("MZ")) {
+		return false
+	}
+
+	offset := binary.LittleEndian.Uint32(data[0x3c:0x40])
+	if uint64(offset)+4 > uint64(len(data)) {
+		return false
+	}
+
+	return bytes.Equal(data[offset:offset+4], []byte("PE\x00\x00"))
+}
+
+// isGenerated returns true if any of the first lines of data is a generated
+// file header.
+func isGenerated(data []byte) bool {
+	lines := bytes.SplitN(data, []byte("\n"), maxGeneratedHeaderLines+1)
+	if len(lines) > maxGeneratedHeaderLines {
+		lines = lines[:maxGeneratedHeaderLines]
+	}
+
+	for _, line := range lines {
+		if generatedHeaderRegex.Match(bytes.TrimRight(line, "\r")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMinified returns true if the average line length of data is very long.
+func isMinified(data []byte) bool {
+	if len(data) < minMinifiedSize {
+		return false
+	}
+
+	lines := bytes.Count(bytes.TrimRight(data, "\n"), []byte("\n")) + 1
+
+	return len(data)/lines >= minMinifiedLineLength
+}
@@ -0,0 +1,38 @@
+package language
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPortableExecutable(t *testing.T) {
+	tests := map[string]struct {
+		Data     string
+		Expected bool
+	}{
+		"pe executable": {
+			Data:     "MZ" + strings.Repeat("x", 0x3a) + "\x40\x00\x00\x00PE\x00\x00",
+			Expected: true,
+		},
+		"dos executable": {
+			Data: "MZ" + strings.Repeat("x", 0x3a) + "\x40\x00\x00\x00NE\x00\x00",
+		},
+		"offset exceeds data": {
+			Data: "MZ" + strings.Repeat("x", 0x3a) + "\xff\x00\x00\x00PE\x00\x00",
+		},
+		"text starting with MZ": {
+			Data: "MZ is the signature of " + strings.Repeat("dos executables. ", 10),
+		},
+		"short": {
+			Data: "MZ",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, isPortableExecutable([]byte(test.Data)))
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package language_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/language"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContentSniffing(t *testing.T) {
+	tests := map[string]struct {
+		Filename string
+		Content  string
+		Expected heartbeat.ContentKind
+	}{
+		"text": {
+			Filename: "main.go",
+			Content:  "package main\n\nfunc main() {}\n",
+			Expected: heartbeat.TextContent,
+		},
+		"empty": {
+			Filename: "empty.txt",
+			Expected: heartbeat.TextContent,
+		},
+		"nul byte": {
+			Filename: "data.txt",
+			Content:  "some\x00data",
+			Expected: heartbeat.BinaryContent,
+		},
+		"png magic number": {
+			Filename: "image.js",
+			Content:  "\x89PNG\r\n\x1a\nrest",
+			Expected: heartbeat.BinaryContent,
+		},
+		"text starting with MZ": {
+			Filename: "notes.txt",
+			Content:  "MZ is the signature of " + strings.Repeat("dos executables. ", 10),
+			Expected: heartbeat.TextContent,
+		},
+		"go generated header": {
+			Filename: "model.pb.go",
+			Content:  "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage model\n",
+			Expected: heartbeat.GeneratedContent,
+		},
+		"generated marker in block comment": {
+			Filename: "bundle.js",
+			Content:  "/**\n * @generated SignedSource<<abc>>\n */\nconst a = 1;\n",
+			Expected: heartbeat.GeneratedContent,
+		},
+		"dotnet auto-generated header": {
+			Filename: "Reference.cs",
+			Content:  "//------\n// <auto-generated>\n//     This code was generated by a tool.\n",
+			Expected: heartbeat.GeneratedContent,
+		},
+		"generated text outside comment": {
+			Filename: "notes.md",
+			Content:  "Files containing @generated are skipped.\n",
+			Expected: heartbeat.TextContent,
+		},
+		"minified": {
+			Filename: "app.min.js",
+			Content:  strings.Repeat("var a=function(){return 1};", 100) + "\n",
+			Expected: heartbeat.MinifiedContent,
+		},
+		"long lines under min size": {
+			Filename: "short.js",
+			Content:  strings.Repeat("a", 600),
+			Expected: heartbeat.TextContent,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fp := filepath.Join(t.TempDir(), test.Filename)
+
+			err := os.WriteFile(fp, []byte(test.Content), 0600)
+			require.NoError(t, err)
+
+			opt := language.WithContentSniffing(language.SniffConfig{})
+
+			h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+				require.Len(t, hh, 1)
+
+				assert.Equal(t, test.Expected, hh[0].ContentKind)
+				assert.Equal(t, heartbeat.CodingCategory, hh[0].Category)
+
+				return []heartbeat.Result{}, nil
+			})
+
+			_, err = h(context.Background(), []heartbeat.Heartbeat{
+				{
+					Category:   heartbeat.CodingCategory,
+					Entity:     fp,
+					EntityType: heartbeat.FileType,
+				},
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithContentSniffing_Actions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	binary := filepath.Join(tmpDir, "app.exe")
+	err := os.WriteFile(binary, []byte("MZ\x90\x00"), 0600)
+	require.NoError(t, err)
+
+	generated := filepath.Join(tmpDir, "parser.go")
+	err = os.WriteFile(generated, []byte("// Code generated by goyacc. DO NOT EDIT.\n"), 0600)
+	require.NoError(t, err)
+
+	building := heartbeat.BuildingCategory
+
+	opt := language.WithContentSniffing(language.SniffConfig{
+		Binary:    language.AttributeAction{Skip: true},
+		Generated: language.AttributeAction{Category: &building},
+	})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 3)
+
+		assert.Equal(t, generated, hh[0].Entity)
+		assert.Equal(t, heartbeat.BuildingCategory, hh[0].Category)
+
+		// coding category set by the plugin is kept
+		assert.Equal(t, generated, hh[1].Entity)
+		assert.Equal(t, heartbeat.CodingCategory, hh[1].Category)
+
+		assert.Equal(t, "https://wakatime.com", hh[2].Entity)
+		assert.Equal(t, heartbeat.TextContent, hh[2].ContentKind)
+
+		return []heartbeat.Result{
+			{
+				Status: 201,
+			},
+		}, nil
+	})
+
+	_, err = h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Category:   heartbeat.CodingCategory,
+			Entity:     binary,
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Category:   heartbeat.CodingCategory,
+			Entity:     generated,
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Category:      heartbeat.CodingCategory,
+			Entity:        generated,
+			EntityType:    heartbeat.FileType,
+			IsCategorySet: true,
+		},
+		{
+			Category:   heartbeat.BrowsingCategory,
+			Entity:     "https://wakatime.com",
+			EntityType: heartbeat.URLType,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestWithContentSniffing_LocalFile(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "archive.gz")
+
+	err := os.WriteFile(fp, []byte("\x1f\x8b\x08"), 0600)
+	require.NoError(t, err)
+
+	opt := language.WithContentSniffing(language.SniffConfig{})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 1)
+
+		assert.Equal(t, heartbeat.BinaryContent, hh[0].ContentKind)
+
+		return []heartbeat.Result{}, nil
+	})
+
+	_, err = h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Entity:     "ssh://192.168.1.1/path/to/remote/archive.gz",
+			EntityType: heartbeat.FileType,
+			LocalFile:  fp,
+		},
+	})
+	require.NoError(t, err)
+}
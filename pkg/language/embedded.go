@@ -0,0 +1,242 @@
+package language
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+var (
+	embeddedTagRegex       = regexp.MustCompile(`(?is)<(script|style|template)\b([^>]*)>`)
+	embeddedAttributeRegex = regexp.MustCompile(`(?i)\b(lang|type)\s*=\s*["']?([^"'\s>]+)`)
+	markdownFenceRegex     = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*\\{?\\.?([^\\s`{}]*)")
+)
+
+// embeddedBlock is a block of code in another language within a file.
+type embeddedBlock struct {
+	Start    int
+	End      int
+	Language heartbeat.Language
+}
+
+// detectEmbedded detects the language of the embedded block of code at the
+// cursor position of multi-language files, e.g. the <script lang="ts"> block
+// of a Vue file or a code fence of a Markdown file. The cursor position is
+// the character offset from the start of the file.
+func detectEmbedded(ctx context.Context, fp string, cursorPosition int) (heartbeat.Language, bool) {
+	var find func(string) []embeddedBlock
+
+	switch strings.ToLower(filepath.Ext(fp)) {
+	case ".astro", ".htm", ".html", ".svelte", ".vue":
+		find = findEmbeddedTags
+	case ".markdown", ".md", ".mdx", ".rmd":
+		find = findMarkdownFences
+	default:
+		return heartbeat.LanguageUnknown, false
+	}
+
+	head, err := fileHead(ctx, fp)
+	if err != nil {
+		log.Extract(ctx).Debugf("failed to load head from file %q: %s", fp, err)
+		return heartbeat.LanguageUnknown, false
+	}
+
+	text := string(head)
+	offset := byteOffset(text, cursorPosition)
+
+	if offset < 0 {
+		return heartbeat.LanguageUnknown, false
+	}
+
+	for _, block := range find(text) {
+		if offset >= block.Start && offset <= block.End {
+			return block.Language, true
+		}
+	}
+
+	return heartbeat.LanguageUnknown, false
+}
+
+// byteOffset converts a character offset to a byte offset of text. It returns
+// -1 if the offset exceeds text.
+func byteOffset(text string, chars int) int {
+	if chars < 0 {
+		return -1
+	}
+
+	offset := 0
+
+	for i := 0; i < chars; i++ {
+		if offset >= len(text) {
+			return -1
+		}
+
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+
+	return offset
+}
+
+// findEmbeddedTags returns the script, style and template blocks of html based
+// files and the frontmatter of Astro files.
+func findEmbeddedTags(text string) []embeddedBlock {
+	var blocks []embeddedBlock
+
+	if strings.HasPrefix(text, "---\n") {
+		// astro component script
+		if end := strings.Index(text[4:], "\n---"); end >= 0 {
+			blocks = append(blocks, embeddedBlock{
+				Start:    4,
+				End:      4 + end,
+				Language: heartbeat.LanguageTypeScript,
+			})
+		}
+	}
+
+	for _, match := range embeddedTagRegex.FindAllStringSubmatchIndex(text, -1) {
+		tag := strings.ToLower(text[match[2]:match[3]])
+		attrs := text[match[4]:match[5]]
+
+		end := strings.Index(strings.ToLower(text[match[1]:]), "</"+tag)
+		if end < 0 {
+			end = len(text) - match[1]
+		}
+
+		language, ok := embeddedTagLanguage(tag, attrs)
+		if !ok {
+			continue
+		}
+
+		blocks = append(blocks, embeddedBlock{
+			Start:    match[1],
+			End:      match[1] + end,
+			Language: language,
+		})
+	}
+
+	return blocks
+}
+
+// embeddedTagLanguage returns the language of a script, style or template tag
+// from its lang or type attribute.
+func embeddedTagLanguage(tag, attrs string) (heartbeat.Language, bool) {
+	var lang string
+
+	for _, match := range embeddedAttributeRegex.FindAllStringSubmatch(attrs, -1) {
+		if strings.EqualFold(match[1], "lang") || lang == "" {
+			lang = strings.ToLower(match[2])
+		}
+	}
+
+	switch tag {
+	case "script":
+		switch lang {
+		case "", "module", "text/javascript", "application/javascript", "js", "jsx":
+			return heartbeat.LanguageJavaScript, true
+		case "ts", "text/typescript", "application/typescript":
+			return heartbeat.LanguageTypeScript, true
+		case "tsx":
+			return heartbeat.LanguageTSX, true
+		case "coffee", "text/coffeescript":
+			return heartbeat.LanguageCoffeeScript, true
+		case "application/json", "application/ld+json", "importmap":
+			return heartbeat.LanguageJSON, true
+		}
+	case "style":
+		switch lang {
+		case "", "css", "text/css", "postcss":
+			return heartbeat.LanguageCSS, true
+		case "styl":
+			return heartbeat.LanguageStylus, true
+		}
+	case "template":
+		// html templates are the language of the file
+		if lang == "" || lang == "html" {
+			return heartbeat.LanguageUnknown, false
+		}
+	}
+
+	return heartbeat.ParseLanguage(lang)
+}
+
+// findMarkdownFences returns the fenced code blocks with an info string of
+// markdown files.
+func findMarkdownFences(text string) []embeddedBlock {
+	var (
+		blocks []embeddedBlock
+		open   string
+		block  embeddedBlock
+		ok     bool
+		offset int
+	)
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		start := offset
+		offset += len(line)
+
+		match := markdownFenceRegex.FindStringSubmatch(line)
+
+		if open != "" {
+			// closing fence uses the same character and is at least as long
+			if match != nil && match[2] == "" && match[1][0] == open[0] && len(match[1]) >= len(open) {
+				if ok {
+					block.End = start
+					blocks = append(blocks, block)
+				}
+
+				open = ""
+			}
+
+			continue
+		}
+
+		if match == nil {
+			continue
+		}
+
+		open = match[1]
+		block = embeddedBlock{Start: offset}
+		block.Language, ok = parseFenceLanguage(match[2])
+	}
+
+	if open != "" && ok {
+		block.End = len(text)
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+// parseFenceLanguage parses the language of the info string of a code fence.
+func parseFenceLanguage(info string) (heartbeat.Language, bool) {
+	switch strings.ToLower(info) {
+	case "":
+		return heartbeat.LanguageUnknown, false
+	case "console", "shell", "sh", "zsh":
+		return heartbeat.LanguageBash, true
+	case "golang":
+		return heartbeat.LanguageGo, true
+	case "js":
+		return heartbeat.LanguageJavaScript, true
+	case "kt":
+		return heartbeat.LanguageKotlin, true
+	case "py":
+		return heartbeat.LanguagePython, true
+	case "rb":
+		return heartbeat.LanguageRuby, true
+	case "rs":
+		return heartbeat.LanguageRust, true
+	case "ts":
+		return heartbeat.LanguageTypeScript, true
+	case "yml":
+		return heartbeat.LanguageYAML, true
+	default:
+		return heartbeat.ParseLanguage(info)
+	}
+}
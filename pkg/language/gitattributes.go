@@ -0,0 +1,346 @@
+package language
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/glob"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+const (
+	// maxAttributesFileSize is the maximum size of a .gitattributes file read.
+	maxAttributesFileSize = 1024 * 1024
+	// maxAttributesDepth is the maximum number of folders walked up to find the
+	// root folder of the git repository.
+	maxAttributesDepth = 100
+)
+
+// AttributesConfig defines the handling of heartbeats for files marked as
+// generated, vendored or documentation in .gitattributes files.
+type AttributesConfig struct {
+	Documentation AttributeAction
+	Generated     AttributeAction
+	Vendored      AttributeAction
+}
+
+// AttributeAction is the action taken on heartbeats of marked files.
+type AttributeAction struct {
+	// Skip drops the heartbeats.
+	Skip bool
+	// Category overrides the category of the heartbeats, if set.
+	Category *heartbeat.Category
+}
+
+// linguistAttributes contains the linguist attributes of a file.
+type linguistAttributes struct {
+	Language      string
+	Documentation bool
+	Generated     bool
+	Vendored      bool
+}
+
+// WithAttributes initializes and returns a heartbeat handle option, which
+// can be used in a heartbeat processing pipeline to skip or recategorize
+// heartbeats of files marked as generated, vendored or documentation via the
+// linguist attributes of .gitattributes files:
+//
+//	dist/** linguist-generated
+//	third_party/** linguist-vendored
+//	docs/** linguist-documentation
+func WithAttributes(config AttributesConfig) heartbeat.HandleOption {
+	return func(next heartbeat.Handle) heartbeat.Handle {
+		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			logger := log.Extract(ctx)
+			logger.Debugln("execute linguist attributes")
+
+			var filtered []heartbeat.Heartbeat
+
+			for _, h := range hh {
+				if h.EntityType != heartbeat.FileType || h.IsRemote() {
+					filtered = append(filtered, h)
+					continue
+				}
+
+				attrs := readLinguistAttributes(ctx, h.Entity)
+
+				// kept for language detection, so attributes are read once
+				h.LinguistLanguage = heartbeat.PointerTo(attrs.Language)
+
+				actions := []struct {
+					Attribute string
+					Marked    bool
+					Action    AttributeAction
+				}{
+					{Attribute: "linguist-generated", Marked: attrs.Generated, Action: config.Generated},
+					{Attribute: "linguist-vendored", Marked: attrs.Vendored, Action: config.Vendored},
+					{Attribute: "linguist-documentation", Marked: attrs.Documentation, Action: config.Documentation},
+				}
+
+				var skipped bool
+
+				for _, a := range actions {
+					if !a.Marked {
+						continue
+					}
+
+					if a.Action.Skip {
+						logger.Debugf("skipping because file is marked %s in .gitattributes", a.Attribute)
+						heartbeat.RecordDropped(ctx, h, "marked "+a.Attribute+" in .gitattributes")
+
+						skipped = true
+
+						break
+					}
+
+					// only a category not set by the plugin or a previous stage is overridden
+					if a.Action.Category != nil && !h.IsCategorySet {
+						h.Category = *a.Action.Category
+						h.IsCategorySet = true
+
+						heartbeat.RecordDecision(ctx, &h, heartbeat.Decision{
+							Stage:     "language",
+							Rule:      a.Attribute,
+							ConfigKey: ".gitattributes",
+							Field:     "category",
+							Value:     h.Category.String(),
+							Message:   "category set by .gitattributes",
+						})
+					}
+				}
+
+				if !skipped {
+					filtered = append(filtered, h)
+				}
+			}
+
+			return next(ctx, filtered)
+		}
+	}
+}
+
+// detectAttributesLanguage returns the language of the linguist-language
+// attribute of the heartbeat's file. Attributes are only read, if not done
+// yet by WithAttributes.
+func detectAttributesLanguage(ctx context.Context, h heartbeat.Heartbeat) (heartbeat.Language, bool) {
+	var name string
+
+	if h.LinguistLanguage != nil {
+		name = *h.LinguistLanguage
+	} else {
+		name = readLinguistAttributes(ctx, h.Entity).Language
+	}
+
+	if name == "" {
+		return heartbeat.LanguageUnknown, false
+	}
+
+	language, ok := heartbeat.ParseLanguage(name)
+	if !ok {
+		log.Extract(ctx).Debugf("unknown linguist-language %q in .gitattributes", name)
+		return heartbeat.LanguageUnknown, false
+	}
+
+	return language, true
+}
+
+// readLinguistAttributes reads the linguist attributes of the file from the
+// .gitattributes files of its folder and all parent folders up to the root
+// folder of the git repository, and from info/attributes of the git folder. Same as git,
+// attributes of deeper files take precedence. Files outside of a git
+// repository have no attributes.
+func readLinguistAttributes(ctx context.Context, fp string) linguistAttributes {
+	var attrs linguistAttributes
+
+	if fp == "" || !filepath.IsAbs(fp) {
+		return attrs
+	}
+
+	var (
+		dirs []string
+		root string
+	)
+
+	dir := filepath.Dir(fp)
+
+	for i := 0; i < maxAttributesDepth; i++ {
+		dirs = append(dirs, dir)
+
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			root = dir
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+
+		dir = parent
+	}
+
+	if root == "" {
+		return attrs
+	}
+
+	// parent folders first, so deeper files take precedence
+	for i := len(dirs) - 1; i >= 0; i-- {
+		applyAttributesFile(ctx, &attrs, filepath.Join(dirs[i], ".gitattributes"), dirs[i], fp)
+	}
+
+	if gitdir, ok := resolveCommonGitdir(filepath.Join(root, ".git")); ok {
+		applyAttributesFile(ctx, &attrs, filepath.Join(gitdir, "info", "attributes"), root, fp)
+	}
+
+	return attrs
+}
+
+// resolveCommonGitdir returns the git folder containing info/attributes. If
+// .git is a file, like in worktrees and submodules, the folder is read from
+// its gitdir line. Worktrees share the folder of the main repository, which
+// is read from the commondir file.
+func resolveCommonGitdir(dotGit string) (string, bool) {
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", false
+	}
+
+	if info.IsDir() {
+		return dotGit, true
+	}
+
+	data, err := os.ReadFile(dotGit) // nolint:gosec
+	if err != nil {
+		return "", false
+	}
+
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+
+	gitdir = strings.TrimSpace(gitdir)
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(filepath.Dir(dotGit), gitdir)
+	}
+
+	data, err = os.ReadFile(filepath.Join(gitdir, "commondir")) // nolint:gosec
+	if err != nil {
+		return gitdir, true
+	}
+
+	commondir := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(commondir) {
+		commondir = filepath.Join(gitdir, commondir)
+	}
+
+	return commondir, true
+}
+
+// applyAttributesFile applies the linguist attributes of the lines of the
+// attributes file matching fp. Patterns are relative to dir.
+func applyAttributesFile(ctx context.Context, attrs *linguistAttributes, attributesFile, dir, fp string) {
+	info, err := os.Stat(attributesFile)
+	if err != nil || info.IsDir() {
+		return
+	}
+
+	logger := log.Extract(ctx)
+
+	if info.Size() > maxAttributesFileSize {
+		logger.Debugf("skipping attributes file %q exceeding maximum size", attributesFile)
+		return
+	}
+
+	rel, err := filepath.Rel(dir, fp)
+	if err != nil {
+		return
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	f, err := os.Open(attributesFile) // nolint:gosec
+	if err != nil {
+		logger.Debugf("failed to open attributes file %q: %s", attributesFile, err)
+		return
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Debugf("failed to close attributes file %q: %s", attributesFile, err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], "[attr]") {
+			continue
+		}
+
+		if !matchAttributesPattern(fields[0], rel) {
+			continue
+		}
+
+		for _, attr := range fields[1:] {
+			attrs.apply(attr)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		logger.Debugf("failed to read attributes file %q: %s", attributesFile, err)
+	}
+}
+
+// apply applies a single attribute, which is set (attr), unset (-attr),
+// unspecified (!attr) or set to a value (attr=value).
+func (a *linguistAttributes) apply(attr string) {
+	var (
+		name  = strings.TrimLeft(attr, "-!")
+		value = !strings.HasPrefix(attr, "-") && !strings.HasPrefix(attr, "!")
+		text  string
+	)
+
+	if k, v, ok := strings.Cut(name, "="); ok {
+		name, text = k, v
+		value = !strings.EqualFold(v, "false")
+	}
+
+	switch name {
+	case "linguist-language":
+		if strings.HasPrefix(attr, "-") || strings.HasPrefix(attr, "!") {
+			a.Language = ""
+			return
+		}
+
+		a.Language = text
+	case "linguist-documentation":
+		a.Documentation = value
+	case "linguist-generated":
+		a.Generated = value
+	case "linguist-vendored":
+		a.Vendored = value
+	}
+}
+
+// matchAttributesPattern matches a slash separated path relative to the
+// folder of the attributes file against a gitattributes pattern. Patterns
+// without a slash match the file name in any folder.
+func matchAttributesPattern(pattern, rel string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		// patterns matching folders don't match files within them
+		return false
+	}
+
+	if !strings.Contains(pattern, "/") {
+		return glob.Match(pattern, path.Base(rel))
+	}
+
+	return glob.Match(strings.TrimPrefix(pattern, "/"), rel)
+}
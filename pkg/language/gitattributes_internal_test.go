@@ -0,0 +1,54 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchAttributesPattern(t *testing.T) {
+	tests := map[string]struct {
+		Pattern  string
+		Path     string
+		Expected bool
+	}{
+		"file name":                  {Pattern: "*.pb.go", Path: "api/v1/service.pb.go", Expected: true},
+		"file name no match":         {Pattern: "*.pb.go", Path: "api/v1/service.go"},
+		"anchored":                   {Pattern: "/dist/*.js", Path: "dist/app.js", Expected: true},
+		"anchored nested no match":   {Pattern: "/dist/*.js", Path: "web/dist/app.js"},
+		"relative with slash":        {Pattern: "dist/*.js", Path: "web/dist/app.js"},
+		"trailing double star":       {Pattern: "vendor/**", Path: "vendor/github.com/pkg/errors/errors.go", Expected: true},
+		"leading double star":        {Pattern: "**/testdata/*", Path: "pkg/language/testdata/golang.go", Expected: true},
+		"inner double star":          {Pattern: "docs/**/*.md", Path: "docs/README.md", Expected: true},
+		"folder pattern":             {Pattern: "vendor/", Path: "vendor/errors.go"},
+		"folder without double star": {Pattern: "vendor", Path: "vendor/errors.go"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.Expected, matchAttributesPattern(test.Pattern, test.Path))
+		})
+	}
+}
+
+func TestLinguistAttributes_Apply(t *testing.T) {
+	var attrs linguistAttributes
+
+	attrs.apply("linguist-generated")
+	attrs.apply("linguist-vendored=true")
+	attrs.apply("linguist-documentation")
+	attrs.apply("-linguist-documentation")
+	attrs.apply("linguist-language=Objective-C")
+
+	assert.Equal(t, linguistAttributes{
+		Language:  "Objective-C",
+		Generated: true,
+		Vendored:  true,
+	}, attrs)
+
+	attrs.apply("linguist-generated=false")
+	attrs.apply("!linguist-vendored")
+	attrs.apply("!linguist-language")
+
+	assert.Equal(t, linguistAttributes{}, attrs)
+}
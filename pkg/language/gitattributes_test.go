@@ -0,0 +1,257 @@
+package language_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/language"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAttributes(t *testing.T) {
+	root := setupTestGitAttributes(t)
+
+	building := heartbeat.BuildingCategory
+
+	opt := language.WithAttributes(language.AttributesConfig{
+		Generated: language.AttributeAction{Skip: true},
+		Vendored:  language.AttributeAction{Category: &building},
+	})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 3)
+
+		assert.Equal(t, filepath.Join(root, "vendor", "lib.go"), hh[0].Entity)
+		assert.Equal(t, heartbeat.BuildingCategory, hh[0].Category)
+		assert.Equal(t, heartbeat.PointerTo(""), hh[0].LinguistLanguage)
+
+		// coding category set by the plugin is kept
+		assert.Equal(t, filepath.Join(root, "vendor", "lib.go"), hh[1].Entity)
+		assert.Equal(t, heartbeat.CodingCategory, hh[1].Category)
+
+		assert.Equal(t, filepath.Join(root, "main.go"), hh[2].Entity)
+		assert.Equal(t, heartbeat.CodingCategory, hh[2].Category)
+
+		return []heartbeat.Result{
+			{
+				Status: 201,
+			},
+		}, nil
+	})
+
+	_, err := h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Category:   heartbeat.CodingCategory,
+			Entity:     filepath.Join(root, "dist", "app.js"),
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Category:   heartbeat.CodingCategory,
+			Entity:     filepath.Join(root, "vendor", "lib.go"),
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Category:      heartbeat.CodingCategory,
+			Entity:        filepath.Join(root, "vendor", "lib.go"),
+			EntityType:    heartbeat.FileType,
+			IsCategorySet: true,
+		},
+		{
+			Category:   heartbeat.CodingCategory,
+			Entity:     filepath.Join(root, "main.go"),
+			EntityType: heartbeat.FileType,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestWithDetection_GitAttributes(t *testing.T) {
+	root := setupTestGitAttributes(t)
+
+	opt := language.WithDetection(language.Config{})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 3)
+
+		assert.Equal(t, heartbeat.LanguagePHP.String(), *hh[0].Language)
+		assert.Equal(t, heartbeat.LanguageCPP.String(), *hh[1].Language)
+		assert.Equal(t, heartbeat.LanguageGo.String(), *hh[2].Language)
+
+		return []heartbeat.Result{
+			{
+				Status: 201,
+			},
+		}, nil
+	})
+
+	_, err := h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Entity:     filepath.Join(root, "templates", "index.tpl"),
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Entity:     filepath.Join(root, "src", "legacy", "util.h"),
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Entity:     filepath.Join(root, "main.go"),
+			EntityType: heartbeat.FileType,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestWithDetection_GitAttributes_OutsideRepository(t *testing.T) {
+	root := setupTestGitAttributes(t)
+
+	err := os.RemoveAll(filepath.Join(root, ".git"))
+	require.NoError(t, err)
+
+	opt := language.WithDetection(language.Config{})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 1)
+
+		assert.Equal(t, heartbeat.LanguageC.String(), *hh[0].Language)
+
+		return []heartbeat.Result{}, nil
+	})
+
+	_, err = h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Entity:     filepath.Join(root, "src", "legacy", "util.h"),
+			EntityType: heartbeat.FileType,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestWithDetection_GitAttributes_LinguistLanguage(t *testing.T) {
+	root := setupTestGitAttributes(t)
+
+	opt := language.WithDetection(language.Config{})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 2)
+
+		// attributes read by WithAttributes are not read again
+		assert.Equal(t, heartbeat.LanguageRuby.String(), *hh[0].Language)
+		assert.Equal(t, heartbeat.LanguageSmarty.String(), *hh[1].Language)
+
+		return []heartbeat.Result{}, nil
+	})
+
+	_, err := h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Entity:           filepath.Join(root, "main.go"),
+			EntityType:       heartbeat.FileType,
+			LinguistLanguage: heartbeat.PointerTo("Ruby"),
+		},
+		{
+			Entity:           filepath.Join(root, "templates", "index.tpl"),
+			EntityType:       heartbeat.FileType,
+			LinguistLanguage: heartbeat.PointerTo(""),
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestWithDetection_GitAttributes_Gitfile(t *testing.T) {
+	tests := map[string]struct {
+		Files map[string]string
+	}{
+		"worktree": {
+			Files: map[string]string{
+				"main/.git/HEAD":                        "ref: refs/heads/master\n",
+				"main/.git/info/attributes":             "*.tpl linguist-language=PHP\n",
+				"main/.git/worktrees/feature/HEAD":      "ref: refs/heads/feature\n",
+				"main/.git/worktrees/feature/commondir": "../..\n",
+				"feature/.git":                          "gitdir: ../main/.git/worktrees/feature\n",
+				"feature/templates/index.tpl":           "{$title}\n",
+			},
+		},
+		"submodule": {
+			Files: map[string]string{
+				"main/.git/HEAD":                            "ref: refs/heads/master\n",
+				"main/.git/modules/feature/HEAD":            "ref: refs/heads/master\n",
+				"main/.git/modules/feature/info/attributes": "*.tpl linguist-language=PHP\n",
+				"main/feature/.git":                         "gitdir: ../.git/modules/feature\n",
+				"main/feature/templates/index.tpl":          "{$title}\n",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+
+			var entity string
+
+			for fp, content := range test.Files {
+				fp = filepath.Join(root, fp)
+
+				err := os.MkdirAll(filepath.Dir(fp), 0700)
+				require.NoError(t, err)
+
+				err = os.WriteFile(fp, []byte(content), 0600)
+				require.NoError(t, err)
+
+				if filepath.Ext(fp) == ".tpl" {
+					entity = fp
+				}
+			}
+
+			opt := language.WithDetection(language.Config{})
+
+			h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+				require.Len(t, hh, 1)
+
+				assert.Equal(t, heartbeat.LanguagePHP.String(), *hh[0].Language)
+
+				return []heartbeat.Result{}, nil
+			})
+
+			_, err := h(context.Background(), []heartbeat.Heartbeat{
+				{
+					Entity:     entity,
+					EntityType: heartbeat.FileType,
+				},
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func setupTestGitAttributes(t *testing.T) string {
+	root := t.TempDir()
+
+	files := map[string]string{
+		".git/HEAD":                 "ref: refs/heads/master\n",
+		".gitattributes":            "# linguist overrides\n*.tpl linguist-language=PHP\ndist/** linguist-generated\nvendor/** linguist-vendored\n",
+		"src/.gitattributes":        "legacy/*.h linguist-language=C\n",
+		"src/legacy/.gitattributes": "*.h linguist-language=C++\n",
+		"dist/app.js":               "console.log('hello')\n",
+		"main.go":                   "package main\n",
+		"src/legacy/util.c":         "",
+		"src/legacy/util.h":         "",
+		"templates/index.tpl":       "{{ .Title }}\n",
+		"vendor/lib.go":             "package lib\n",
+	}
+
+	for name, content := range files {
+		fp := filepath.Join(root, filepath.FromSlash(name))
+
+		err := os.MkdirAll(filepath.Dir(fp), 0750)
+		require.NoError(t, err)
+
+		err = os.WriteFile(fp, []byte(content), 0600)
+		require.NoError(t, err)
+	}
+
+	return root
+}
@@ -0,0 +1,83 @@
+package language
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/wakatime/wakatime-cli/pkg/file"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// Max file size supporting reading from file. Default is 512Kb.
+const maxFileSize = 512000
+
+// fileHeads caches the heads of files read while detecting the language of a
+// heartbeat, so the detection stages read the file once.
+type fileHeads struct {
+	mu    sync.Mutex
+	heads map[string]fileHeadResult
+}
+
+// fileHeadResult is the cached outcome of reading the head of a file.
+type fileHeadResult struct {
+	data []byte
+	err  error
+}
+
+type fileHeadsKey struct{}
+
+// withFileHeads returns a new context caching the heads of files read via
+// fileHead. It's scoped to a single heartbeat, so the cache doesn't grow with
+// the number of heartbeats.
+func withFileHeads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fileHeadsKey{}, &fileHeads{
+		heads: make(map[string]fileHeadResult),
+	})
+}
+
+// fileHead returns the first `maxFileSize` bytes of the file's content. The
+// head is read only once, if the context caches file heads.
+func fileHead(ctx context.Context, fp string) ([]byte, error) {
+	cache, ok := ctx.Value(fileHeadsKey{}).(*fileHeads)
+	if !ok {
+		return readFileHead(ctx, fp, maxFileSize)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if result, ok := cache.heads[fp]; ok {
+		return result.data, result.err
+	}
+
+	data, err := readFileHead(ctx, fp, maxFileSize)
+
+	cache.heads[fp] = fileHeadResult{data: data, err: err}
+
+	return data, err
+}
+
+// readFileHead reads the first size bytes of the file's content.
+func readFileHead(ctx context.Context, fp string, size int64) ([]byte, error) {
+	logger := log.Extract(ctx)
+
+	f, err := file.OpenNoLock(fp) // nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %s", err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Debugf("failed to close file '%s': %s", fp, err)
+		}
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(f, size))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read bytes from file: %s", err)
+	}
+
+	return data, nil
+}
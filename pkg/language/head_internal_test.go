@@ -0,0 +1,64 @@
+package language
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileHead_Cached(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "main.py")
+
+	err := os.WriteFile(fp, []byte("#!/usr/bin/env python\nprint(1)\n"), 0600)
+	require.NoError(t, err)
+
+	ctx := withFileHeads(context.Background())
+
+	head, err := fileHead(ctx, fp)
+	require.NoError(t, err)
+
+	assert.Equal(t, "#!/usr/bin/env python\nprint(1)\n", string(head))
+
+	// later detection stages of the heartbeat get the head read first
+	err = os.WriteFile(fp, []byte("changed"), 0600)
+	require.NoError(t, err)
+
+	head, err = fileHead(ctx, fp)
+	require.NoError(t, err)
+
+	assert.Equal(t, "#!/usr/bin/env python\nprint(1)\n", string(head))
+
+	line, err := fileFirstLine(ctx, fp)
+	require.NoError(t, err)
+
+	assert.Equal(t, "#!/usr/bin/env python\n", line)
+
+	// without cache, the file is read again
+	head, err = fileHead(context.Background(), fp)
+	require.NoError(t, err)
+
+	assert.Equal(t, "changed", string(head))
+
+	// a new cache reads the file again
+	head, err = fileHead(withFileHeads(ctx), fp)
+	require.NoError(t, err)
+
+	assert.Equal(t, "changed", string(head))
+}
+
+func TestReadFileHead_Size(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "main.js")
+
+	err := os.WriteFile(fp, bytes.Repeat([]byte("a"), maxSniffSize+1), 0600)
+	require.NoError(t, err)
+
+	head, err := readFileHead(context.Background(), fp, maxSniffSize)
+	require.NoError(t, err)
+
+	assert.Len(t, head, maxSniffSize)
+}
@@ -0,0 +1,340 @@
+package language
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// heuristic resolves a file with an ambiguous extension to a language, if any
+// of its patterns matches the file contents. It's modeled after the heuristics
+// of GitHub Linguist.
+type heuristic struct {
+	Language heartbeat.Language
+	Patterns []*regexp.Regexp
+}
+
+// objectiveCPattern matches Objective-C directives and imports.
+const objectiveCPattern = `(?m)^\s*(?:@(?:interface|class|protocol|property|end|synchronised|selector|implementation)\b|#import\s+.+\.h[">])`
+
+// heuristics contains the heuristics by lowercase file extension. They're
+// evaluated in order and the first matching heuristic wins.
+var heuristics = map[string][]heuristic{
+	".cls": {
+		{
+			Language: heartbeat.LanguageTeX,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*\\(?:NeedsTeXFormat|ProvidesClass)\{`),
+		},
+		{
+			Language: heartbeat.LanguageVBA,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:VERSION 1\.0 CLASS|Attribute VB_Name\s*=)`),
+		},
+		{
+			Language: heartbeat.LanguageApex,
+			Patterns: compileHeuristicPatterns(
+				`(?i)\b(?:public|private|global)\s+(?:(?:virtual|abstract|with sharing|without sharing|inherited sharing)\s+)*class\b`,
+			),
+		},
+	},
+	".cs": {
+		{
+			Language: heartbeat.LanguageSmalltalk,
+			Patterns: compileHeuristicPatterns(`![\w\s]+methodsFor: `),
+		},
+		{
+			Language: heartbeat.LanguageCSharp,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:using\s+[\w.]+\s*;|namespace\s+[\w.]+)`),
+		},
+	},
+	".d": {
+		{
+			Language: heartbeat.LanguageD,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*module\s+[\w.]+\s*;|^\s*import\s+[\w.]+\s*;`),
+		},
+		{
+			Language: heartbeat.LanguageDTrace,
+			Patterns: compileHeuristicPatterns(
+				`(?m)^(?:\w+:\w*:\w*:\w*|BEGIN|END|provider\s+|(?:tick|profile)-\w+\s+\{)`,
+				`(?m)^#pragma\s+D\s+(?:option|attributes|depends_on)\s`,
+			),
+		},
+		{
+			Language: heartbeat.LanguageMakefile,
+			Patterns: compileHeuristicPatterns(`(?m)^[\w/\\.-]+\.o\s*:\s+\S`),
+		},
+	},
+	".es": {
+		{
+			Language: heartbeat.LanguageErlang,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:%%|main\s*\(.*?\)\s*->)`),
+		},
+		{
+			Language: heartbeat.LanguageJavaScript,
+			Patterns: compileHeuristicPatterns(
+				`(?m)^\s*(?:import|export)\s`,
+				`"use strict"|'use strict'`,
+			),
+		},
+	},
+	".fs": {
+		{
+			Language: heartbeat.LanguageForth,
+			Patterns: compileHeuristicPatterns(`(?m)^(?:: |new-device)`),
+		},
+		{
+			Language: heartbeat.LanguageFSharp,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:#light|import|let|module|namespace|open|type)\b`),
+		},
+		{
+			Language: heartbeat.LanguageGLSL,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:#version|precision|uniform|varying|vec[234])\b`),
+		},
+	},
+	".gs": {
+		{
+			Language: heartbeat.LanguageGLSL,
+			Patterns: compileHeuristicPatterns(`(?m)^#version\s+\d+\b`),
+		},
+		{
+			Language: heartbeat.LanguageGosu,
+			Patterns: compileHeuristicPatterns(`(?m)^uses\s+[\w.]+\*?\s*$`),
+		},
+	},
+	".h": {
+		{
+			Language: heartbeat.LanguageObjectiveC,
+			Patterns: compileHeuristicPatterns(objectiveCPattern),
+		},
+		{
+			Language: heartbeat.LanguageCPP,
+			Patterns: compileHeuristicPatterns(
+				`(?m)^\s*#\s*include\s*<(?:cstdint|string|vector|map|list|array|bitset|queue|stack|forward_list|unordered_map|unordered_set|(?:i|o|io)stream)>`,
+				`(?m)^\s*template\s*<`,
+				`(?m)^[ \t]*(?:private|public|protected):\s*$`,
+				`(?m)^\s*namespace\s+\w*\s*\{`,
+				`\bstd::\w+`,
+			),
+		},
+	},
+	".inc": {
+		{
+			Language: heartbeat.LanguagePHP,
+			Patterns: compileHeuristicPatterns(`^<\?(?:php)?`),
+		},
+		{
+			Language: heartbeat.LanguagePOVRay,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*#(?:declare|local|macro|while)\s`),
+		},
+		{
+			Language: heartbeat.LanguageSourcePawn,
+			Patterns: compileHeuristicPatterns(
+				`(?m)^public\s+(?:SharedPlugin|Extension|Plugin)\s+\w+\s*=`,
+				`(?m)^#pragma\s+semicolon\b`,
+			),
+		},
+		{
+			Language: heartbeat.LanguageNASM,
+			Patterns: compileHeuristicPatterns(`(?im)^\s*(?:section\s+\.(?:text|data|bss)\b|%(?:macro|define|include)\s)`),
+		},
+	},
+	".m": {
+		{
+			Language: heartbeat.LanguageObjectiveC,
+			Patterns: compileHeuristicPatterns(objectiveCPattern),
+		},
+		{
+			Language: heartbeat.LanguageLimbo,
+			Patterns: compileHeuristicPatterns(`(?m)^\w+\s*:\s*module\s*\{`),
+		},
+		{
+			Language: heartbeat.LanguageMathematica,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*\w+\[.*?_\]\s*:?=`),
+		},
+		{
+			Language: heartbeat.LanguageMatlab,
+			Patterns: compileHeuristicPatterns(
+				`(?m)^\s*%`,
+				`(?m)^\s*function\s+(?:\[?[\w\s,]*\]?\s*=\s*)?\w+\s*\(`,
+			),
+		},
+	},
+	".pl": {
+		{
+			Language: heartbeat.LanguagePerl,
+			Patterns: compileHeuristicPatterns(perlPatterns...),
+		},
+		{
+			Language: heartbeat.LanguageRaku,
+			Patterns: compileHeuristicPatterns(rakuPatterns...),
+		},
+		{
+			Language: heartbeat.LanguageProlog,
+			Patterns: compileHeuristicPatterns(`(?m)^[^#%]*:-`),
+		},
+	},
+	".pm": {
+		{
+			Language: heartbeat.LanguagePerl,
+			Patterns: compileHeuristicPatterns(perlPatterns...),
+		},
+		{
+			Language: heartbeat.LanguageRaku,
+			Patterns: compileHeuristicPatterns(rakuPatterns...),
+		},
+	},
+	".pp": {
+		{
+			Language: heartbeat.LanguagePascal,
+			Patterns: compileHeuristicPatterns(`(?im)^\s*(?:program|unit)\s+\w+\s*;`, `(?im)^\s*end[.;]`),
+		},
+		{
+			Language: heartbeat.LanguagePuppet,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:class|define|node)\s+[\w:'"-]+.*\{`, `(?m)^\s+\w+\s+=>\s`),
+		},
+	},
+	".pro": {
+		{
+			Language: heartbeat.LanguageProlog,
+			Patterns: compileHeuristicPatterns(`(?m)^[^\[#%]+:-`),
+		},
+		{
+			Language: heartbeat.LanguageIDL,
+			Patterns: compileHeuristicPatterns(`(?im)^\s*(?:function|pro)\s+\w+(?:\s*,[ \w,]*)?$`),
+		},
+	},
+	".r": {
+		{
+			Language: heartbeat.LanguageREBOL,
+			Patterns: compileHeuristicPatterns(`(?i)\bRebol\s*\[`),
+		},
+		{
+			Language: heartbeat.LanguageR,
+			Patterns: compileHeuristicPatterns(`<-`, `(?m)^\s*library\(`),
+		},
+	},
+	".rs": {
+		{
+			Language: heartbeat.LanguageRust,
+			Patterns: compileHeuristicPatterns(`(?m)^(?:use |fn |mod |pub |macro_rules|impl|#!?\[)`),
+		},
+		{
+			Language: heartbeat.LanguageRenderScript,
+			Patterns: compileHeuristicPatterns(`(?m)^#pragma\s+(?:rs|version)\b`),
+		},
+	},
+	".sc": {
+		{
+			Language: heartbeat.LanguageSuperCollider,
+			Patterns: compileHeuristicPatterns(`\^(?:this|super)\.`, `(?m)^\s*~\w+\s*=`),
+		},
+		{
+			Language: heartbeat.LanguageScala,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*import\s+(?:scala|java)\.`, `(?m)^\s*(?:case\s+)?(?:class|object|trait)\s+\w+`),
+		},
+	},
+	".t": {
+		{
+			Language: heartbeat.LanguagePerl,
+			Patterns: compileHeuristicPatterns(perlPatterns...),
+		},
+		{
+			Language: heartbeat.LanguageRaku,
+			Patterns: compileHeuristicPatterns(rakuPatterns...),
+		},
+		{
+			Language: heartbeat.LanguageTuring,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*%[ \t]+`, `(?m)^\s*var\s+\w+(?:\s*:\s*\w+)?\s*:=`),
+		},
+	},
+	".ts": {
+		{
+			Language: heartbeat.LanguageXML,
+			Patterns: compileHeuristicPatterns(`<TS\b`),
+		},
+		{
+			Language: heartbeat.LanguageTypeScript,
+			Patterns: compileHeuristicPatterns(`(?m)^\s*(?:import|export)\s`),
+		},
+	},
+	".v": {
+		{
+			Language: heartbeat.LanguageCoq,
+			Patterns: compileHeuristicPatterns(
+				`(?m)(?:^|\s)(?:Proof|Qed)\.(?:$|\s)`,
+				`(?m)(?:^|\s)Require\s+(?:Import|Export)\s`,
+			),
+		},
+		{
+			Language: heartbeat.LanguageVerilog,
+			Patterns: compileHeuristicPatterns(
+				`(?m)^[ \t]*module\s+[^\s()]+\s+#?\(`,
+				"(?m)^[ \\t]*`(?:define|ifdef|ifndef|include|timescale)",
+				`(?m)^[ \t]*always[ \t]+@`,
+				`(?m)^[ \t]*initial[ \t]+(?:begin|@)`,
+			),
+		},
+		{
+			Language: heartbeat.LanguageV,
+			Patterns: compileHeuristicPatterns(
+				`\$(?:if|else)[ \t]`,
+				`(?m)^[ \t]*fn\s+[^\s()]+\(.*?\).*?\{`,
+				`(?m)^[ \t]*module\s+\w+\s*$`,
+			),
+		},
+	},
+}
+
+var (
+	perlPatterns = []string{
+		`(?m)^\s*use\s+(?:strict|warnings|v?5)\b`,
+		`^#!.*\bperl\b`,
+	}
+	rakuPatterns = []string{
+		`(?m)^\s*use\s+v6\b`,
+		`(?m)^\s*(?:unit\s+)?(?:module|class|role|grammar)\s+[\w:]+\s*[;{]`,
+	}
+)
+
+// detectHeuristics detects the language of files with an ambiguous extension
+// from their contents.
+func detectHeuristics(ctx context.Context, fp string) (heartbeat.Language, bool) {
+	rules, ok := heuristics[strings.ToLower(filepath.Ext(fp))]
+	if !ok {
+		return heartbeat.LanguageUnknown, false
+	}
+
+	head, err := fileHead(ctx, fp)
+	if err != nil {
+		log.Extract(ctx).Debugf("failed to load head from file %q: %s", fp, err)
+		return heartbeat.LanguageUnknown, false
+	}
+
+	return matchHeuristics(rules, head)
+}
+
+// matchHeuristics returns the language of the first heuristic matching data.
+func matchHeuristics(rules []heuristic, data []byte) (heartbeat.Language, bool) {
+	for _, rule := range rules {
+		for _, pattern := range rule.Patterns {
+			if pattern.Match(data) {
+				return rule.Language, true
+			}
+		}
+	}
+
+	return heartbeat.LanguageUnknown, false
+}
+
+func compileHeuristicPatterns(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+
+	for i, p := range patterns {
+		compiled[i] = regexp.MustCompile(p)
+	}
+
+	return compiled
+}
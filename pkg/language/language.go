@@ -9,17 +9,34 @@ import (
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
 )
 
 // Config defines language detection options.
 type Config struct {
 	// GuessLanguage enables detecting lexer language from file contents.
 	GuessLanguage bool
+	// MapPatterns map entity paths to languages. They take precedence over
+	// language detection.
+	MapPatterns []MapPattern
+	// SecondaryLanguage enables detecting the language of embedded code at the
+	// cursor position of multi-language files.
+	SecondaryLanguage bool
+}
+
+// MapPattern maps entity paths matching a regex pattern to a language.
+type MapPattern struct {
+	// Language is the language of matching entities.
+	Language heartbeat.Language
+	// Regex matches the entity path.
+	Regex regex.Regex
 }
 
 // WithDetection initializes and returns a heartbeat handle option, which
 // can be used in a heartbeat processing pipeline to detect and add programming
-// language info to heartbeats of entity type 'file'.
+// language info to heartbeats of entity type 'file'. If enabled and the cursor
+// position is known, the language of the embedded code at the cursor position
+// of multi-language files is added as secondary language.
 func WithDetection(config Config) heartbeat.HandleOption {
 	return func(next heartbeat.Handle) heartbeat.Handle {
 		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
@@ -27,30 +44,33 @@ func WithDetection(config Config) heartbeat.HandleOption {
 			logger.Debugln("execute language detection")
 
 			for n, h := range hh {
-				if hh[n].Language != nil {
-					continue
+				// file heads are shared by the detection stages of a heartbeat
+				ctx := withFileHeads(ctx)
+
+				if config.SecondaryLanguage && h.CursorPosition != nil && h.EntityType == heartbeat.FileType &&
+					h.LanguageSecondary == nil {
+					fp := h.Entity
+					if h.LocalFile != "" {
+						fp = h.LocalFile
+					}
+
+					if language, ok := detectEmbedded(ctx, fp, *h.CursorPosition); ok {
+						hh[n].LanguageSecondary = heartbeat.PointerTo(language.String())
+					}
 				}
 
-				filepath := h.Entity
-
-				if h.LocalFile != "" {
-					filepath = h.LocalFile
-				}
-
-				language, err := Detect(ctx, filepath, config.GuessLanguage)
-				if err != nil && hh[n].LanguageAlternate != "" {
-					hh[n].Language = heartbeat.PointerTo(hh[n].LanguageAlternate)
-
+				if hh[n].Language != nil {
 					continue
 				}
 
+				language, err := Resolve(ctx, h, config)
 				if err != nil {
 					logger.Debugf("failed to detect language on file entity %q: %s", h.Entity, err)
 
 					continue
 				}
 
-				hh[n].Language = heartbeat.PointerTo(language.String())
+				hh[n].Language = heartbeat.PointerTo(language)
 			}
 
 			return next(ctx, hh)
@@ -58,24 +78,88 @@ func WithDetection(config Config) heartbeat.HandleOption {
 	}
 }
 
-// Detect detects the language of a specific file. If guessLanguage is true,
-// Chroma will be used to detect a language from the file contents.
+// Resolve returns the language of a heartbeat, as detected in the heartbeat
+// processing pipeline. The language sent by the plugin takes precedence over
+// map patterns, linguist-language attributes and detection from the file. The
+// alternate language is used if no language is detected.
+func Resolve(ctx context.Context, h heartbeat.Heartbeat, config Config) (string, error) {
+	if h.Language != nil {
+		return *h.Language, nil
+	}
+
+	if language, ok := matchPattern(ctx, h.Entity, config.MapPatterns); ok {
+		return language.String(), nil
+	}
+
+	if h.EntityType == heartbeat.FileType && !h.IsRemote() {
+		if language, ok := detectAttributesLanguage(ctx, h); ok {
+			return language.String(), nil
+		}
+	}
+
+	filepath := h.Entity
+
+	if h.LocalFile != "" {
+		filepath = h.LocalFile
+	}
+
+	language, err := Detect(ctx, filepath, config.GuessLanguage)
+	if err != nil && h.LanguageAlternate != "" {
+		return h.LanguageAlternate, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return language.String(), nil
+}
+
+// Detect detects the language of a specific file. An explicit vim, emacs or
+// kate modeline takes precedence over content heuristics of ambiguous file
+// extensions and special cases, and over the lexer matched by filename unless
+// that lexer analyses the file content with a higher weight. Files not matched
+// by name are detected by the interpreter of their shebang line. If
+// guessLanguage is true, Chroma will be used to detect a language from the
+// file contents.
 func Detect(ctx context.Context, fp string, guessLanguage bool) (heartbeat.Language, error) {
-	if language, ok := detectSpecialCases(ctx, fp); ok {
+	registerLexers(ctx)
+
+	if language, ok := detectNotebook(ctx, fp); ok {
 		return language, nil
 	}
 
+	languageModeline, weightModeline, okModeline := detectModeline(ctx, fp)
+
+	if !okModeline {
+		if language, ok := detectHeuristics(ctx, fp); ok {
+			return language, nil
+		}
+
+		if language, ok := detectSpecialCases(ctx, fp); ok {
+			return language, nil
+		}
+	}
+
 	var language heartbeat.Language
 
-	languageChroma, weight, ok := detectChromaCustomized(ctx, fp, guessLanguage)
+	languageChroma, weight, ok := detectChromaCustomized(ctx, fp)
+	if !ok {
+		languageChroma, ok = detectShebang(ctx, fp)
+	}
+
+	if !ok && guessLanguage {
+		languageChroma, ok = detectChromaAnalyse(ctx, fp)
+	}
+
 	if ok {
 		language = languageChroma
 	}
 
-	languageVim, weightVim, okVim := detectVimModeline(fp)
-	if okVim && weightVim > weight {
-		// use language from vim modeline, if weight is higher
-		language = languageVim
+	if okModeline && (!ok || weightModeline >= weight) {
+		// use language from vim, emacs or kate modeline, unless chroma weight is higher.
+		// On equal weight, e.g. if neither lexer analyses the text, the modeline wins.
+		language = languageModeline
 	}
 
 	if language == heartbeat.LanguageUnknown {
@@ -85,6 +169,17 @@ func Detect(ctx context.Context, fp string, guessLanguage bool) (heartbeat.Langu
 	return language, nil
 }
 
+// matchPattern returns the language of the first map pattern matching fp.
+func matchPattern(ctx context.Context, fp string, patterns []MapPattern) (heartbeat.Language, bool) {
+	for _, pattern := range patterns {
+		if pattern.Regex.MatchString(ctx, fp) {
+			return pattern.Language, true
+		}
+	}
+
+	return heartbeat.LanguageUnknown, false
+}
+
 // detectSpecialCases detects the language by file extension for some special cases.
 func detectSpecialCases(ctx context.Context, fp string) (heartbeat.Language, bool) {
 	dir, file := filepath.Split(fp)
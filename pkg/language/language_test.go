@@ -3,11 +3,16 @@ package language_test
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 	"github.com/wakatime/wakatime-cli/pkg/language"
 	"github.com/wakatime/wakatime-cli/pkg/lexer"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +54,42 @@ func TestWithDetection(t *testing.T) {
 	}, result)
 }
 
+func TestWithDetection_MapPatterns(t *testing.T) {
+	opt := language.WithDetection(language.Config{
+		MapPatterns: []language.MapPattern{
+			{
+				Language: heartbeat.LanguagePHP,
+				Regex:    regex.NewRegexpWrap(regexp.MustCompile(`(?i)\.go$`)),
+			},
+		},
+	})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 2)
+		assert.Equal(t, heartbeat.LanguagePHP.String(), *hh[0].Language)
+		assert.Equal(t, heartbeat.LanguagePython.String(), *hh[1].Language)
+
+		return []heartbeat.Result{
+			{
+				Status: 201,
+			},
+		}, nil
+	})
+
+	_, err := h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Entity:     "testdata/codefiles/golang.go",
+			EntityType: heartbeat.FileType,
+		},
+		{
+			Entity:     "testdata/codefiles/golang.go",
+			EntityType: heartbeat.FileType,
+			Language:   heartbeat.PointerTo("Python"),
+		},
+	})
+	require.NoError(t, err)
+}
+
 func TestWithDetection_Override(t *testing.T) {
 	opt := language.WithDetection(language.Config{})
 
@@ -257,6 +298,249 @@ func TestDetect_FSharp_Over_Forth(t *testing.T) {
 	assert.Equal(t, heartbeat.LanguageFSharp, lang)
 }
 
+func TestDetect_Shebang(t *testing.T) {
+	tests := map[string]struct {
+		Content  string
+		Expected heartbeat.Language
+	}{
+		"bash": {
+			Content:  "#!/bin/sh\necho hello\n",
+			Expected: heartbeat.LanguageBash,
+		},
+		"env split string": {
+			Content:  "#!/usr/bin/env -S node --experimental-modules\nconsole.log('hello')\n",
+			Expected: heartbeat.LanguageJavaScript,
+		},
+		"versioned python": {
+			Content:  "#!/usr/bin/env python3.12\nprint('hello')\n",
+			Expected: heartbeat.LanguagePython,
+		},
+		"versioned ruby": {
+			Content:  "#!/usr/local/bin/ruby2.7\nputs 'hello'\n",
+			Expected: heartbeat.LanguageRuby,
+		},
+		"raku": {
+			Content:  "#!/usr/bin/env perl6\nsay 'hello';\n",
+			Expected: heartbeat.LanguageRaku,
+		},
+		"typescript": {
+			Content:  "#!/usr/bin/env ts-node\nconsole.log('hello')\n",
+			Expected: heartbeat.LanguageTypeScript,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fp := filepath.Join(t.TempDir(), "bin", "script")
+
+			err := os.MkdirAll(filepath.Dir(fp), 0750)
+			require.NoError(t, err)
+
+			err = os.WriteFile(fp, []byte(test.Content), 0600)
+			require.NoError(t, err)
+
+			lang, err := language.Detect(context.Background(), fp, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Expected, lang)
+		})
+	}
+}
+
+func TestDetect_Shebang_UnknownInterpreter(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "script")
+
+	err := os.WriteFile(fp, []byte("#!/usr/bin/env unknown-interpreter\n"), 0600)
+	require.NoError(t, err)
+
+	_, err = language.Detect(context.Background(), fp, false)
+	require.Error(t, err)
+}
+
+func TestDetect_Notebook(t *testing.T) {
+	tests := map[string]heartbeat.Language{
+		"python.ipynb": heartbeat.LanguagePython,
+		"julia.ipynb":  heartbeat.LanguageJulia,
+	}
+
+	for filename, expected := range tests {
+		t.Run(filename, func(t *testing.T) {
+			lang, err := language.Detect(context.Background(), filepath.Join("testdata/codefiles", filename), false)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, lang)
+		})
+	}
+}
+
+func TestDetect_Notebook_LargeCells(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "large.ipynb")
+
+	cell := `{"cell_type": "code", "metadata": {}, "outputs": [], "source": ["` + strings.Repeat("x = 1\\n", 1000) + `"]}`
+	cells := strings.TrimSuffix(strings.Repeat(cell+",", 2000), ",")
+
+	data := `{"cells": [` + cells + `], "metadata": {"kernelspec": {"language": "python"}}, "nbformat": 4}`
+	require.Greater(t, len(data), 10*1024*1024)
+
+	err := os.WriteFile(fp, []byte(data), 0600)
+	require.NoError(t, err)
+
+	lang, err := language.Detect(context.Background(), fp, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, heartbeat.LanguagePython, lang)
+}
+
+func TestWithDetection_SecondaryLanguage(t *testing.T) {
+	tests := map[string]struct {
+		Entity         string
+		CursorPosition int
+		Expected       *string
+	}{
+		"vue template": {
+			Entity:         "testdata/codefiles/component.vue",
+			CursorPosition: 15,
+		},
+		"vue script": {
+			Entity:         "testdata/codefiles/component.vue",
+			CursorPosition: 75,
+			Expected:       heartbeat.PointerTo(heartbeat.LanguageTypeScript.String()),
+		},
+		"vue style": {
+			Entity:         "testdata/codefiles/component.vue",
+			CursorPosition: 148,
+			Expected:       heartbeat.PointerTo(heartbeat.LanguageSCSS.String()),
+		},
+		"markdown fence": {
+			Entity:         "testdata/codefiles/readme.md",
+			CursorPosition: 22,
+			Expected:       heartbeat.PointerTo(heartbeat.LanguagePython.String()),
+		},
+		"markdown fence without language": {
+			Entity:         "testdata/codefiles/readme.md",
+			CursorPosition: 45,
+		},
+		"markdown text": {
+			Entity:         "testdata/codefiles/readme.md",
+			CursorPosition: 3,
+		},
+		"cursor position exceeds file": {
+			Entity:         "testdata/codefiles/readme.md",
+			CursorPosition: 1000,
+		},
+		"single language file": {
+			Entity:         "testdata/codefiles/golang.go",
+			CursorPosition: 10,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			opt := language.WithDetection(language.Config{SecondaryLanguage: true})
+
+			h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+				require.Len(t, hh, 1)
+
+				assert.Equal(t, test.Expected, hh[0].LanguageSecondary)
+
+				return []heartbeat.Result{}, nil
+			})
+
+			_, err := h(context.Background(), []heartbeat.Heartbeat{
+				{
+					CursorPosition: heartbeat.PointerTo(test.CursorPosition),
+					Entity:         test.Entity,
+					EntityType:     heartbeat.FileType,
+				},
+			})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWithDetection_SecondaryLanguage_Disabled(t *testing.T) {
+	opt := language.WithDetection(language.Config{})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 1)
+
+		assert.Nil(t, hh[0].LanguageSecondary)
+		assert.Equal(t, heartbeat.PointerTo(heartbeat.LanguageVueJS.String()), hh[0].Language)
+
+		return []heartbeat.Result{}, nil
+	})
+
+	_, err := h(context.Background(), []heartbeat.Heartbeat{
+		{
+			CursorPosition: heartbeat.PointerTo(75),
+			Entity:         "testdata/codefiles/component.vue",
+			EntityType:     heartbeat.FileType,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestDetect_Heuristics(t *testing.T) {
+	tests := map[string]heartbeat.Language{
+		"apex.cls":         heartbeat.LanguageApex,
+		"coq.v":            heartbeat.LanguageCoq,
+		"cpp.h":            heartbeat.LanguageCPP,
+		"csharp.cs":        heartbeat.LanguageCSharp,
+		"d.d":              heartbeat.LanguageD,
+		"dtrace.d":         heartbeat.LanguageDTrace,
+		"erlang.es":        heartbeat.LanguageErlang,
+		"forth.fs":         heartbeat.LanguageForth,
+		"fsharp.fs":        heartbeat.LanguageFSharp,
+		"glsl.fs":          heartbeat.LanguageGLSL,
+		"glsl.gs":          heartbeat.LanguageGLSL,
+		"gosu.gs":          heartbeat.LanguageGosu,
+		"idl.pro":          heartbeat.LanguageIDL,
+		"javascript.es":    heartbeat.LanguageJavaScript,
+		"limbo.m":          heartbeat.LanguageLimbo,
+		"makefile.d":       heartbeat.LanguageMakefile,
+		"mathematica.m":    heartbeat.LanguageMathematica,
+		"matlab.m":         heartbeat.LanguageMatlab,
+		"nasm.inc":         heartbeat.LanguageNASM,
+		"objective-c.h":    heartbeat.LanguageObjectiveC,
+		"objective-c.m":    heartbeat.LanguageObjectiveC,
+		"pascal.pp":        heartbeat.LanguagePascal,
+		"perl.pl":          heartbeat.LanguagePerl,
+		"perl.pm":          heartbeat.LanguagePerl,
+		"perl.t":           heartbeat.LanguagePerl,
+		"php.inc":          heartbeat.LanguagePHP,
+		"povray.inc":       heartbeat.LanguagePOVRay,
+		"prolog.pl":        heartbeat.LanguageProlog,
+		"prolog.pro":       heartbeat.LanguageProlog,
+		"puppet.pp":        heartbeat.LanguagePuppet,
+		"qt.ts":            heartbeat.LanguageXML,
+		"r.r":              heartbeat.LanguageR,
+		"raku.pl":          heartbeat.LanguageRaku,
+		"raku.pm":          heartbeat.LanguageRaku,
+		"raku.t":           heartbeat.LanguageRaku,
+		"rebol.r":          heartbeat.LanguageREBOL,
+		"renderscript.rs":  heartbeat.LanguageRenderScript,
+		"rust.rs":          heartbeat.LanguageRust,
+		"scala.sc":         heartbeat.LanguageScala,
+		"sourcepawn.inc":   heartbeat.LanguageSourcePawn,
+		"supercollider.sc": heartbeat.LanguageSuperCollider,
+		"tex.cls":          heartbeat.LanguageTeX,
+		"turing.t":         heartbeat.LanguageTuring,
+		"typescript.ts":    heartbeat.LanguageTypeScript,
+		"vba.cls":          heartbeat.LanguageVBA,
+		"verilog.v":        heartbeat.LanguageVerilog,
+		"vlang.v":          heartbeat.LanguageV,
+	}
+
+	for filename, expected := range tests {
+		t.Run(filename, func(t *testing.T) {
+			lang, err := language.Detect(context.Background(), filepath.Join("testdata/codefiles/heuristics", filename), false)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, lang, fmt.Sprintf("got: %q, want: %q", lang, expected))
+		})
+	}
+}
+
 func TestDetect_ChromaTopLanguagesRetrofit(t *testing.T) {
 	err := lexer.RegisterAll()
 	require.NoError(t, err)
@@ -919,3 +1203,51 @@ func TestDetect_ChromaTopLanguagesRetrofit(t *testing.T) {
 		})
 	}
 }
+
+func TestDetect_Modeline(t *testing.T) {
+	tests := map[string]struct {
+		Filename string
+		Content  string
+		Expected heartbeat.Language
+	}{
+		"vim": {
+			Filename: "script",
+			Content:  "print('hello')\n\n# vim: set ft=python :\n",
+			Expected: heartbeat.LanguagePython,
+		},
+		"emacs": {
+			Filename: "Buildfile",
+			Content:  "# -*- mode: ruby -*-\ntask :default\n",
+			Expected: heartbeat.LanguageRuby,
+		},
+		"kate": {
+			Filename: "notes.txt",
+			Content:  "[section]\nkey = value\n; kate: hl INI Files;\n",
+			Expected: heartbeat.LanguageINI,
+		},
+		"equal weight": {
+			Filename: "main.c",
+			Content:  "int main() {}\n// vim: ft=cpp\n",
+			Expected: heartbeat.LanguageCPP,
+		},
+		"vim precedes heuristics": {
+			Filename: "compat.h",
+			Content:  "// vim: ft=c\n/* keep in sync with std::string */\nint length(const char *s);\n",
+			Expected: heartbeat.LanguageC,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fp := filepath.Join(t.TempDir(), test.Filename)
+
+			err := os.WriteFile(fp, []byte(test.Content), 0600)
+			require.NoError(t, err)
+
+			lang, err := language.Detect(context.Background(), fp, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.Expected, lang)
+		})
+	}
+}
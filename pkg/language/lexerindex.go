@@ -0,0 +1,146 @@
+package language
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/danwakefield/fnmatch"
+)
+
+// lexerIndexCache holds the filename glob index of the lexers of the chroma
+// registry. It is rebuilt when the number of registered lexers changes.
+// nolint:gochecknoglobals
+var lexerIndexCache struct {
+	sync.Mutex
+	filenames *globIndex
+	aliases   *globIndex
+	size      int
+}
+
+// globIndex indexes filename globs of lexers by exact filename and by file
+// extension, so matching a filename doesn't need to check every glob. Globs
+// of other forms are matched one by one.
+type globIndex struct {
+	byExtension map[string][]globEntry
+	byFilename  map[string][]globEntry
+	complex     []globEntry
+}
+
+// globEntry is a filename glob of a lexer. Position is the order of the glob
+// in the registry, which keeps matches in the order of a full registry scan.
+type globEntry struct {
+	Glob     string
+	Lexer    chroma.Lexer
+	Position int
+	// Suffix is the literal suffix of globs like *.go, which are indexed by
+	// extension.
+	Suffix string
+}
+
+// loadLexerIndex returns the filename and alias filename glob indexes of the
+// registered lexers.
+func loadLexerIndex() (filenames, aliases *globIndex) {
+	lexerIndexCache.Lock()
+	defer lexerIndexCache.Unlock()
+
+	registered := lexers.GlobalLexerRegistry.Lexers
+
+	if lexerIndexCache.filenames == nil || lexerIndexCache.size != len(registered) {
+		lexerIndexCache.filenames = newGlobIndex(registered, func(c *chroma.Config) []string { return c.Filenames })
+		lexerIndexCache.aliases = newGlobIndex(registered, func(c *chroma.Config) []string { return c.AliasFilenames })
+		lexerIndexCache.size = len(registered)
+	}
+
+	return lexerIndexCache.filenames, lexerIndexCache.aliases
+}
+
+// newGlobIndex builds the index of the globs returned by globs for each lexer.
+func newGlobIndex(registered chroma.Lexers, globs func(*chroma.Config) []string) *globIndex {
+	index := &globIndex{
+		byExtension: map[string][]globEntry{},
+		byFilename:  map[string][]globEntry{},
+	}
+
+	var position int
+
+	for _, lexer := range registered {
+		for _, glob := range globs(lexer.Config()) {
+			entry := globEntry{
+				Glob:     glob,
+				Lexer:    lexer,
+				Position: position,
+			}
+
+			position++
+
+			switch {
+			case !hasGlobMeta(glob):
+				index.byFilename[glob] = append(index.byFilename[glob], entry)
+			case strings.HasPrefix(glob, "*") && !hasGlobMeta(glob[1:]) && filepath.Ext(glob[1:]) != "":
+				entry.Suffix = glob[1:]
+				ext := filepath.Ext(entry.Suffix)
+				index.byExtension[ext] = append(index.byExtension[ext], entry)
+			default:
+				index.complex = append(index.complex, entry)
+			}
+		}
+	}
+
+	return index
+}
+
+// match returns the lexers with a glob matching any of the filenames, in
+// order of the registry. A lexer is returned once per matching glob.
+func (idx *globIndex) match(filenames ...string) chroma.PrioritisedLexers {
+	var candidates []globEntry
+
+	for _, filename := range filenames {
+		candidates = append(candidates, idx.byFilename[filename]...)
+		candidates = append(candidates, idx.byExtension[filepath.Ext(filename)]...)
+	}
+
+	candidates = append(candidates, idx.complex...)
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Position < candidates[j].Position
+	})
+
+	matched := chroma.PrioritisedLexers{}
+
+	for i, entry := range candidates {
+		// same glob found via several filenames
+		if i > 0 && candidates[i-1].Position == entry.Position {
+			continue
+		}
+
+		for _, filename := range filenames {
+			if entry.matches(filename) {
+				matched = append(matched, entry.Lexer)
+				break
+			}
+		}
+	}
+
+	return matched
+}
+
+// matches returns true if the glob of the entry matches filename.
+func (e globEntry) matches(filename string) bool {
+	switch {
+	case e.Suffix != "":
+		return strings.HasSuffix(filename, e.Suffix)
+	case !hasGlobMeta(e.Glob):
+		return e.Glob == filename
+	default:
+		return fnmatch.Match(e.Glob, filename, 0)
+	}
+}
+
+// hasGlobMeta returns true if the glob contains any special characters.
+func hasGlobMeta(glob string) bool {
+	return strings.ContainsAny(glob, `*?[\`)
+}
@@ -0,0 +1,149 @@
+package language
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/lexer"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/danwakefield/fnmatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchLexers(t *testing.T) {
+	err := lexer.RegisterAll()
+	require.NoError(t, err)
+
+	filenames := append(testdataFilenames(t),
+		".bashrc",
+		"CMakeLists.txt",
+		"Dockerfile",
+		"Makefile",
+		"MAIN.GO",
+		"archive.tar.gz",
+		"nginx.conf",
+		"no_extension",
+		"script.sh.in",
+	)
+
+	for _, filename := range filenames {
+		t.Run(filename, func(t *testing.T) {
+			assert.Equal(t, lexerNames(matchLexersScan(filename)), lexerNames(matchLexers(filename)))
+		})
+	}
+}
+
+func TestMatchLexers_RegistryChanged(t *testing.T) {
+	_ = matchLexers("main.go")
+
+	registered := lexers.GlobalLexerRegistry.Lexers
+
+	t.Cleanup(func() {
+		lexers.GlobalLexerRegistry.Lexers = registered
+	})
+
+	lexers.GlobalLexerRegistry.Lexers = append(registered[:len(registered):len(registered)], chroma.MustNewLexer(
+		&chroma.Config{
+			Name:      "Index Test",
+			Filenames: []string{"*.indextest"},
+		},
+		func() chroma.Rules {
+			return chroma.Rules{"root": {}}
+		},
+	))
+
+	assert.Equal(t, []string{"Index Test"}, lexerNames(matchLexers("file.indextest")))
+}
+
+func BenchmarkMatchLexers(b *testing.B) {
+	err := lexer.RegisterAll()
+	require.NoError(b, err)
+
+	filenames := testdataFilenames(b)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, filename := range filenames {
+			_ = matchLexers(filename)
+		}
+	}
+}
+
+func BenchmarkMatchLexers_Scan(b *testing.B) {
+	err := lexer.RegisterAll()
+	require.NoError(b, err)
+
+	filenames := testdataFilenames(b)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, filename := range filenames {
+			_ = matchLexersScan(filename)
+		}
+	}
+}
+
+// matchLexersScan matches the filename against every glob of the registered
+// lexers. It is the reference for the indexed lookup of matchLexers.
+func matchLexersScan(fp string) chroma.PrioritisedLexers {
+	filename := filepath.Base(fp)
+	matched := chroma.PrioritisedLexers{}
+
+	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
+		for _, glob := range lexer.Config().Filenames {
+			if fnmatch.Match(glob, filename, 0) || fnmatch.Match(glob, strings.ToLower(filename), 0) {
+				matched = append(matched, lexer)
+			}
+		}
+	}
+
+	if len(matched) > 0 {
+		return matched
+	}
+
+	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
+		for _, glob := range lexer.Config().AliasFilenames {
+			if fnmatch.Match(glob, filename, 0) {
+				matched = append(matched, lexer)
+			}
+		}
+	}
+
+	return matched
+}
+
+func testdataFilenames(tb testing.TB) []string {
+	var filenames []string
+
+	err := filepath.WalkDir("testdata", func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			filenames = append(filenames, filepath.Base(path))
+		}
+
+		return nil
+	})
+	require.NoError(tb, err)
+
+	return filenames
+}
+
+func lexerNames(ll chroma.PrioritisedLexers) []string {
+	names := []string{}
+
+	for _, l := range ll {
+		names = append(names, l.Config().Name)
+	}
+
+	return names
+}
@@ -0,0 +1,111 @@
+package language
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+// Info describes a known language.
+type Info struct {
+	// Name is the language name sent with heartbeats.
+	Name string
+	// Chroma are the names of the chroma lexers detecting the language.
+	Chroma []string
+	// Vim are the vim file types detected as the language.
+	Vim []string
+}
+
+// Candidate is a chroma lexer considered when detecting the language of a
+// file by its filename.
+type Candidate struct {
+	Lexer    string
+	Language heartbeat.Language
+	Weight   float32
+	Priority float32
+}
+
+// List returns all known languages sorted by name. Languages sharing the
+// same name, e.g. go templates, are listed once.
+func List(ctx context.Context) []Info {
+	registerLexers(ctx)
+
+	chromaNames := map[string][]string{}
+
+	for _, lexer := range lexers.GlobalLexerRegistry.Lexers {
+		name := lexer.Config().Name
+
+		if language, ok := heartbeat.ParseLanguageFromChroma(name); ok {
+			chromaNames[language.String()] = append(chromaNames[language.String()], name)
+		}
+	}
+
+	vimNames := map[string][]string{}
+
+	for fileType, name := range vimFileTypes {
+		if language, ok := heartbeat.ParseLanguage(name); ok {
+			vimNames[language.String()] = append(vimNames[language.String()], fileType)
+		}
+	}
+
+	var (
+		infos []Info
+		seen  = map[string]bool{}
+	)
+
+	for _, language := range heartbeat.Languages() {
+		name := language.String()
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		sort.Strings(chromaNames[name])
+		sort.Strings(vimNames[name])
+
+		infos = append(infos, Info{
+			Name:   name,
+			Chroma: chromaNames[name],
+			Vim:    vimNames[name],
+		})
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return strings.ToLower(infos[i].Name) < strings.ToLower(infos[j].Name)
+	})
+
+	return infos
+}
+
+// Candidates returns the chroma lexers matching the filename of fp with
+// their customized weight and priority. The best matching lexer, which is
+// used for language detection, is returned first.
+func Candidates(ctx context.Context, fp string) []Candidate {
+	registerLexers(ctx)
+
+	matched := matchLexers(fp)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	var candidates []Candidate
+
+	for _, lexer := range weightLexers(ctx, fp, matched) {
+		name := lexer.Config().Name
+		language, _ := heartbeat.ParseLanguageFromChroma(name)
+
+		candidates = append(candidates, Candidate{
+			Lexer:    name,
+			Language: language,
+			Weight:   lexer.Weight,
+			Priority: lexer.Priority,
+		})
+	}
+
+	return candidates
+}
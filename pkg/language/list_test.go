@@ -0,0 +1,73 @@
+package language_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/language"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	infos := language.List(context.Background())
+
+	var names []string
+
+	for _, info := range infos {
+		names = append(names, info.Name)
+
+		if info.Name == heartbeat.LanguageHTML.String() {
+			assert.Contains(t, info.Chroma, "HTML")
+			assert.Equal(t, []string{"html", "htmlcheetah", "htmldjango", "htmlm4", "xhtml"}, info.Vim)
+		}
+
+		if info.Name == heartbeat.LanguageVBNet.String() {
+			assert.Equal(t, []string{"VB.net"}, info.Chroma)
+			assert.Equal(t, []string{"vb"}, info.Vim)
+		}
+	}
+
+	assert.IsNonDecreasing(t, lowercase(names))
+
+	// go templates are reported as go
+	assert.Contains(t, names, "Go")
+	assert.Len(t, names, len(unique(names)))
+}
+
+func TestCandidates(t *testing.T) {
+	candidates := language.Candidates(context.Background(), "testdata/codefiles/perl.pl")
+
+	assert.Equal(t, []language.Candidate{
+		{Lexer: "Perl", Language: heartbeat.LanguagePerl, Priority: 0.01},
+		{Lexer: "Raku", Language: heartbeat.LanguageRaku},
+		{Lexer: "Prolog", Language: heartbeat.LanguageProlog},
+		{Lexer: "Perl6", Language: heartbeat.LanguagePerl6},
+	}, candidates)
+}
+
+func TestCandidates_NoMatch(t *testing.T) {
+	assert.Empty(t, language.Candidates(context.Background(), "testdata/codefiles/unknown.xyz"))
+}
+
+func lowercase(ss []string) []string {
+	var lower []string
+
+	for _, s := range ss {
+		lower = append(lower, strings.ToLower(s))
+	}
+
+	return lower
+}
+
+func unique(ss []string) map[string]struct{} {
+	set := map[string]struct{}{}
+
+	for _, s := range ss {
+		set[s] = struct{}{}
+	}
+
+	return set
+}
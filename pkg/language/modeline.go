@@ -0,0 +1,298 @@
+package language
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/file"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+)
+
+const (
+	// maxModelineLines is the number of lines at the start and end of a file
+	// searched for modelines, same as Kate.
+	maxModelineLines = 10
+	// maxModelineTailSize is the number of bytes read from the end of a file
+	// larger than maxFileSize to find modelines.
+	maxModelineTailSize = 4096
+)
+
+var (
+	emacsModelineRegex      = regexp.MustCompile(`-\*-(.+?)-\*-`)
+	emacsLocalVariableRegex = regexp.MustCompile(`(?i)^\W*mode:\s*([^\s;]+)`)
+	kateModelineRegex       = regexp.MustCompile(`kate:.*?\bhl\s+([^;]+)`)
+)
+
+// detectModeline detects the language from a Vim, Emacs or Kate modeline
+// within the first or last lines of the file. The weight is the weight of the
+// language's lexer analysing the modeline text.
+func detectModeline(ctx context.Context, fp string) (heartbeat.Language, float32, bool) {
+	logger := log.Extract(ctx)
+
+	text, err := modelineText(ctx, fp)
+	if err != nil {
+		logger.Debugf("failed to load modeline text from file %q: %s", fp, err)
+		return heartbeat.LanguageUnknown, 0, false
+	}
+
+	if text == "" {
+		return heartbeat.LanguageUnknown, 0, false
+	}
+
+	for _, detect := range []func(string) (heartbeat.Language, float32, bool){
+		detectVimModeline,
+		detectEmacsModeline,
+		detectKateModeline,
+	} {
+		if language, weight, ok := detect(text); ok {
+			return language, weight, true
+		}
+	}
+
+	return heartbeat.LanguageUnknown, 0, false
+}
+
+// modelineText returns the first and last maxModelineLines lines of the file.
+func modelineText(ctx context.Context, fp string) (string, error) {
+	head, err := fileHead(ctx, fp)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r\n", "\n"), "\n")
+
+	if len(head) >= maxFileSize {
+		tail, err := fileTail(ctx, fp, maxModelineTailSize)
+		if err != nil {
+			return "", err
+		}
+
+		first := lines
+		if len(first) > maxModelineLines {
+			first = first[:maxModelineLines]
+		}
+
+		lines = append(first, strings.Split(strings.ReplaceAll(string(tail), "\r\n", "\n"), "\n")...)
+	}
+
+	// ignore the empty line after the last line break
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > maxModelineLines*2 {
+		lines = append(lines[:maxModelineLines], lines[len(lines)-maxModelineLines:]...)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// fileTail returns the last size bytes of the file's content.
+func fileTail(ctx context.Context, fp string, size int64) ([]byte, error) {
+	logger := log.Extract(ctx)
+
+	f, err := file.OpenNoLock(fp) // nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %s", err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Debugf("failed to close file '%s': %s", fp, err)
+		}
+	}()
+
+	if _, err := f.Seek(-size, io.SeekEnd); err != nil {
+		return nil, fmt.Errorf("failed to seek file: %s", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bytes from file: %s", err)
+	}
+
+	return data, nil
+}
+
+// detectEmacsModeline tries to detect the language from the emacs mode of the
+// -*- mode: python -*- line or of a Local Variables block. Same as emacs, the
+// -*- line must be the first line, or the second line after a shebang.
+func detectEmacsModeline(text string) (heartbeat.Language, float32, bool) {
+	var mode string
+
+	if matches := emacsModelineRegex.FindStringSubmatch(emacsFirstLine(text)); matches != nil {
+		vars := strings.TrimSpace(matches[1])
+
+		if !strings.Contains(vars, ":") {
+			// -*- python -*-
+			mode = vars
+		}
+
+		for _, v := range strings.Split(vars, ";") {
+			key, value, ok := strings.Cut(v, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "mode") {
+				mode = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	if mode == "" {
+		mode = emacsLocalVariablesMode(text)
+	}
+
+	if mode == "" {
+		return heartbeat.LanguageUnknown, 0, false
+	}
+
+	lang, ok := parseEmacs(mode)
+	if !ok {
+		return heartbeat.LanguageUnknown, 0, false
+	}
+
+	return lang, modelineWeight(lang, text), true
+}
+
+// emacsFirstLine returns the line emacs searches for -*- variables, which is
+// the first line, or the second line if the first line is a shebang.
+func emacsFirstLine(text string) string {
+	lines := strings.SplitN(text, "\n", 3)
+
+	if len(lines) > 1 && strings.HasPrefix(lines[0], "#!") {
+		return lines[1]
+	}
+
+	return lines[0]
+}
+
+// emacsLocalVariablesMode returns the mode of a Local Variables block:
+//
+//	;; Local Variables:
+//	;; mode: lisp
+//	;; End:
+func emacsLocalVariablesMode(text string) string {
+	var block bool
+
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case strings.Contains(line, "Local Variables:"):
+			block = true
+		case block && strings.Contains(line, "End:"):
+			block = false
+		case block:
+			if matches := emacsLocalVariableRegex.FindStringSubmatch(line); matches != nil {
+				return matches[1]
+			}
+		}
+	}
+
+	return ""
+}
+
+// detectKateModeline tries to detect the language from the highlighting of
+// the kate modeline, e.g. kate: hl Python; indent-width 4;.
+func detectKateModeline(text string) (heartbeat.Language, float32, bool) {
+	matches := kateModelineRegex.FindStringSubmatch(text)
+	if matches == nil {
+		return heartbeat.LanguageUnknown, 0, false
+	}
+
+	lang, ok := parseKate(strings.TrimSpace(matches[1]))
+	if !ok {
+		return heartbeat.LanguageUnknown, 0, false
+	}
+
+	return lang, modelineWeight(lang, text), true
+}
+
+// modelineWeight returns the weight of the language's lexer analysing text, or
+// 0 if the lexer has no analyser.
+func modelineWeight(lang heartbeat.Language, text string) float32 {
+	lexer := lexers.Get(lang.StringChroma())
+	if lexer == nil {
+		return 0
+	}
+
+	analyser, ok := lexer.(chroma.Analyser)
+	if !ok {
+		return 0
+	}
+
+	return analyser.AnalyseText(text)
+}
+
+// parseEmacs parses the language from an emacs major mode name.
+func parseEmacs(mode string) (heartbeat.Language, bool) {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	mode = strings.TrimSuffix(mode, "-mode")
+	mode = strings.TrimSuffix(mode, "-ts")
+
+	switch mode {
+	case "asm":
+		return heartbeat.ParseLanguage("assembly")
+	case "cperl":
+		return heartbeat.ParseLanguage("perl")
+	case "elisp", "lisp-interaction":
+		return heartbeat.ParseLanguage("emacs lisp")
+	case "ess-r":
+		return heartbeat.ParseLanguage("r")
+	case "fundamental":
+		return heartbeat.ParseLanguage("text")
+	case "html-helper", "mhtml", "web":
+		return heartbeat.ParseLanguage("html")
+	case "js", "js2", "js3":
+		return heartbeat.ParseLanguage("javascript")
+	case "lisp":
+		return heartbeat.ParseLanguage("common lisp")
+	case "makefile-bsdmake", "makefile-gmake":
+		return heartbeat.ParseLanguage("makefile")
+	case "nxml":
+		return heartbeat.ParseLanguage("xml")
+	case "objc":
+		return heartbeat.ParseLanguage("objective-c")
+	case "protobuf":
+		return heartbeat.ParseLanguage("protocol buffer")
+	case "rjsx":
+		return heartbeat.ParseLanguage("jsx")
+	case "sh", "shell-script":
+		return heartbeat.ParseLanguage("bash")
+	case "tuareg":
+		return heartbeat.ParseLanguage("ocaml")
+	case "typescript-tsx":
+		return heartbeat.ParseLanguage("tsx")
+	default:
+		return heartbeat.ParseLanguage(mode)
+	}
+}
+
+// parseKate parses the language from a kate highlighting name.
+func parseKate(hl string) (heartbeat.Language, bool) {
+	switch strings.ToLower(strings.TrimSpace(hl)) {
+	case "gnu assembler":
+		return heartbeat.ParseLanguage("assembly")
+	case "ini files":
+		return heartbeat.ParseLanguage("ini")
+	case "intel x86 (nasm)":
+		return heartbeat.ParseLanguage("nasm")
+	case "iso c++":
+		return heartbeat.ParseLanguage("c++")
+	case "javascript react (jsx)":
+		return heartbeat.ParseLanguage("jsx")
+	case "php (html)", "php/php":
+		return heartbeat.ParseLanguage("php")
+	case "ruby/rails/rhtml":
+		return heartbeat.ParseLanguage("ruby")
+	case "typescript react (tsx)":
+		return heartbeat.ParseLanguage("tsx")
+	case "zsh":
+		return heartbeat.ParseLanguage("bash")
+	default:
+		return heartbeat.ParseLanguage(hl)
+	}
+}
@@ -0,0 +1,150 @@
+package language
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectEmacsModeline(t *testing.T) {
+	tests := map[string]struct {
+		Text     string
+		Language heartbeat.Language
+	}{
+		"mode": {
+			Text:     "# -*- mode: python; coding: utf-8 -*-",
+			Language: heartbeat.LanguagePython,
+		},
+		"mode only": {
+			Text:     "/* -*- c++ -*- */",
+			Language: heartbeat.LanguageCPP,
+		},
+		"mode suffix": {
+			Text:     ";; -*- mode: emacs-lisp-mode -*-",
+			Language: heartbeat.LanguageEmacsLisp,
+		},
+		"alias": {
+			Text:     "// -*- mode: js2; indent-tabs-mode: nil -*-",
+			Language: heartbeat.LanguageJavaScript,
+		},
+		"tree sitter mode": {
+			Text:     "// -*- mode: rust-ts -*-",
+			Language: heartbeat.LanguageRust,
+		},
+		"after shebang": {
+			Text:     "#!/usr/bin/env bash\n# -*- mode: sh -*-\necho hello",
+			Language: heartbeat.LanguageBash,
+		},
+		"local variables": {
+			Text: strings.Join([]string{
+				"# Local Variables:",
+				"# indent-tabs-mode: nil",
+				"# mode: sh",
+				"# End:",
+			}, "\n"),
+			Language: heartbeat.LanguageBash,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lang, _, ok := detectEmacsModeline(test.Text)
+			require.True(t, ok)
+
+			assert.Equal(t, test.Language, lang, fmt.Sprintf("got: %q, want: %q", lang, test.Language))
+		})
+	}
+}
+
+func TestDetectEmacsModeline_NotFound(t *testing.T) {
+	tests := map[string]string{
+		"no modeline":      "print('hello')",
+		"unknown mode":     "-*- mode: unknown-major -*-",
+		"no mode variable": "-*- coding: utf-8 -*-",
+		"outside block":    "# mode: python",
+		"second line":      "print('hello')\n# -*- mode: ruby -*-",
+		"third line":       "#!/bin/sh\n\n# -*- mode: ruby -*-",
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, _, ok := detectEmacsModeline(text)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestDetectKateModeline(t *testing.T) {
+	tests := map[string]struct {
+		Text     string
+		Language heartbeat.Language
+	}{
+		"hl": {
+			Text:     "# kate: hl Python; indent-width 4;",
+			Language: heartbeat.LanguagePython,
+		},
+		"hl last": {
+			Text:     "// kate: space-indent on; hl C++",
+			Language: heartbeat.LanguageCPP,
+		},
+		"alias": {
+			Text:     "; kate: hl INI Files; remove-trailing-spaces all;",
+			Language: heartbeat.LanguageINI,
+		},
+		"zsh": {
+			Text:     "# kate: hl Zsh;",
+			Language: heartbeat.LanguageBash,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lang, _, ok := detectKateModeline(test.Text)
+			require.True(t, ok)
+
+			assert.Equal(t, test.Language, lang, fmt.Sprintf("got: %q, want: %q", lang, test.Language))
+		})
+	}
+}
+
+func TestModelineText(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	fp := filepath.Join(t.TempDir(), "file.txt")
+
+	err := os.WriteFile(fp, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+	require.NoError(t, err)
+
+	text, err := modelineText(context.Background(), fp)
+	require.NoError(t, err)
+
+	expected := append(append([]string{}, lines[:10]...), lines[20:]...)
+
+	assert.Equal(t, strings.Join(expected, "\n"), text)
+}
+
+func TestModelineText_LargeFile(t *testing.T) {
+	fp := filepath.Join(t.TempDir(), "file.txt")
+
+	data := "first line\n" + strings.Repeat("x", maxFileSize) + "\n# vim: ft=python\n"
+
+	err := os.WriteFile(fp, []byte(data), 0600)
+	require.NoError(t, err)
+
+	text, err := modelineText(context.Background(), fp)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(text, "first line\n"))
+	assert.True(t, strings.HasSuffix(text, "\n# vim: ft=python"))
+}
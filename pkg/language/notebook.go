@@ -0,0 +1,140 @@
+package language
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/file"
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// maxNotebookSize is the maximum size of a notebook file read. Cells are
+// skipped token by token, so they are not held in memory. Default is 64Mb.
+const maxNotebookSize = 64 * 1024 * 1024
+
+// notebookMetadata contains the language of the metadata of a notebook.
+type notebookMetadata struct {
+	KernelSpec struct {
+		Language string `json:"language"`
+	} `json:"kernelspec"`
+	LanguageInfo struct {
+		Name string `json:"name"`
+	} `json:"language_info"`
+}
+
+// detectNotebook detects the language of Jupyter notebooks from the language
+// of their kernel.
+func detectNotebook(ctx context.Context, fp string) (heartbeat.Language, bool) {
+	if !strings.EqualFold(filepath.Ext(fp), ".ipynb") {
+		return heartbeat.LanguageUnknown, false
+	}
+
+	logger := log.Extract(ctx)
+
+	name, err := readNotebookLanguage(ctx, fp)
+	if err != nil {
+		logger.Debugf("failed to read notebook language from file %q: %s", fp, err)
+		return heartbeat.LanguageUnknown, false
+	}
+
+	language, ok := heartbeat.ParseLanguage(name)
+	if !ok {
+		logger.Debugf("unknown notebook language %q", name)
+		return heartbeat.LanguageUnknown, false
+	}
+
+	return language, true
+}
+
+// readNotebookLanguage returns the language name of the notebook metadata,
+// which is read from kernelspec.language or language_info.name.
+func readNotebookLanguage(ctx context.Context, fp string) (string, error) {
+	logger := log.Extract(ctx)
+
+	f, err := file.OpenNoLock(fp) // nolint:gosec
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %s", err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			logger.Debugf("failed to close file '%s': %s", fp, err)
+		}
+	}()
+
+	metadata, err := decodeNotebookMetadata(json.NewDecoder(io.LimitReader(f, maxNotebookSize)))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse notebook: %s", err)
+	}
+
+	if name := strings.TrimSpace(metadata.KernelSpec.Language); name != "" {
+		return name, nil
+	}
+
+	if name := strings.TrimSpace(metadata.LanguageInfo.Name); name != "" {
+		return name, nil
+	}
+
+	return "", fmt.Errorf("no language in notebook metadata")
+}
+
+// decodeNotebookMetadata decodes the metadata of a notebook. Other top level
+// fields, like cells, which usually come before the metadata, are skipped
+// without decoding them.
+func decodeNotebookMetadata(dec *json.Decoder) (notebookMetadata, error) {
+	var metadata notebookMetadata
+
+	if t, err := dec.Token(); err != nil {
+		return metadata, err
+	} else if t != json.Delim('{') {
+		return metadata, errors.New("notebook is not a json object")
+	}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return metadata, err
+		}
+
+		if t == "metadata" {
+			err := dec.Decode(&metadata)
+
+			return metadata, err
+		}
+
+		if err := skipJSONValue(dec); err != nil {
+			return metadata, err
+		}
+	}
+
+	return metadata, errors.New("no metadata in notebook")
+}
+
+// skipJSONValue skips the next json value of the decoder token by token.
+func skipJSONValue(dec *json.Decoder) error {
+	var depth int
+
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package language
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/shebang"
+)
+
+// maxShebangSize is the maximum number of bytes read to find a shebang line.
+const maxShebangSize = 1024
+
+// interpreterVersionRegex matches the version suffix of interpreters, e.g. 3.12
+// of python3.12 or -3.0 of guile-3.0.
+var interpreterVersionRegex = regexp.MustCompile(`[-.]?\d+(?:\.\d+)*$`)
+
+// detectShebang detects the language from the interpreter of the shebang line
+// of the file.
+func detectShebang(ctx context.Context, fp string) (heartbeat.Language, bool) {
+	logger := log.Extract(ctx)
+
+	line, err := fileFirstLine(ctx, fp)
+	if err != nil {
+		logger.Debugf("failed to read first line from file %q: %s", fp, err)
+		return heartbeat.LanguageUnknown, false
+	}
+
+	interpreter, ok := shebang.Interpreter(line)
+	if !ok {
+		return heartbeat.LanguageUnknown, false
+	}
+
+	if language, ok := parseInterpreter(interpreter); ok {
+		return language, true
+	}
+
+	// python3.12, ruby2.7
+	return parseInterpreter(interpreterVersionRegex.ReplaceAllString(interpreter, ""))
+}
+
+// fileFirstLine returns the first line of the file's content up to
+// maxShebangSize bytes.
+func fileFirstLine(ctx context.Context, fp string) (string, error) {
+	head, err := fileHead(ctx, fp)
+	if err != nil {
+		return "", err
+	}
+
+	if len(head) > maxShebangSize {
+		head = head[:maxShebangSize]
+	}
+
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i+1]
+	}
+
+	return string(head), nil
+}
+
+// parseInterpreter parses the language from a lowercase interpreter name.
+func parseInterpreter(interpreter string) (heartbeat.Language, bool) {
+	switch interpreter {
+	case "ash", "bash", "busybox", "dash", "ksh", "mksh", "sh", "zsh":
+		return heartbeat.LanguageBash, true
+	case "awk", "gawk", "mawk", "nawk":
+		return heartbeat.LanguageAwk, true
+	case "clisp", "ecl", "sbcl":
+		return heartbeat.LanguageCommonLisp, true
+	case "crystal":
+		return heartbeat.LanguageCrystal, true
+	case "csh", "tcsh":
+		return heartbeat.LanguageTcsh, true
+	case "dart":
+		return heartbeat.LanguageDart, true
+	case "elixir":
+		return heartbeat.LanguageElixir, true
+	case "emacs":
+		return heartbeat.LanguageEmacsLisp, true
+	case "escript":
+		return heartbeat.LanguageErlang, true
+	case "execlineb":
+		return heartbeat.LanguageExecline, true
+	case "expect", "tclsh", "wish":
+		return heartbeat.LanguageTcl, true
+	case "fish":
+		return heartbeat.LanguageFish, true
+	case "gnuplot":
+		return heartbeat.LanguageGnuplot, true
+	case "groovy":
+		return heartbeat.LanguageGroovy, true
+	case "guile":
+		return heartbeat.LanguageScheme, true
+	case "hy":
+		return heartbeat.LanguageHy, true
+	case "java":
+		return heartbeat.LanguageJava, true
+	case "jruby", "macruby", "rake", "rbx", "ruby", "truffleruby":
+		return heartbeat.LanguageRuby, true
+	case "julia":
+		return heartbeat.LanguageJulia, true
+	case "kotlin", "kotlinc":
+		return heartbeat.LanguageKotlin, true
+	case "lua", "luajit":
+		return heartbeat.LanguageLua, true
+	case "make":
+		return heartbeat.LanguageMakefile, true
+	case "node", "nodejs":
+		return heartbeat.LanguageJavaScript, true
+	case "nu":
+		return heartbeat.LanguageNushell, true
+	case "ocaml", "ocamlrun":
+		return heartbeat.LanguageOCaml, true
+	case "octave":
+		return heartbeat.LanguageOctave, true
+	case "osascript":
+		return heartbeat.LanguageAppleScript, true
+	case "perl":
+		return heartbeat.LanguagePerl, true
+	case "perl6", "raku", "rakudo":
+		return heartbeat.LanguageRaku, true
+	case "php":
+		return heartbeat.LanguagePHP, true
+	case "pike":
+		return heartbeat.LanguagePike, true
+	case "powershell", "pwsh":
+		return heartbeat.LanguagePowerShell, true
+	case "pypy", "python", "pythonw":
+		return heartbeat.LanguagePython, true
+	case "racket":
+		return heartbeat.LanguageRacket, true
+	case "rexx", "regina":
+		return heartbeat.LanguageRexx, true
+	case "rscript":
+		return heartbeat.LanguageR, true
+	case "runghc", "runhaskell":
+		return heartbeat.LanguageHaskell, true
+	case "scala":
+		return heartbeat.LanguageScala, true
+	case "sed":
+		return heartbeat.LanguageSed, true
+	case "swift":
+		return heartbeat.LanguageSwift, true
+	case "ts-node", "tsx":
+		return heartbeat.LanguageTypeScript, true
+	default:
+		return heartbeat.LanguageUnknown, false
+	}
+}
@@ -0,0 +1,6 @@
+Require Import Arith.
+
+Theorem plus_zero : forall n : nat, n + 0 = n.
+Proof.
+  intros n. induction n; simpl; auto.
+Qed.
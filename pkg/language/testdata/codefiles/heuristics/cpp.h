@@ -0,0 +1,10 @@
+#pragma once
+
+#include <string>
+
+namespace wakatime {
+class Person {
+public:
+    std::string name;
+};
+}
@@ -0,0 +1,8 @@
+using System;
+
+namespace WakaTime
+{
+    public class Program
+    {
+    }
+}
@@ -0,0 +1,4 @@
+#!/usr/bin/env escript
+%% -*- erlang -*-
+main(_) ->
+    io:format("hello~n").
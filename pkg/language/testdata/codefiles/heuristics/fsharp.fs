@@ -0,0 +1,5 @@
+module Program
+
+open System
+
+let square x = x * x
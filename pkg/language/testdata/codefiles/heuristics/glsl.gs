@@ -0,0 +1,4 @@
+#version 330 core
+layout (points) in;
+layout (points, max_vertices = 1) out;
+void main() { EmitVertex(); }
@@ -0,0 +1,3 @@
+uses java.util.*
+
+var list = new ArrayList<String>()
@@ -0,0 +1,3 @@
+pro hello
+  print, 'hello'
+end
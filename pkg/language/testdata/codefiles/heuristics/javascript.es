@@ -0,0 +1,5 @@
+'use strict'
+
+export default function hello() {
+  return 'hello'
+}
@@ -0,0 +1,2 @@
+square[x_] := x^2
+square[3]
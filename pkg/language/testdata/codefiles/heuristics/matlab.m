@@ -0,0 +1,4 @@
+function y = square(x)
+% square returns the square of x
+y = x .^ 2;
+end
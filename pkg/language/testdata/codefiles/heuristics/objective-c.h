@@ -0,0 +1,5 @@
+#import <Foundation/Foundation.h>
+
+@interface Person : NSObject
+@property NSString *name;
+@end
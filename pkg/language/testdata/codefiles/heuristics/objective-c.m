@@ -0,0 +1,4 @@
+#import "Person.h"
+
+@implementation Person
+@end
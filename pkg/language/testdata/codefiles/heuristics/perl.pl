@@ -0,0 +1,5 @@
+#!/usr/bin/perl
+use strict;
+use warnings;
+
+print "hello\n";
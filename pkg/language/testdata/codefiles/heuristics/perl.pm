@@ -0,0 +1,4 @@
+package WakaTime;
+use strict;
+
+1;
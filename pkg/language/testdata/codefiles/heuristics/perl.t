@@ -0,0 +1,4 @@
+use strict;
+use Test::More tests => 1;
+
+ok(1, 'works');
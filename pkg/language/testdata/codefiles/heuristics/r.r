@@ -0,0 +1,3 @@
+library(stats)
+x <- c(1, 2, 3)
+print(mean(x))
@@ -0,0 +1,3 @@
+use v6;
+
+say "hello";
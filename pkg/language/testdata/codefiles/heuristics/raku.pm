@@ -0,0 +1,3 @@
+unit module WakaTime;
+
+sub hello is export { say "hello" }
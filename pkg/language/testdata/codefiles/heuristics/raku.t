@@ -0,0 +1,4 @@
+use v6;
+use Test;
+
+ok True, 'works';
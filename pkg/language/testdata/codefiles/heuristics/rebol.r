@@ -0,0 +1,4 @@
+REBOL [
+    Title: "Hello"
+]
+print "hello"
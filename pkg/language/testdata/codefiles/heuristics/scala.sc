@@ -0,0 +1,5 @@
+import scala.collection.mutable
+
+object Hello {
+  def main(args: Array[String]): Unit = println("hello")
+}
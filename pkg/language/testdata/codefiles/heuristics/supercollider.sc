@@ -0,0 +1,4 @@
+Hello {
+	*new { ^super.new.init }
+	init { ^this.postln }
+}
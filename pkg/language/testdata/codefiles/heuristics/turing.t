@@ -0,0 +1,3 @@
+% print a greeting
+var name : string := "world"
+put "hello ", name
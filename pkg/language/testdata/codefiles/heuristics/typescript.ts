@@ -0,0 +1,3 @@
+import { hello } from './hello'
+
+export const greeting: string = hello()
@@ -0,0 +1,8 @@
+`timescale 1ns / 1ps
+
+module counter (
+  input clk,
+  output reg [3:0] count
+);
+  always @(posedge clk) count <= count + 1;
+endmodule
@@ -5,12 +5,9 @@ import (
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
-
-	"github.com/alecthomas/chroma/v2"
-	"github.com/alecthomas/chroma/v2/lexers"
 )
 
-var modelineRegex = regexp.MustCompile(`(?m)(?:vi|vim|ex)(?:[<=>]?\d*)?:.*(?:ft|filetype|syn|syntax)=([^:\s]+)`)
+var modelineRegex = regexp.MustCompile(`(?m)(?:^|\s)(?:vi|vim|Vim|ex)(?:[<=>]?\d*)?:.*(?:ft|filetype|syn|syntax)=([^:\s]+)`)
 
 // detectVimModeline tries to detect the language from the vim modeline.
 func detectVimModeline(text string) (heartbeat.Language, float32, bool) {
@@ -25,102 +22,58 @@ func detectVimModeline(text string) (heartbeat.Language, float32, bool) {
 		return heartbeat.LanguageUnknown, 0, false
 	}
 
-	lexer := lexers.Get(lang.StringChroma())
-	if lexer == nil {
-		return heartbeat.LanguageUnknown, 0, false
-	}
-
-	analyser, ok := lexer.(chroma.Analyser)
-	if !ok {
-		return heartbeat.LanguageUnknown, 0, false
-	}
+	return lang, modelineWeight(lang, text), true
+}
 
-	return lang, analyser.AnalyseText(text), true
+// vimFileTypes maps vim file types to language names.
+var vimFileTypes = map[string]string{
+	"a65":         "assembly",
+	"asm":         "assembly",
+	"asm68k":      "assembly",
+	"asmh8300":    "assembly",
+	"basic":       "basic",
+	"c":           "c",
+	"cpp":         "cpp",
+	"crontab":     "crontab",
+	"cs":          "csharp",
+	"haml":        "haml",
+	"haskell":     "haskell",
+	"html":        "html",
+	"htmlcheetah": "html",
+	"htmldjango":  "html",
+	"htmlm4":      "html",
+	"java":        "java",
+	"javascript":  "javascript",
+	"lhaskell":    "haskell",
+	"markdown":    "markdown",
+	"objc":        "objectivec",
+	"objcpp":      "objectivecpp",
+	"ocaml":       "ocaml",
+	"perl":        "perl",
+	"perl6":       "perl",
+	"php":         "php",
+	"phtml":       "php",
+	"prolog":      "prolog",
+	"python":      "python",
+	"r":           "r",
+	"ruby":        "ruby",
+	"sass":        "sass",
+	"scheme":      "scheme",
+	"scss":        "scss",
+	"skill":       "skill",
+	"vb":          "vbnet",
+	"vim":         "viml",
+	"xhtml":       "html",
+	"xml":         "xml",
+	"yaml":        "yaml",
 }
 
-// nolint:gocyclo
 // parseVim parses the language from a vim plugin specific string.
 func parseVim(language string) (heartbeat.Language, bool) {
-	switch strings.ToLower(language) {
-	case strings.ToLower("a65"):
-		return heartbeat.ParseLanguage("assembly")
-	case strings.ToLower("asm"):
-		return heartbeat.ParseLanguage("assembly")
-	case strings.ToLower("asm68k"):
-		return heartbeat.ParseLanguage("assembly")
-	case strings.ToLower("asmh8300"):
-		return heartbeat.ParseLanguage("assembly")
-	case strings.ToLower("basic"):
-		return heartbeat.ParseLanguage("basic")
-	case strings.ToLower("c"):
-		return heartbeat.ParseLanguage("c")
-	case strings.ToLower("cpp"):
-		return heartbeat.ParseLanguage("cpp")
-	case strings.ToLower("crontab"):
-		return heartbeat.ParseLanguage("crontab")
-	case strings.ToLower("cs"):
-		return heartbeat.ParseLanguage("csharp")
-	case strings.ToLower("haml"):
-		return heartbeat.ParseLanguage("haml")
-	case strings.ToLower("haskell"):
-		return heartbeat.ParseLanguage("haskell")
-	case strings.ToLower("html"):
-		return heartbeat.ParseLanguage("html")
-	case strings.ToLower("htmlcheetah"):
-		return heartbeat.ParseLanguage("html")
-	case strings.ToLower("htmldjango"):
-		return heartbeat.ParseLanguage("html")
-	case strings.ToLower("htmlm4"):
-		return heartbeat.ParseLanguage("html")
-	case strings.ToLower("java"):
-		return heartbeat.ParseLanguage("java")
-	case strings.ToLower("javascript"):
-		return heartbeat.ParseLanguage("javascript")
-	case strings.ToLower("lhaskell"):
-		return heartbeat.ParseLanguage("haskell")
-	case strings.ToLower("markdown"):
-		return heartbeat.ParseLanguage("markdown")
-	case strings.ToLower("objc"):
-		return heartbeat.ParseLanguage("objectivec")
-	case strings.ToLower("objcpp"):
-		return heartbeat.ParseLanguage("objectivecpp")
-	case strings.ToLower("ocaml"):
-		return heartbeat.ParseLanguage("ocaml")
-	case strings.ToLower("perl"):
-		return heartbeat.ParseLanguage("perl")
-	case strings.ToLower("perl6"):
-		return heartbeat.ParseLanguage("perl")
-	case strings.ToLower("php"):
-		return heartbeat.ParseLanguage("php")
-	case strings.ToLower("phtml"):
-		return heartbeat.ParseLanguage("php")
-	case strings.ToLower("prolog"):
-		return heartbeat.ParseLanguage("prolog")
-	case strings.ToLower("python"):
-		return heartbeat.ParseLanguage("python")
-	case strings.ToLower("r"):
-		return heartbeat.ParseLanguage("r")
-	case strings.ToLower("ruby"):
-		return heartbeat.ParseLanguage("ruby")
-	case strings.ToLower("sass"):
-		return heartbeat.ParseLanguage("sass")
-	case strings.ToLower("scheme"):
-		return heartbeat.ParseLanguage("scheme")
-	case strings.ToLower("scss"):
-		return heartbeat.ParseLanguage("scss")
-	case strings.ToLower("skill"):
-		return heartbeat.ParseLanguage("skill")
-	case strings.ToLower("vb"):
-		return heartbeat.ParseLanguage("vbnet")
-	case strings.ToLower("vim"):
-		return heartbeat.ParseLanguage("viml")
-	case strings.ToLower("xhtml"):
-		return heartbeat.ParseLanguage("html")
-	case strings.ToLower("xml"):
-		return heartbeat.ParseLanguage("xml")
-	case strings.ToLower("yaml"):
-		return heartbeat.ParseLanguage("yaml")
-	default:
+	name, ok := vimFileTypes[strings.ToLower(language)]
+	if !ok {
 		return heartbeat.LanguageUnknown, false
 	}
+
+	return heartbeat.ParseLanguage(name)
 }
@@ -1,8 +1,6 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
@@ -10,7 +8,7 @@ import (
 )
 
 // nolint:gochecknoglobals
-var actionscript3AnalyserRe = regexp.MustCompile(`\w+\s*:\s*\w`)
+var actionscript3AnalyserRe = compileLazy(`\w+\s*:\s*\w`)
 
 // ActionScript3 lexer.
 type ActionScript3 struct{}
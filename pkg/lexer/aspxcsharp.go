@@ -1,16 +1,14 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
 var (
-	csharpAspxAnalyzerPageLanguageRe   = regexp.MustCompile(`(?i)Page\s*Language="C#"`)
-	csharpAspxAnalyzerScriptLanguageRe = regexp.MustCompile(`(?i)script[^>]+language=["\']C#`)
+	csharpAspxAnalyzerPageLanguageRe   = compileLazy(`(?i)Page\s*Language="C#"`)
+	csharpAspxAnalyzerScriptLanguageRe = compileLazy(`(?i)script[^>]+language=["\']C#`)
 )
 
 // AspxCSharp lexer.
@@ -1,8 +1,6 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
@@ -10,8 +8,8 @@ import (
 
 // nolint:gochecknoglobals
 var (
-	vbAspxAnalyzerPageLanguageRe   = regexp.MustCompile(`(?i)Page\s*Language="Vb"`)
-	vbAspxAnalyzerScriptLanguageRe = regexp.MustCompile(`(?i)script[^>]+language=["\']vb`)
+	vbAspxAnalyzerPageLanguageRe   = compileLazy(`(?i)Page\s*Language="Vb"`)
+	vbAspxAnalyzerScriptLanguageRe = compileLazy(`(?i)script[^>]+language=["\']vb`)
 )
 
 // AspxVBNet lexer.
@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var bugsAnalyzerRe = regexp.MustCompile(`(?m)^\s*model\s*{`)
+var bugsAnalyzerRe = compileLazy(`(?m)^\s*model\s*{`)
 
 // BUGS lexer.
 type BUGS struct{}
@@ -1,15 +1,13 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
 // nolint:gochecknoglobals
-var ca65AnalyserCommentRe = regexp.MustCompile(`(?m)^\s*;`)
+var ca65AnalyserCommentRe = compileLazy(`(?m)^\s*;`)
 
 // Ca65Assembler lexer.
 type Ca65Assembler struct{}
@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var cbmBasicV2AnalyserRe = regexp.MustCompile(`^\d+`)
+var cbmBasicV2AnalyserRe = compileLazy(`^\d+`)
 
 // CBMBasicV2 CBM BASIC V2 lexer.
 type CBMBasicV2 struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -10,8 +9,8 @@ import (
 )
 
 var (
-	easytrieveAnalyserCommetLineRe  = regexp.MustCompile(`^\s*\*`)
-	easytrieveAnalyserMacroHeaderRe = regexp.MustCompile(`\s*MACRO`)
+	easytrieveAnalyserCommetLineRe  = compileLazy(`^\s*\*`)
+	easytrieveAnalyserMacroHeaderRe = compileLazy(`\s*MACRO`)
 )
 
 // Easytrieve lexer.
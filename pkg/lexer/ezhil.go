@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var ezhilAnalyserRe = regexp.MustCompile(`[u0b80-u0bff]`)
+var ezhilAnalyserRe = compileLazy(`[u0b80-u0bff]`)
 
 // Ezhil lexer.
 type Ezhil struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -11,7 +10,7 @@ import (
 )
 
 // nolint:gochecknoglobals
-var forthAnalyzerRe = regexp.MustCompile(`\n:[^\n]+;\n`)
+var forthAnalyzerRe = compileLazy(`\n:[^\n]+;\n`)
 
 // Forth lexer.
 type Forth struct{}
@@ -2,7 +2,6 @@ package lexer
 
 import (
 	"math"
-	"regexp"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
@@ -10,9 +9,9 @@ import (
 )
 
 var (
-	gapAnalyserDeclarationRe = regexp.MustCompile(
+	gapAnalyserDeclarationRe = compileLazy(
 		`(InstallTrueMethod|Declare(Attribute|Category|Filter|Operation|GlobalFunction|Synonym|SynonymAttr|Property))`)
-	gapAnalyserImplementationRe = regexp.MustCompile(
+	gapAnalyserImplementationRe = compileLazy(
 		`(DeclareRepresentation|Install(GlobalFunction|Method|ImmediateMethod|OtherMethod)|New(Family|Type)|Objectify)`)
 )
 
@@ -1,8 +1,6 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
@@ -10,8 +8,8 @@ import (
 )
 
 var (
-	gasAnalyzerDirectiveRe      = regexp.MustCompile(`(?m)^\.(text|data|section)`)
-	gasAnalyzerOtherDirectiveRe = regexp.MustCompile(`(?m)^\.\w+`)
+	gasAnalyzerDirectiveRe      = compileLazy(`(?m)^\.(text|data|section)`)
+	gasAnalyzerOtherDirectiveRe = compileLazy(`(?m)^\.\w+`)
 )
 
 // Gas lexer.
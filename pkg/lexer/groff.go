@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"unicode"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -11,7 +10,7 @@ import (
 )
 
 // nolint:gochecknoglobals
-var groffAlphanumericRe = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+var groffAlphanumericRe = compileLazy(`^[a-zA-Z0-9]+$`)
 
 // Groff lexer.
 type Groff struct{}
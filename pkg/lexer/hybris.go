@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var hybrisAnalyserRe = regexp.MustCompile(`\b(?:public|private)\s+method\b`)
+var hybrisAnalyserRe = compileLazy(`\b(?:public|private)\s+method\b`)
 
 // Hybris lexer.
 type Hybris struct{}
@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var inform6AnalyserRe = regexp.MustCompile(`(?i)\borigsource\b`)
+var inform6AnalyserRe = compileLazy(`(?i)\borigsource\b`)
 
 // Inform6 lexer.
 type Inform6 struct{}
@@ -1,17 +1,15 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
 var (
-	jagsAnalyserModelRe = regexp.MustCompile(`(?m)^\s*model\s*\{`)
-	jagsAnalyserDataRe  = regexp.MustCompile(`(?m)^\s*data\s*\{`)
-	jagsAnalyserVarRe   = regexp.MustCompile(`(?m)^\s*var`)
+	jagsAnalyserModelRe = compileLazy(`(?m)^\s*model\s*\{`)
+	jagsAnalyserDataRe  = compileLazy(`(?m)^\s*data\s*\{`)
+	jagsAnalyserVarRe   = compileLazy(`(?m)^\s*var`)
 )
 
 // JAGS lexer.
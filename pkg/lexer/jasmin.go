@@ -2,7 +2,6 @@ package lexer
 
 import (
 	"math"
-	"regexp"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
@@ -10,9 +9,9 @@ import (
 )
 
 var (
-	jasminAnalyserClassRe       = regexp.MustCompile(`(?m)^\s*\.class\s`)
-	jasminAnalyserInstructionRe = regexp.MustCompile(`(?m)^\s*[a-z]+_[a-z]+\b`)
-	jasminAnalyserKeywordsRe    = regexp.MustCompile(
+	jasminAnalyserClassRe       = compileLazy(`(?m)^\s*\.class\s`)
+	jasminAnalyserInstructionRe = compileLazy(`(?m)^\s*[a-z]+_[a-z]+\b`)
+	jasminAnalyserKeywordsRe    = compileLazy(
 		`(?m)^\s*\.(attribute|bytecode|debug|deprecated|enclosing|inner|interface|limit|set|signature|stack)\b`)
 )
 
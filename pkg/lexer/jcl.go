@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -9,7 +8,7 @@ import (
 	"github.com/alecthomas/chroma/v2"
 )
 
-var jclAnalyserJobHeaderRe = regexp.MustCompile(`(?i)^//[a-z#$@][a-z0-9#$@]{0,7}\s+job(\s+.*)?$`)
+var jclAnalyserJobHeaderRe = compileLazy(`(?i)^//[a-z#$@][a-z0-9#$@]{0,7}\s+job(\s+.*)?$`)
 
 // JCL lexer.
 type JCL struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -10,8 +9,8 @@ import (
 )
 
 var (
-	lassoAnalyserDelimiterRe = regexp.MustCompile(`(?i)<\?lasso`)
-	lassoAnalyserLocalRe     = regexp.MustCompile(`(?i)local\(`)
+	lassoAnalyserDelimiterRe = compileLazy(`(?i)<\?lasso`)
+	lassoAnalyserLocalRe     = compileLazy(`(?i)local\(`)
 )
 
 // Lasso lexer.
@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/alecthomas/chroma/v2"
 	l "github.com/alecthomas/chroma/v2/lexers"
@@ -13,8 +14,23 @@ type Lexer interface {
 	Name() string
 }
 
-// RegisterAll registers all custom lexers.
+// registerOnce registers all custom lexers on first call.
+// nolint:gochecknoglobals
+var registerOnce = sync.OnceValue(func() error {
+	return RegisterTo(l.GlobalLexerRegistry)
+})
+
+// RegisterAll registers all custom lexers. Lexers are registered only once, so
+// it can be called cheaply right before detecting a language. Registration
+// only adds the lexer configs, like names, filenames and mime types, to the
+// chroma registry. The rule tables of a lexer are built on first use.
 func RegisterAll() error {
+	return registerOnce()
+}
+
+// RegisterTo registers all custom lexers to the chroma registry. Use RegisterAll
+// to register them to the global registry.
+func RegisterTo(registry *chroma.LexerRegistry) error {
 	var lexers = []Lexer{
 		ADL{},
 		AMPL{},
@@ -305,13 +321,23 @@ func RegisterAll() error {
 		Zephir{},
 	}
 
+	registered := make(map[chroma.Lexer]struct{}, len(registry.Lexers))
+	for _, lexer := range registry.Lexers {
+		registered[lexer] = struct{}{}
+	}
+
 	for _, lexer := range lexers {
 		found := lexer.Lexer()
 		if found == nil {
 			return fmt.Errorf("%q lexer not found", lexer.Name())
 		}
 
-		_ = l.Register(lexer.Lexer())
+		// chroma lexers customized in place are registered already
+		if _, ok := registered[found]; ok {
+			continue
+		}
+
+		_ = registry.Register(found)
 	}
 
 	return nil
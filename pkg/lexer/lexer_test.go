@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/lexer"
 
+	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -311,3 +313,42 @@ func TestChromaLanguagesRegister(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterAll(t *testing.T) {
+	err := lexer.RegisterAll()
+	require.NoError(t, err)
+
+	registered := len(lexers.GlobalLexerRegistry.Lexers)
+
+	err = lexer.RegisterAll()
+	require.NoError(t, err)
+
+	assert.Len(t, lexers.GlobalLexerRegistry.Lexers, registered)
+	assert.NotNil(t, lexers.Get(heartbeat.LanguageAgda.StringChroma()))
+}
+
+func TestRegisterTo(t *testing.T) {
+	registered := len(lexers.GlobalLexerRegistry.Lexers)
+
+	registry := chroma.NewLexerRegistry()
+
+	err := lexer.RegisterTo(registry)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, registry.Lexers)
+	assert.NotNil(t, registry.Get(heartbeat.LanguageAgda.StringChroma()))
+	assert.Len(t, lexers.GlobalLexerRegistry.Lexers, registered)
+}
+
+// BenchmarkRegisterAll measures the registration right before detecting a
+// language, after the lexers were registered on first call.
+func BenchmarkRegisterAll(b *testing.B) {
+	err := lexer.RegisterAll()
+	require.NoError(b, err)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_ = lexer.RegisterAll()
+	}
+}
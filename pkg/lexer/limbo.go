@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var limboAnalyzerRe = regexp.MustCompile(`(?m)^implement \w+;`)
+var limboAnalyzerRe = compileLazy(`(?m)^implement \w+;`)
 
 // Limbo lexer.
 type Limbo struct{}
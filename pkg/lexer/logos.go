@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var logosAnalyserKeywordsRe = regexp.MustCompile(`%(?:hook|ctor|init|c\()`)
+var logosAnalyserKeywordsRe = compileLazy(`%(?:hook|ctor|init|c\()`)
 
 // Logos lexer.
 type Logos struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -9,7 +8,7 @@ import (
 	"github.com/alecthomas/chroma/v2"
 )
 
-var logtalkAnalyserSyntaxRe = regexp.MustCompile(`(?m)^:-\s[a-z]`)
+var logtalkAnalyserSyntaxRe = compileLazy(`(?m)^:-\s[a-z]`)
 
 // Logtalk lexer.
 type Logtalk struct{}
@@ -1,15 +1,13 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/lexers"
 )
 
-var makefileAnalyserVariableRe = regexp.MustCompile(`\$\([A-Z_]+\)`)
+var makefileAnalyserVariableRe = compileLazy(`\$\([A-Z_]+\)`)
 
 // Makefile lexer.
 type Makefile struct{}
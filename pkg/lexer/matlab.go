@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -11,8 +10,8 @@ import (
 )
 
 var (
-	matlabAnalyserCommentRe   = regexp.MustCompile(`^\s*%`)
-	matlabAnalyserSystemCMDRe = regexp.MustCompile(`^!\w+`)
+	matlabAnalyserCommentRe   = compileLazy(`^\s*%`)
+	matlabAnalyserSystemCMDRe = compileLazy(`^!\w+`)
 )
 
 // Matlab lexer.
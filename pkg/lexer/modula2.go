@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -11,8 +10,8 @@ import (
 )
 
 var (
-	modula2AnalyserProcedureRe = regexp.MustCompile(`\bPROCEDURE\b`)
-	modula2AnalyserFunctionRe  = regexp.MustCompile(`\bFUNCTION\b`)
+	modula2AnalyserProcedureRe = compileLazy(`\bPROCEDURE\b`)
+	modula2AnalyserFunctionRe  = compileLazy(`\bFUNCTION\b`)
 )
 
 // Modula2 lexer.
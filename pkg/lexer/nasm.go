@@ -1,15 +1,13 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/alecthomas/chroma/v2"
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2/lexers"
 )
 
-var nasmAnalyzerRe = regexp.MustCompile(`(?i)PROC`)
+var nasmAnalyzerRe = compileLazy(`(?i)PROC`)
 
 // NASM lexer.
 type NASM struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -16,11 +15,11 @@ var (
 	// JavaDoc/Doxygen keywords that only apply to Objective-C, mind.
 	//
 	// The upshot of this is that we CANNOT match @class or @interface.
-	objectiveCAnalyserKeywordsRe = regexp.MustCompile(`@(?:end|implementation|protocol)`)
+	objectiveCAnalyserKeywordsRe = compileLazy(`@(?:end|implementation|protocol)`)
 	// Matches [ <ws>? identifier <ws> ( identifier <ws>? ] |  identifier? : )
 	// (note the identifier is *optional* when there is a ':'!)
-	objectiveCAnalyserMessageRe  = regexp.MustCompile(`\[\s*[a-zA-Z_]\w*\s+(?:[a-zA-Z_]\w*\s*\]|(?:[a-zA-Z_]\w*)?:)`)
-	objectiveCAnalyserNSNumberRe = regexp.MustCompile(`@[0-9]+`)
+	objectiveCAnalyserMessageRe  = compileLazy(`\[\s*[a-zA-Z_]\w*\s+(?:[a-zA-Z_]\w*\s*\]|(?:[a-zA-Z_]\w*)?:)`)
+	objectiveCAnalyserNSNumberRe = compileLazy(`@[0-9]+`)
 )
 
 // ObjectiveC lexer.
@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var objectiveJAnalyserImportRe = regexp.MustCompile(`(?m)^\s*@import\s+[<"]`)
+var objectiveJAnalyserImportRe = compileLazy(`(?m)^\s*@import\s+[<"]`)
 
 // ObjectiveJ lexer.
 type ObjectiveJ struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -11,7 +10,7 @@ import (
 	"github.com/alecthomas/chroma/v2/lexers"
 )
 
-var perlAnalyserRe = regexp.MustCompile(`(?:my|our)\s+[$@%(]`)
+var perlAnalyserRe = compileLazy(`(?:my|our)\s+[$@%(]`)
 
 // Perl lexer.
 type Perl struct{}
@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -11,13 +10,13 @@ import (
 )
 
 var (
-	perl6AnalyserDecl      = regexp.MustCompile(`(?:my|our|has)\s+(?:['\w:-]+\s+)?[$@%&(]`)
-	perl6AnalyserDeclScope = regexp.MustCompile(`^\s*(?:(?P<scope>my|our)\s+)?(?:module|class|role|enum|grammar)`)
-	perl6AnalyserOperator  = regexp.MustCompile(`#.*`)
-	perl6AnalyserShell     = regexp.MustCompile(`^\s*$`)
-	perl6AnalyserV6        = regexp.MustCompile(`^\s*(?:use\s+)?v6(?:\.\d(?:\.\d)?)?;`)
-	perl6BeginPodRe        = regexp.MustCompile(`^=\w+`)
-	perl6EndPodRe          = regexp.MustCompile(`^=(?:end|cut)`)
+	perl6AnalyserDecl      = compileLazy(`(?:my|our|has)\s+(?:['\w:-]+\s+)?[$@%&(]`)
+	perl6AnalyserDeclScope = compileLazy(`^\s*(?:(?P<scope>my|our)\s+)?(?:module|class|role|enum|grammar)`)
+	perl6AnalyserOperator  = compileLazy(`#.*`)
+	perl6AnalyserShell     = compileLazy(`^\s*$`)
+	perl6AnalyserV6        = compileLazy(`^\s*(?:use\s+)?v6(?:\.\d(?:\.\d)?)?;`)
+	perl6BeginPodRe        = compileLazy(`^=\w+`)
+	perl6EndPodRe          = compileLazy(`^=(?:end|cut)`)
 )
 
 // Perl6 lexer.
@@ -1,16 +1,14 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
 var (
-	rebolAnalyserHeaderRe              = regexp.MustCompile(`^\s*REBOL\s*\[`)
-	rebolAnalyserHeaderPrecedingTextRe = regexp.MustCompile(`\s*REBOL\s*\[`)
+	rebolAnalyserHeaderRe              = compileLazy(`^\s*REBOL\s*\[`)
+	rebolAnalyserHeaderPrecedingTextRe = compileLazy(`\s*REBOL\s*\[`)
 )
 
 // REBOL lexer.
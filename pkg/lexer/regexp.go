@@ -0,0 +1,60 @@
+package lexer
+
+import (
+	"regexp"
+	"sync"
+)
+
+// lazyRegexp is a regular expression, which is compiled on first use. This
+// keeps the analysers of the lexers from compiling their regular expressions
+// on startup of commands, which don't detect languages at all.
+type lazyRegexp struct {
+	expr string
+	once sync.Once
+	rgx  *regexp.Regexp
+}
+
+// compileLazy returns a regular expression, which is compiled on first use.
+// It panics on first use, if the expression cannot be parsed.
+func compileLazy(expr string) *lazyRegexp {
+	return &lazyRegexp{expr: expr}
+}
+
+// MatchString reports whether the string s contains any match of the regular
+// expression.
+func (r *lazyRegexp) MatchString(s string) bool {
+	return r.compiled().MatchString(s)
+}
+
+// FindAllString returns a slice of all successive matches of the regular
+// expression. If n >= 0, it returns at most n matches.
+func (r *lazyRegexp) FindAllString(s string, n int) []string {
+	return r.compiled().FindAllString(s, n)
+}
+
+// FindStringSubmatch returns a slice of strings holding the text of the
+// leftmost match of the regular expression and the matches of its
+// subexpressions.
+func (r *lazyRegexp) FindStringSubmatch(s string) []string {
+	return r.compiled().FindStringSubmatch(s)
+}
+
+// ReplaceAllLiteralString returns a copy of src, replacing matches of the
+// regular expression with the replacement string repl.
+func (r *lazyRegexp) ReplaceAllLiteralString(src, repl string) string {
+	return r.compiled().ReplaceAllLiteralString(src, repl)
+}
+
+// SubexpNames returns the names of the parenthesized subexpressions of the
+// regular expression.
+func (r *lazyRegexp) SubexpNames() []string {
+	return r.compiled().SubexpNames()
+}
+
+func (r *lazyRegexp) compiled() *regexp.Regexp {
+	r.once.Do(func() {
+		r.rgx = regexp.MustCompile(r.expr)
+	})
+
+	return r.rgx
+}
@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var rslAnalyserRe = regexp.MustCompile(`(?i)scheme\s*.*?=\s*class\s*type`)
+var rslAnalyserRe = compileLazy(`(?i)scheme\s*.*?=\s*class\s*type`)
 
 // RSL lexer. RSL <http://en.wikipedia.org/wiki/RAISE> is the formal
 // specification language used in RAISE (Rigorous Approach to Industrial
@@ -1,15 +1,13 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/alecthomas/chroma/v2"
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 )
 
 var (
-	singularityAnalyserHeaderRe  = regexp.MustCompile(`(?i)\b(?:osversion|includecmd|mirrorurl)\b`)
-	singularityAnalyserSectionRe = regexp.MustCompile(
+	singularityAnalyserHeaderRe  = compileLazy(`(?i)\b(?:osversion|includecmd|mirrorurl)\b`)
+	singularityAnalyserSectionRe = compileLazy(
 		`%(?:pre|post|setup|environment|help|labels|test|runscript|files|startscript)\b`)
 )
 
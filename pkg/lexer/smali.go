@@ -1,8 +1,6 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
@@ -10,13 +8,13 @@ import (
 )
 
 var (
-	smaliAnalyserClassRe         = regexp.MustCompile(`(?m)^\s*\.class\s`)
-	smaliAnalyserClassKeywordsRe = regexp.MustCompile(
+	smaliAnalyserClassRe         = compileLazy(`(?m)^\s*\.class\s`)
+	smaliAnalyserClassKeywordsRe = compileLazy(
 		`(?m)\b((check-cast|instance-of|throw-verification-error` +
 			`)\b|(-to|add|[ais]get|[ais]put|and|cmpl|const|div|` +
 			`if|invoke|move|mul|neg|not|or|rem|return|rsub|shl` +
 			`|shr|sub|ushr)[-/])|{|}`)
-	smaliAnalyserKeywordsRe = regexp.MustCompile(
+	smaliAnalyserKeywordsRe = compileLazy(
 		`(?m)(\.(catchall|epilogue|restart local|prologue)|` +
 			`\b(array-data|class-change-error|declared-synchronized|` +
 			`(field|inline|vtable)@0x[0-9a-fA-F]|generic-error|` +
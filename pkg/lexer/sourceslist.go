@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var sourcesListAnalyserRe = regexp.MustCompile(`(?m)^\s*(deb|deb-src) `)
+var sourcesListAnalyserRe = compileLazy(`(?m)^\s*(deb|deb-src) `)
 
 // SourcesList lexer. Lexer that highlights debian sources.list files.
 type SourcesList struct{}
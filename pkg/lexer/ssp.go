@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -10,7 +9,7 @@ import (
 	"github.com/alecthomas/chroma/v2"
 )
 
-var sspAnalyserRe = regexp.MustCompile(`val \w+\s*:`)
+var sspAnalyserRe = compileLazy(`val \w+\s*:`)
 
 // SSP lexer. Lexer for Scalate Server Pages.
 type SSP struct{}
@@ -1,14 +1,12 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
-var stanAnalyserRe = regexp.MustCompile(`(?m)^\s*parameters\s*\{`)
+var stanAnalyserRe = compileLazy(`(?m)^\s*parameters\s*\{`)
 
 // Stan lexer. Lexer for Stan models.
 //
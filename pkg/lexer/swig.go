@@ -1,15 +1,13 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
 var (
-	swigAnalyserDirectivesRe = regexp.MustCompile(`(?m)^\s*(%[a-z_][a-z0-9_]*)`)
+	swigAnalyserDirectivesRe = compileLazy(`(?m)^\s*(%[a-z_][a-z0-9_]*)`)
 	// nolint:gochecknoglobals
 	swigAnalyserDirectives = map[string]struct{}{
 		// Most common directives
@@ -1,15 +1,13 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/lexers"
 )
 
-var tasmAnalyzerRe = regexp.MustCompile(`(?i)PROC`)
+var tasmAnalyzerRe = compileLazy(`(?i)PROC`)
 
 // TASM lexer.
 type TASM struct{}
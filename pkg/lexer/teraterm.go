@@ -1,7 +1,6 @@
 package lexer
 
 import (
-	"regexp"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
@@ -9,7 +8,7 @@ import (
 	"github.com/alecthomas/chroma/v2"
 )
 
-var teraTermAnalyserCommandRe = regexp.MustCompile(`(?i)\b(` + strings.Join([]string{
+var teraTermAnalyserCommandRe = compileLazy(`(?i)\b(` + strings.Join([]string{
 	"basename",
 	"beep",
 	"bplusrecv",
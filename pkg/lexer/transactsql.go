@@ -1,8 +1,6 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
@@ -10,11 +8,11 @@ import (
 )
 
 var (
-	tSQLAnalyserGoRe                  = regexp.MustCompile(`(?i)\bgo\b`)
-	tSQLAnalyserDeclareRe             = regexp.MustCompile(`(?i)\bdeclare\s+@`)
-	tSQLAnalyserVariableRe            = regexp.MustCompile(`@[a-zA-Z_]\w*\b`)
-	tSQLAnalyserNameBetweenBacktickRe = regexp.MustCompile("`[a-zA-Z_]\\w*`")
-	tSQLAnalyserNameBetweenBracketRe  = regexp.MustCompile(`\[[a-zA-Z_]\w*\]`)
+	tSQLAnalyserGoRe                  = compileLazy(`(?i)\bgo\b`)
+	tSQLAnalyserDeclareRe             = compileLazy(`(?i)\bdeclare\s+@`)
+	tSQLAnalyserVariableRe            = compileLazy(`@[a-zA-Z_]\w*\b`)
+	tSQLAnalyserNameBetweenBacktickRe = compileLazy("`[a-zA-Z_]\\w*`")
+	tSQLAnalyserNameBetweenBracketRe  = compileLazy(`\[[a-zA-Z_]\w*\]`)
 )
 
 // TransactSQL lexer.
@@ -1,18 +1,16 @@
 package lexer
 
 import (
-	"regexp"
-
 	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
 
 	"github.com/alecthomas/chroma/v2"
 )
 
 var (
-	velocityAnalzserMacroRe     = regexp.MustCompile(`(?s)#\{?macro\}?\(.*?\).*?#\{?end\}?`)
-	velocityAnalzserIfRe        = regexp.MustCompile(`(?s)#\{?if\}?\(.+?\).*?#\{?end\}?`)
-	velocityAnalzserForeachRe   = regexp.MustCompile(`(?s)#\{?foreach\}?\(.+?\).*?#\{?end\}?`)
-	velocityAnalzserReferenceRe = regexp.MustCompile(`\$!?\{?[a-zA-Z_]\w*(\([^)]*\))?(\.\w+(\([^)]*\))?)*\}?`)
+	velocityAnalzserMacroRe     = compileLazy(`(?s)#\{?macro\}?\(.*?\).*?#\{?end\}?`)
+	velocityAnalzserIfRe        = compileLazy(`(?s)#\{?if\}?\(.+?\).*?#\{?end\}?`)
+	velocityAnalzserForeachRe   = compileLazy(`(?s)#\{?foreach\}?\(.+?\).*?#\{?end\}?`)
+	velocityAnalzserReferenceRe = compileLazy(`\$!?\{?[a-zA-Z_]\w*(\([^)]*\))?(\.\w+(\([^)]*\))?)*\}?`)
 )
 
 // Velocity lexer.
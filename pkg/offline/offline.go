@@ -189,6 +189,8 @@ func Sync(ctx context.Context, filepath string, syncLimit int) func(next heartbe
 			if err != nil {
 				return fmt.Errorf("failed to handle heartbeats api results: %s", err)
 			}
+
+			heartbeat.CountSynced(ctx, countSuccessful(results, len(hh)))
 		}
 
 		return nil
@@ -255,6 +257,24 @@ func handleResults(ctx context.Context, filepath string, results []heartbeat.Res
 	return err
 }
 
+// countSuccessful returns the number of results with successful status
+// out of the first n results.
+func countSuccessful(results []heartbeat.Result, n int) int {
+	var successful int
+
+	for i, result := range results {
+		if i >= n {
+			break
+		}
+
+		if result.Status >= http.StatusOK && result.Status <= 299 {
+			successful++
+		}
+	}
+
+	return successful
+}
+
 func popHeartbeats(ctx context.Context, filepath string, limit int) ([]heartbeat.Heartbeat, error) {
 	db, close, err := openDB(ctx, filepath)
 	if err != nil {
@@ -499,9 +519,7 @@ func (q *Queue) PopMany(limit int) ([]heartbeat.Heartbeat, error) {
 			break
 		}
 
-		var h heartbeat.Heartbeat
-
-		err := json.Unmarshal(value, &h)
+		h, err := unmarshalQueued(value)
 		if err != nil {
 			return nil, fmt.Errorf("failed to json unmarshal heartbeat data: %s", err)
 		}
@@ -527,7 +545,7 @@ func (q *Queue) PushMany(hh []heartbeat.Heartbeat) error {
 	}
 
 	for _, h := range hh {
-		data, err := json.Marshal(h)
+		data, err := marshalQueued(h)
 		if err != nil {
 			return fmt.Errorf("failed to json marshal heartbeat: %s", err)
 		}
@@ -558,9 +576,7 @@ func (q *Queue) ReadMany(limit int) ([]heartbeat.Heartbeat, error) {
 			break
 		}
 
-		var h heartbeat.Heartbeat
-
-		err := json.Unmarshal(value, &h)
+		h, err := unmarshalQueued(value)
 		if err != nil {
 			return nil, fmt.Errorf("failed to json unmarshal heartbeat data: %s", err)
 		}
@@ -570,3 +586,42 @@ func (q *Queue) ReadMany(limit int) ([]heartbeat.Heartbeat, error) {
 
 	return heartbeats, nil
 }
+
+// queuedHeartbeat is a heartbeat as stored in the offline queue. The api key
+// declared in a .wakatime-project file is not part of the heartbeat sent to the
+// api. Instead of storing the key in plain text, the path of the project file is
+// stored alongside, to read the key again when syncing.
+type queuedHeartbeat struct {
+	heartbeat.Heartbeat
+	ProjectFile string `json:"project_file,omitempty"`
+}
+
+// marshalQueued json encodes a heartbeat for storing in the offline queue.
+func marshalQueued(h heartbeat.Heartbeat) ([]byte, error) {
+	queued := queuedHeartbeat{Heartbeat: h}
+
+	if h.ProjectSettings != nil && h.ProjectSettings.APIKey != "" {
+		queued.ProjectFile = h.ProjectSettings.File
+	}
+
+	return json.Marshal(queued)
+}
+
+// unmarshalQueued json decodes a heartbeat stored in the offline queue.
+func unmarshalQueued(data []byte) (heartbeat.Heartbeat, error) {
+	var queued queuedHeartbeat
+
+	if err := json.Unmarshal(data, &queued); err != nil {
+		return heartbeat.Heartbeat{}, err
+	}
+
+	h := queued.Heartbeat
+
+	if queued.ProjectFile != "" {
+		h.ProjectSettings = &heartbeat.ProjectSettings{
+			File: queued.ProjectFile,
+		}
+	}
+
+	return h, nil
+}
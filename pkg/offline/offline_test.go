@@ -1252,6 +1252,55 @@ func TestQueue_PushMany(t *testing.T) {
 	assert.JSONEq(t, string(dataJs), stored[2].Heartbeat)
 }
 
+func TestQueue_PushMany_ProjectAPIKey(t *testing.T) {
+	// setup
+	db, cleanup := initDB(t)
+	defer cleanup()
+
+	var h heartbeat.Heartbeat
+
+	dataGo, err := os.ReadFile("testdata/heartbeat_go.json")
+	require.NoError(t, err)
+
+	err = json.Unmarshal(dataGo, &h)
+	require.NoError(t, err)
+
+	h.APIKey = "00000000-0000-4000-8000-000000000000"
+	h.ProjectSettings = &heartbeat.ProjectSettings{
+		APIKey: "00000000-0000-4000-8000-000000000001",
+		File:   "/tmp/.wakatime-project",
+	}
+
+	tx, err := db.Begin(true)
+	require.NoError(t, err)
+
+	// run
+	q := offline.NewQueue(tx)
+	q.Bucket = "test_bucket"
+	err = q.PushMany([]heartbeat.Heartbeat{h})
+	require.NoError(t, err)
+
+	popped, err := q.PopMany(1)
+	require.NoError(t, err)
+
+	err = tx.Commit()
+	require.NoError(t, err)
+
+	// check
+	require.Len(t, popped, 1)
+	assert.Empty(t, popped[0].APIKey)
+	// only the path of the project file is stored, not the api key
+	assert.Equal(t, &heartbeat.ProjectSettings{
+		File: "/tmp/.wakatime-project",
+	}, popped[0].ProjectSettings)
+
+	popped[0].ProjectSettings = nil
+	h.APIKey = ""
+	h.ProjectSettings = nil
+
+	assert.Equal(t, h, popped[0])
+}
+
 func TestQueue_ReadMany(t *testing.T) {
 	// setup
 	f, err := os.CreateTemp(t.TempDir(), "")
@@ -0,0 +1,240 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+const (
+	// DefaultTimeoutSecs is the default number of seconds a plugin is allowed to run.
+	DefaultTimeoutSecs = 2
+	// waitDelay is the time to wait for output pipes to close after a plugin was killed.
+	waitDelay = 100 * time.Millisecond
+)
+
+// Plugin is an external executable, which receives heartbeats as a JSON array
+// on stdin and writes the modified heartbeats as a JSON array to stdout.
+type Plugin struct {
+	// Name is the key of the plugin in the [plugins] config section.
+	Name string
+	// Command contains the executable followed by its arguments.
+	Command []string
+}
+
+// Config contains plugin execution configurations.
+type Config struct {
+	// Plugins are executed in order, each one receiving the output of the previous one.
+	Plugins []Plugin
+	// Timeout is the maximum duration a single plugin is allowed to run.
+	Timeout time.Duration
+}
+
+// Payload is the representation of a heartbeat exchanged with plugins.
+// Only project, branch, category, language and dependencies are taken over
+// from the plugin output. Any other field is ignored.
+type Payload struct {
+	Branch       *string  `json:"branch"`
+	Category     string   `json:"category"`
+	Dependencies []string `json:"dependencies"`
+	Entity       string   `json:"entity"`
+	EntityType   string   `json:"type"`
+	IsWrite      *bool    `json:"is_write,omitempty"`
+	Language     *string  `json:"language"`
+	Project      *string  `json:"project"`
+	ProjectPath  string   `json:"project_path,omitempty"`
+	Time         float64  `json:"time"`
+}
+
+// WithEnrichment initializes and returns a heartbeat handle option, which
+// can be used in a heartbeat processing pipeline to let external executables
+// modify a whitelist of heartbeat fields.
+func WithEnrichment(config Config) heartbeat.HandleOption {
+	return func(next heartbeat.Handle) heartbeat.Handle {
+		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			if len(config.Plugins) == 0 || len(hh) == 0 {
+				return next(ctx, hh)
+			}
+
+			logger := log.Extract(ctx)
+			logger.Debugln("execute heartbeat plugins")
+
+			timeout := config.Timeout
+			if timeout <= 0 {
+				timeout = DefaultTimeoutSecs * time.Second
+			}
+
+			for _, p := range config.Plugins {
+				enriched, err := Run(ctx, p, timeout, hh)
+				if err != nil {
+					logger.Warnf("failed to run plugin %q: %s", p.Name, err)
+					continue
+				}
+
+				for i := range enriched {
+					recordChanges(ctx, p, hh[i], &enriched[i])
+				}
+
+				hh = enriched
+			}
+
+			return next(ctx, hh)
+		}
+	}
+}
+
+// Run executes a single plugin with the passed in heartbeats and returns the
+// heartbeats with the whitelisted fields applied from the plugin output.
+// The passed in heartbeats are not modified.
+func Run(ctx context.Context, p Plugin, timeout time.Duration, hh []heartbeat.Heartbeat) ([]heartbeat.Heartbeat, error) {
+	if len(p.Command) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	payloads := make([]Payload, len(hh))
+	for i, h := range hh {
+		payloads[i] = toPayload(h)
+	}
+
+	input, err := json.Marshal(payloads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to json encode heartbeats: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.Command[0], p.Command[1:]...) // nolint:gosec
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// don't wait for child processes still holding stdout after the plugin was killed
+	cmd.WaitDelay = waitDelay
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+
+		return nil, fmt.Errorf("%s: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	var output []Payload
+
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("failed to json decode output %q: %s", stdout.String(), err)
+	}
+
+	if len(output) != len(hh) {
+		return nil, fmt.Errorf("expected %d heartbeats in output but got %d", len(hh), len(output))
+	}
+
+	enriched := make([]heartbeat.Heartbeat, len(hh))
+
+	for i, h := range hh {
+		enriched[i], err = apply(h, output[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid heartbeat at index %d: %s", i, err)
+		}
+	}
+
+	return enriched, nil
+}
+
+// toPayload converts a heartbeat into its plugin representation.
+func toPayload(h heartbeat.Heartbeat) Payload {
+	return Payload{
+		Branch:       h.Branch,
+		Category:     h.Category.String(),
+		Dependencies: h.Dependencies,
+		Entity:       h.Entity,
+		EntityType:   h.EntityType.String(),
+		IsWrite:      h.IsWrite,
+		Language:     h.Language,
+		Project:      h.Project,
+		ProjectPath:  h.ProjectPath,
+		Time:         h.Time,
+	}
+}
+
+// apply takes over the whitelisted fields from plugin output to the heartbeat.
+func apply(h heartbeat.Heartbeat, p Payload) (heartbeat.Heartbeat, error) {
+	if p.Category != "" {
+		category, err := heartbeat.ParseCategory(p.Category)
+		if err != nil {
+			return heartbeat.Heartbeat{}, err
+		}
+
+		h.Category = category
+	}
+
+	if p.Branch != nil {
+		h.Branch = heartbeat.PointerTo(*p.Branch)
+	}
+
+	if p.Language != nil {
+		h.Language = heartbeat.PointerTo(*p.Language)
+	}
+
+	if p.Project != nil {
+		h.Project = heartbeat.PointerTo(*p.Project)
+	}
+
+	if p.Dependencies != nil {
+		h.Dependencies = p.Dependencies
+	}
+
+	return h, nil
+}
+
+// recordChanges records a decision for each whitelisted field changed by a plugin.
+func recordChanges(ctx context.Context, p Plugin, before heartbeat.Heartbeat, after *heartbeat.Heartbeat) {
+	record := func(field, value string) {
+		heartbeat.RecordDecision(ctx, after, heartbeat.Decision{
+			Stage:     "plugin",
+			Rule:      p.Name,
+			ConfigKey: "plugins." + p.Name,
+			Field:     field,
+			Value:     value,
+			Message:   fmt.Sprintf("%s set by plugin %q", field, p.Name),
+		})
+	}
+
+	if before.Category != after.Category {
+		record("category", after.Category.String())
+	}
+
+	if stringValue(before.Branch) != stringValue(after.Branch) {
+		record("branch", stringValue(after.Branch))
+	}
+
+	if stringValue(before.Language) != stringValue(after.Language) {
+		record("language", stringValue(after.Language))
+	}
+
+	if stringValue(before.Project) != stringValue(after.Project) {
+		record("project", stringValue(after.Project))
+	}
+
+	if strings.Join(before.Dependencies, ",") != strings.Join(after.Dependencies, ",") {
+		record("dependencies", strings.Join(after.Dependencies, ","))
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
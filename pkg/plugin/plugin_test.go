@@ -0,0 +1,159 @@
+package plugin_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/plugin"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnrichment(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping because OS is windows.")
+	}
+
+	opt := plugin.WithEnrichment(plugin.Config{
+		Plugins: []plugin.Plugin{
+			{Name: "ticket", Command: []string{"testdata/ticket.sh"}},
+		},
+		Timeout: time.Second,
+	})
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		assert.Equal(t, []heartbeat.Heartbeat{
+			{
+				Branch:       heartbeat.PointerTo("JIRA-123"),
+				Category:     heartbeat.DebuggingCategory,
+				Dependencies: []string{"owner:payments"},
+				Entity:       "/tmp/main.go",
+				EntityType:   heartbeat.FileType,
+				Language:     heartbeat.PointerTo("Go"),
+				Project:      heartbeat.PointerTo("billing"),
+				Time:         1585598060,
+			},
+		}, hh)
+
+		return []heartbeat.Result{
+			{
+				Status: 201,
+			},
+		}, nil
+	})
+
+	result, err := h(context.Background(), []heartbeat.Heartbeat{
+		{
+			Branch:     heartbeat.PointerTo("master"),
+			Category:   heartbeat.CodingCategory,
+			Entity:     "/tmp/main.go",
+			EntityType: heartbeat.FileType,
+			Language:   heartbeat.PointerTo("Go"),
+			Project:    heartbeat.PointerTo("wakatime-cli"),
+			Time:       1585598060,
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []heartbeat.Result{
+		{
+			Status: 201,
+		},
+	}, result)
+}
+
+func TestWithEnrichment_FailingPluginIsSkipped(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping because OS is windows.")
+	}
+
+	opt := plugin.WithEnrichment(plugin.Config{
+		Plugins: []plugin.Plugin{
+			{Name: "invalid", Command: []string{"testdata/invalid_category.sh"}},
+			{Name: "missing", Command: []string{"testdata/nonexisting.sh"}},
+		},
+		Timeout: time.Second,
+	})
+
+	input := []heartbeat.Heartbeat{
+		{
+			Category:   heartbeat.CodingCategory,
+			Entity:     "/tmp/main.go",
+			EntityType: heartbeat.FileType,
+			Project:    heartbeat.PointerTo("wakatime-cli"),
+		},
+	}
+
+	h := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		assert.Equal(t, input, hh)
+
+		return []heartbeat.Result{}, nil
+	})
+
+	_, err := h(context.Background(), input)
+	require.NoError(t, err)
+}
+
+func TestRun_Unchanged(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping because OS is windows.")
+	}
+
+	input := []heartbeat.Heartbeat{
+		{
+			Branch:       heartbeat.PointerTo("master"),
+			Category:     heartbeat.CodingCategory,
+			Dependencies: []string{"os"},
+			Entity:       "/tmp/main.go",
+			EntityType:   heartbeat.FileType,
+			Language:     heartbeat.PointerTo("Go"),
+			Project:      heartbeat.PointerTo("wakatime-cli"),
+			ProjectPath:  "/tmp",
+			Time:         1585598060,
+		},
+	}
+
+	result, err := plugin.Run(
+		context.Background(),
+		plugin.Plugin{Name: "echo", Command: []string{"testdata/echo.sh"}},
+		time.Second,
+		input,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, input, result)
+}
+
+func TestRun_Timeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping because OS is windows.")
+	}
+
+	_, err := plugin.Run(
+		context.Background(),
+		plugin.Plugin{Name: "sleep", Command: []string{"testdata/sleep.sh"}},
+		100*time.Millisecond,
+		[]heartbeat.Heartbeat{{Entity: "/tmp/main.go"}},
+	)
+
+	assert.EqualError(t, err, "timed out after 100ms")
+}
+
+func TestRun_LengthMismatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping because OS is windows.")
+	}
+
+	_, err := plugin.Run(
+		context.Background(),
+		plugin.Plugin{Name: "ticket", Command: []string{"testdata/ticket.sh"}},
+		time.Second,
+		[]heartbeat.Heartbeat{{Entity: "/tmp/main.go"}, {Entity: "/tmp/main.py"}},
+	)
+
+	assert.EqualError(t, err, "expected 2 heartbeats in output but got 1")
+}
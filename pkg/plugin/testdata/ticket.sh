@@ -0,0 +1,3 @@
+#!/bin/sh
+cat > /dev/null
+echo '[{"entity":"/tmp/other.go","project":"billing","branch":"JIRA-123","category":"debugging","dependencies":["owner:payments"],"time":1}]'
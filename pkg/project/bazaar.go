@@ -0,0 +1,104 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+var bazaarNicknameRegex = regexp.MustCompile(`^\s*nickname\s*=\s*(.+?)\s*$`)
+
+// Bazaar contains bazaar data.
+type Bazaar struct {
+	// Filepath contains the entity path.
+	Filepath string
+}
+
+// Detect gets information about the bazaar project for a given file.
+func (b Bazaar) Detect(ctx context.Context) (Result, bool, error) {
+	var fp string
+
+	// Take only the directory
+	if fileOrDirExists(b.Filepath) {
+		fp = filepath.Dir(b.Filepath)
+	}
+
+	// Find for .bzr folder
+	bzrDirectory, found := FindFileOrDirectory(ctx, fp, ".bzr")
+	if !found {
+		return Result{}, false, nil
+	}
+
+	logger := log.Extract(ctx)
+	folder := filepath.Dir(bzrDirectory)
+
+	branch, err := findBazaarBranch(ctx, bzrDirectory)
+	if err != nil {
+		logger.Errorf(
+			"error finding for branch name from %q: %s",
+			bzrDirectory,
+			err,
+		)
+	}
+
+	if branch == "" {
+		// bazaar defaults the branch nickname to the branch folder name
+		branch = filepath.Base(folder)
+	}
+
+	return Result{
+		Project: filepath.Base(folder),
+		Branch:  branch,
+		Folder:  folder,
+	}, true, nil
+}
+
+// findBazaarBranch returns the branch nickname. For lightweight checkouts
+// the name of the referenced branch location is used.
+func findBazaarBranch(ctx context.Context, fp string) (string, error) {
+	conf := filepath.Join(fp, "branch", "branch.conf")
+	if fileOrDirExists(conf) {
+		lines, err := ReadFile(ctx, conf, maxMetadataLines)
+		if err != nil {
+			return "", fmt.Errorf("failed while opening file %q: %s", conf, err)
+		}
+
+		for _, line := range lines {
+			if match := bazaarNicknameRegex.FindStringSubmatch(line); len(match) == 2 {
+				return match[1], nil
+			}
+		}
+	}
+
+	location := filepath.Join(fp, "branch", "location")
+	if !fileOrDirExists(location) {
+		return "", nil
+	}
+
+	lines, err := ReadFile(ctx, location, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed while opening file %q: %s", location, err)
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse branch location %q: %s", lines[0], err)
+	}
+
+	return path.Base(strings.TrimSuffix(parsed.Path, "/")), nil
+}
+
+// ID returns its id.
+func (Bazaar) ID() DetectorID {
+	return BazaarDetector
+}
@@ -0,0 +1,99 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBazaar_Detect(t *testing.T) {
+	fp := setupTestBazaar(t)
+
+	copyFile(t, "testdata/bzr/branch.conf", filepath.Join(fp, "wakatime-cli/.bzr/branch/branch.conf"))
+
+	b := project.Bazaar{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := b.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "billing",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestBazaar_Detect_LightweightCheckout(t *testing.T) {
+	fp := setupTestBazaar(t)
+
+	err := os.WriteFile(
+		filepath.Join(fp, "wakatime-cli/.bzr/branch/location"),
+		[]byte("file:///srv/bzr/wakatime-cli/feature-billing/\n"),
+		0600,
+	)
+	require.NoError(t, err)
+
+	b := project.Bazaar{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := b.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "feature-billing",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestBazaar_Detect_NoNickname(t *testing.T) {
+	fp := setupTestBazaar(t)
+
+	b := project.Bazaar{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := b.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "wakatime-cli",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestBazaar_ID(t *testing.T) {
+	b := project.Bazaar{}
+
+	assert.Equal(t, project.BazaarDetector, b.ID())
+}
+
+func setupTestBazaar(t *testing.T) (fp string) {
+	tmpDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/src/pkg"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	tmpFile, err := os.Create(filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"))
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	err = os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/.bzr/branch"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	return tmpDir
+}
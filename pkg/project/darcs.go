@@ -0,0 +1,41 @@
+package project
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// Darcs contains darcs data.
+type Darcs struct {
+	// Filepath contains the entity path.
+	Filepath string
+}
+
+// Detect gets information about the darcs project for a given file.
+// Darcs has no branches inside a repository, so no branch is returned.
+func (d Darcs) Detect(ctx context.Context) (Result, bool, error) {
+	var fp string
+
+	// Take only the directory
+	if fileOrDirExists(d.Filepath) {
+		fp = filepath.Dir(d.Filepath)
+	}
+
+	// Find for _darcs folder
+	darcsDirectory, found := FindFileOrDirectory(ctx, fp, "_darcs")
+	if !found {
+		return Result{}, false, nil
+	}
+
+	folder := filepath.Dir(darcsDirectory)
+
+	return Result{
+		Project: filepath.Base(folder),
+		Folder:  folder,
+	}, true, nil
+}
+
+// ID returns its id.
+func (Darcs) ID() DetectorID {
+	return DarcsDetector
+}
@@ -0,0 +1,47 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDarcs_Detect(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/src/pkg"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	tmpFile, err := os.Create(filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"))
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	err = os.Mkdir(filepath.Join(tmpDir, "wakatime-cli/_darcs"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	d := project.Darcs{
+		Filepath: filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := d.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Folder:  filepath.Join(tmpDir, "wakatime-cli"),
+	}, result)
+}
+
+func TestDarcs_ID(t *testing.T) {
+	d := project.Darcs{}
+
+	assert.Equal(t, project.DarcsDetector, d.ID())
+}
@@ -0,0 +1,159 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// localWorkspaceFolderEnv is the environment variable commonly set inside a
+// devcontainer via remoteEnv to the host folder of the workspace.
+const localWorkspaceFolderEnv = "LOCAL_WORKSPACE_FOLDER"
+
+// DevContainer contains devcontainer data.
+type DevContainer struct {
+	Filepath string
+}
+
+// Detect finds a .devcontainer/devcontainer.json or .devcontainer.json file and
+// uses it to name the project the same on the host and inside the container.
+// The project name is read from the wakatime customizations of the devcontainer:
+//
+//	{
+//	  "name": "Go",
+//	  "customizations": {
+//	    "wakatime": {
+//	      "project": "wakatime-cli"
+//	    }
+//	  }
+//	}
+//
+// Inside the container, the folder name of the LOCAL_WORKSPACE_FOLDER environment
+// variable is used otherwise, which devcontainers set via:
+//
+//	"remoteEnv": { "LOCAL_WORKSPACE_FOLDER": "${localWorkspaceFolder}" }
+func (d DevContainer) Detect(ctx context.Context) (Result, bool, error) {
+	fp, folder, found := findDevContainerFile(ctx, d.Filepath)
+	if !found {
+		return Result{}, false, nil
+	}
+
+	logger := log.Extract(ctx)
+	logger.Debugf("devcontainer file found at: %s", fp)
+
+	data, err := os.ReadFile(fp) // nolint:gosec
+	if err != nil {
+		return Result{}, false, fmt.Errorf("failed to read file %q: %s", fp, err)
+	}
+
+	var config struct {
+		Customizations struct {
+			WakaTime struct {
+				Project string `json:"project"`
+			} `json:"wakatime"`
+		} `json:"customizations"`
+	}
+
+	if err := json.Unmarshal(stripJSONComments(data), &config); err != nil {
+		return Result{}, false, fmt.Errorf("failed to parse devcontainer file %q: %s", fp, err)
+	}
+
+	project := strings.TrimSpace(config.Customizations.WakaTime.Project)
+
+	if project == "" {
+		if local := strings.TrimRight(os.Getenv(localWorkspaceFolderEnv), `/\`); local != "" {
+			project = local[strings.LastIndexAny(local, `/\`)+1:]
+		}
+	}
+
+	if project == "" {
+		return Result{}, false, nil
+	}
+
+	return Result{
+		Project: project,
+		Folder:  folder,
+	}, true, nil
+}
+
+// findDevContainerFile returns the devcontainer file and the workspace folder containing it.
+func findDevContainerFile(ctx context.Context, fp string) (string, string, bool) {
+	if found, ok := FindFileOrDirectory(ctx, fp, filepath.Join(".devcontainer", "devcontainer.json")); ok {
+		return found, filepath.Dir(filepath.Dir(found)), true
+	}
+
+	if found, ok := FindFileOrDirectory(ctx, fp, ".devcontainer.json"); ok {
+		return found, filepath.Dir(found), true
+	}
+
+	return "", "", false
+}
+
+// stripJSONComments removes comments and trailing commas from json with comments,
+// which is the format of devcontainer files.
+func stripJSONComments(data []byte) []byte {
+	var (
+		out      = make([]byte, 0, len(data))
+		inString bool
+	)
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		switch {
+		case inString:
+			out = append(out, c)
+
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && (data[i] != '*' || data[i+1] != '/') {
+				i++
+			}
+
+			i++
+		case c == '}' || c == ']':
+			// remove trailing comma
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// ID returns its id.
+func (DevContainer) ID() DetectorID {
+	return DevContainerDetector
+}
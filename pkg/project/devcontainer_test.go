@@ -0,0 +1,115 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/gandarez/go-realpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevContainer_Detect(t *testing.T) {
+	tests := map[string]struct {
+		Filename             string
+		Content              string
+		LocalWorkspaceFolder string
+		Expected             string
+	}{
+		"customizations": {
+			Filename: ".devcontainer/devcontainer.json",
+			Content: `{
+	// the name is shown in the editor
+	"name": "Go",
+	"image": "mcr.microsoft.com/devcontainers/go:1", /* pinned */
+	"customizations": {
+		"wakatime": {
+			"project": "wakatime-cli",
+		},
+	},
+}`,
+			Expected: "wakatime-cli",
+		},
+		"root devcontainer file": {
+			Filename: ".devcontainer.json",
+			Content:  `{"customizations": {"wakatime": {"project": "billing"}}}`,
+			Expected: "billing",
+		},
+		"local workspace folder": {
+			Filename:             ".devcontainer/devcontainer.json",
+			Content:              `{"name": "Go", "remoteEnv": {"LOCAL_WORKSPACE_FOLDER": "${localWorkspaceFolder}"}}`,
+			LocalWorkspaceFolder: `C:\Users\user\projects\wakatime-cli\`,
+			Expected:             "wakatime-cli",
+		},
+		"url in string": {
+			Filename: ".devcontainer/devcontainer.json",
+			Content:  `{"image": "ghcr.io/org/image", "customizations": {"wakatime": {"project": "http://example.com/*"}}}`,
+			Expected: "http://example.com/*",
+		},
+		"no project name": {
+			Filename: ".devcontainer/devcontainer.json",
+			Content:  `{"name": "Go"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("LOCAL_WORKSPACE_FOLDER", test.LocalWorkspaceFolder)
+
+			tmpDir, err := realpath.Realpath(t.TempDir())
+			require.NoError(t, err)
+
+			folder := filepath.Join(tmpDir, "workspace")
+
+			err = os.MkdirAll(filepath.Join(folder, "src"), os.FileMode(int(0700)))
+			require.NoError(t, err)
+
+			err = os.MkdirAll(filepath.Dir(filepath.Join(folder, test.Filename)), os.FileMode(int(0700)))
+			require.NoError(t, err)
+
+			err = os.WriteFile(filepath.Join(folder, test.Filename), []byte(test.Content), 0600)
+			require.NoError(t, err)
+
+			d := project.DevContainer{
+				Filepath: filepath.Join(folder, "src"),
+			}
+
+			result, detected, err := d.Detect(context.Background())
+			require.NoError(t, err)
+
+			if test.Expected == "" {
+				assert.False(t, detected)
+				return
+			}
+
+			assert.True(t, detected)
+			assert.Equal(t, project.Result{
+				Project: test.Expected,
+				Folder:  folder,
+			}, result)
+		})
+	}
+}
+
+func TestDevContainer_Detect_NotFound(t *testing.T) {
+	t.Setenv("LOCAL_WORKSPACE_FOLDER", "/home/user/wakatime-cli")
+
+	d := project.DevContainer{
+		Filepath: t.TempDir(),
+	}
+
+	_, detected, err := d.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, detected)
+}
+
+func TestDevContainer_ID(t *testing.T) {
+	d := project.DevContainer{}
+
+	assert.Equal(t, project.DevContainerDetector, d.ID())
+}
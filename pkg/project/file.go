@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/log"
 )
@@ -19,7 +18,8 @@ type File struct {
 
 // Detect get information from a .wakatime-project file about the project for
 // a given file. First line of .wakatime-project sets the project
-// name. Second line sets the current branch name.
+// name. Second line sets the current branch name. The project and branch
+// keys of the extended format's [settings] section take precedence.
 func (f File) Detect(ctx context.Context) (Result, bool, error) {
 	fp, found := FindFileOrDirectory(ctx, f.Filepath, WakaTimeProjectFile)
 	if !found {
@@ -29,7 +29,7 @@ func (f File) Detect(ctx context.Context) (Result, bool, error) {
 	logger := log.Extract(ctx)
 	logger.Debugf("wakatime project file found at: %s", fp)
 
-	lines, err := ReadFile(ctx, fp, 2)
+	pf, err := readProjectFile(ctx, fp)
 	if err != nil {
 		return Result{}, false, fmt.Errorf("error reading file: %s", err)
 	}
@@ -37,14 +37,11 @@ func (f File) Detect(ctx context.Context) (Result, bool, error) {
 	result := Result{
 		Folder:  filepath.Dir(fp),
 		Project: filepath.Base(filepath.Dir(fp)),
+		Branch:  pf.Branch,
 	}
 
-	if len(lines) > 0 {
-		result.Project = strings.TrimSpace(lines[0])
-	}
-
-	if len(lines) > 1 {
-		result.Branch = strings.TrimSpace(lines[1])
+	if pf.HasProject {
+		result.Project = pf.Project
 	}
 
 	return result, true, nil
@@ -72,6 +72,86 @@ func TestFile_Detect_ParentFolderExists(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestFile_Detect_ExtendedFormat(t *testing.T) {
+	tests := map[string]struct {
+		Content  string
+		Expected project.Result
+	}{
+		"legacy lines with settings": {
+			Content: "wakatime-cli\nmaster\n[settings]\ncategory = debugging\n",
+			Expected: project.Result{
+				Branch:  "master",
+				Project: "wakatime-cli",
+			},
+		},
+		"settings override lines": {
+			Content: "wakatime-cli\n[settings]\nbranch = feature/settings\n",
+			Expected: project.Result{
+				Branch:  "feature/settings",
+				Project: "wakatime-cli",
+			},
+		},
+		"settings only": {
+			Content: "[settings]\nproject = billing\nbranch = main\n",
+			Expected: project.Result{
+				Branch:  "main",
+				Project: "billing",
+			},
+		},
+		"settings without project": {
+			Content: "[Settings]\ncategory = debugging\n",
+			Expected: project.Result{
+				Project: "folder",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir, err := realpath.Realpath(t.TempDir())
+			require.NoError(t, err)
+
+			dir := filepath.Join(tmpDir, "folder")
+
+			err = os.Mkdir(dir, os.FileMode(int(0700)))
+			require.NoError(t, err)
+
+			err = os.WriteFile(filepath.Join(dir, ".wakatime-project"), []byte(test.Content), 0600)
+			require.NoError(t, err)
+
+			f := project.File{
+				Filepath: filepath.Join(dir, ".wakatime-project"),
+			}
+
+			result, detected, err := f.Detect(context.Background())
+			require.NoError(t, err)
+
+			test.Expected.Folder = dir
+
+			assert.True(t, detected)
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestFile_Detect_ExtendedFormat_InvalidCategory(t *testing.T) {
+	tmpDir, err := realpath.Realpath(t.TempDir())
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(tmpDir, ".wakatime-project"), []byte("[settings]\ncategory = invalid\n"), 0600)
+	require.NoError(t, err)
+
+	f := project.File{
+		Filepath: filepath.Join(tmpDir, ".wakatime-project"),
+	}
+
+	result, detected, err := f.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, filepath.Base(tmpDir), result.Project)
+}
+
 func TestFile_Detect_NoFileFound(t *testing.T) {
 	tmpDir := t.TempDir()
 
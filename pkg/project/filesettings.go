@@ -0,0 +1,266 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
+
+	"gopkg.in/ini.v1"
+)
+
+const (
+	// maxProjectFileLines is the maximum number of lines read from a .wakatime-project file.
+	maxProjectFileLines = 1000
+	// projectFileSettingsSection starts the key/value settings of the extended
+	// .wakatime-project format.
+	projectFileSettingsSection = "[settings]"
+)
+
+var matchAllRegex = regexp.MustCompile(".*")
+
+// projectFile contains the contents of a .wakatime-project file.
+type projectFile struct {
+	Project    string
+	HasProject bool
+	Branch     string
+	Category   *heartbeat.Category
+	Settings   *heartbeat.ProjectSettings
+}
+
+// readProjectFile reads a .wakatime-project file. The legacy format contains the
+// project name on the first line and the branch name on the second line. The
+// extended format continues with a [settings] section of key/value pairs:
+//
+//	my-project
+//	[settings]
+//	branch = main
+//	category = debugging
+//	exclude =
+//	  ^/tmp/
+//	  \.min\.js$
+//	include = \.go$
+//	hide_file_names = true
+//	api_key = waka_00000000-0000-4000-8000-000000000000
+//
+// Patterns are regular expressions matched against the entity's absolute path,
+// the same as the patterns of the ~/.wakatime.cfg file.
+func readProjectFile(ctx context.Context, fp string) (projectFile, error) {
+	lines, err := ReadFile(ctx, fp, maxProjectFileLines)
+	if err != nil {
+		return projectFile{}, err
+	}
+
+	var pf projectFile
+
+	for i, line := range lines {
+		if strings.EqualFold(strings.TrimSpace(line), projectFileSettingsSection) {
+			if err := pf.parseSettings(ctx, strings.Join(lines[i:], "\n")); err != nil {
+				return projectFile{}, fmt.Errorf("failed to parse settings of %q: %s", fp, err)
+			}
+
+			break
+		}
+
+		switch i {
+		case 0:
+			pf.Project = strings.TrimSpace(line)
+			pf.HasProject = true
+		case 1:
+			pf.Branch = strings.TrimSpace(line)
+		}
+	}
+
+	if pf.Settings != nil {
+		pf.Settings.File = fp
+	}
+
+	return pf, nil
+}
+
+// parseSettings parses the [settings] section of the extended format.
+func (pf *projectFile) parseSettings(ctx context.Context, data string) error {
+	cfg, err := ini.LoadSources(ini.LoadOptions{
+		AllowPythonMultilineValues: true,
+		SkipUnrecognizableLines:    true,
+	}, []byte(data))
+	if err != nil {
+		return err
+	}
+
+	section := cfg.Section("settings")
+
+	if value := strings.TrimSpace(section.Key("project").String()); value != "" {
+		pf.Project = value
+		pf.HasProject = true
+	}
+
+	if value := strings.TrimSpace(section.Key("branch").String()); value != "" {
+		pf.Branch = value
+	}
+
+	if value := strings.TrimSpace(section.Key("category").String()); value != "" {
+		category, err := heartbeat.ParseCategory(value)
+		if err != nil {
+			log.Extract(ctx).Warnf("invalid category in project file, it will be ignored: %s", err)
+		} else {
+			pf.Category = &category
+		}
+	}
+
+	settings := heartbeat.ProjectSettings{
+		APIKey:        strings.TrimSpace(section.Key("api_key").String()),
+		Exclude:       parseProjectFilePatterns(ctx, section.Key("exclude").String()),
+		HideFileNames: parseProjectFilePatterns(ctx, section.Key("hide_file_names").String()),
+		Include:       parseProjectFilePatterns(ctx, section.Key("include").String()),
+	}
+
+	if settings.APIKey != "" || len(settings.Exclude) > 0 || len(settings.HideFileNames) > 0 || len(settings.Include) > 0 {
+		pf.Settings = &settings
+	}
+
+	return nil
+}
+
+// WithFileAPIKey initializes and returns a heartbeat handle option, which can
+// be used in a heartbeat processing pipeline to read the api key of the
+// .wakatime-project file again for heartbeats synced from the offline queue.
+// The queue stores the path of the project file instead of its api key, so the
+// key isn't kept in plain text and a changed or removed key is respected.
+func WithFileAPIKey() heartbeat.HandleOption {
+	return func(next heartbeat.Handle) heartbeat.Handle {
+		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			logger := log.Extract(ctx)
+			logger.Debugln("execute project file api key reading")
+
+			// heartbeats are often in the same folder, read each project file once
+			files := make(map[string]projectFile)
+
+			for n, h := range hh {
+				if h.ProjectSettings == nil || h.ProjectSettings.File == "" || h.ProjectSettings.APIKey != "" {
+					continue
+				}
+
+				fp := h.ProjectSettings.File
+
+				pf, ok := files[fp]
+				if !ok {
+					var err error
+
+					pf, err = readProjectFile(ctx, fp)
+					if err != nil {
+						logger.Warnf("failed to read project file: %s", err)
+					}
+
+					files[fp] = pf
+				}
+
+				if pf.Settings != nil {
+					hh[n].ProjectSettings.APIKey = pf.Settings.APIKey
+				}
+			}
+
+			return next(ctx, hh)
+		}
+	}
+}
+
+// parseProjectFilePatterns parses a boolean or a newline separated list of
+// case insensitive regular expressions.
+func parseProjectFilePatterns(ctx context.Context, s string) []regex.Regex {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\r", "\n"))
+
+	switch strings.ToLower(s) {
+	case "", "false":
+		return nil
+	case "true":
+		return []regex.Regex{regex.NewRegexpWrap(matchAllRegex)}
+	}
+
+	logger := log.Extract(ctx)
+
+	var patterns []regex.Regex
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// make all regex case insensitive
+		if !strings.HasPrefix(line, "(?i)") {
+			line = "(?i)" + line
+		}
+
+		compiled, err := regex.Compile(line)
+		if err != nil {
+			logger.Warnf("failed to compile regex pattern %q, it will be ignored", line)
+			continue
+		}
+
+		patterns = append(patterns, compiled)
+	}
+
+	return patterns
+}
+
+// WithFileSettings initializes and returns a heartbeat handle option, which
+// can be used in a heartbeat processing pipeline to apply the settings of the
+// extended .wakatime-project format to file heartbeats under its folder.
+func WithFileSettings() heartbeat.HandleOption {
+	return func(next heartbeat.Handle) heartbeat.Handle {
+		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			logger := log.Extract(ctx)
+			logger.Debugln("execute project file settings")
+
+			// heartbeats are often in the same folder, read each project file once
+			files := make(map[string]projectFile)
+
+			for n, h := range hh {
+				if h.EntityType != heartbeat.FileType {
+					continue
+				}
+
+				fp, found := FindFileOrDirectory(ctx, h.Entity, WakaTimeProjectFile)
+				if !found {
+					continue
+				}
+
+				pf, ok := files[fp]
+				if !ok {
+					var err error
+
+					pf, err = readProjectFile(ctx, fp)
+					if err != nil {
+						logger.Warnf("failed to read project file: %s", err)
+					}
+
+					files[fp] = pf
+				}
+
+				hh[n].ProjectSettings = pf.Settings
+
+				// only a category not set by the plugin or a previous stage is overridden
+				if pf.Category != nil && !h.IsCategorySet {
+					hh[n].Category = *pf.Category
+					hh[n].IsCategorySet = true
+
+					heartbeat.RecordDecision(ctx, &hh[n], heartbeat.Decision{
+						Stage:     "project",
+						Rule:      "project file settings",
+						ConfigKey: WakaTimeProjectFile,
+						Field:     "category",
+						Value:     pf.Category.String(),
+						Message:   "category set by project file",
+					})
+				}
+			}
+
+			return next(ctx, hh)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/project"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
+
+	"github.com/gandarez/go-realpath"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFileSettings(t *testing.T) {
+	tmpDir, err := realpath.Realpath(t.TempDir())
+	require.NoError(t, err)
+
+	err = os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli", "src"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	copyFile(
+		t,
+		"testdata/wakatime-project-settings",
+		filepath.Join(tmpDir, "wakatime-cli", ".wakatime-project"),
+	)
+
+	entity := filepath.Join(tmpDir, "wakatime-cli", "src", "main.go")
+
+	err = os.WriteFile(entity, []byte("package main"), 0600)
+	require.NoError(t, err)
+
+	other := filepath.Join(tmpDir, "other.go")
+
+	err = os.WriteFile(other, []byte("package other"), 0600)
+	require.NoError(t, err)
+
+	handle := project.WithFileSettings()(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		require.Len(t, hh, 5)
+
+		// file under the project folder
+		assert.Equal(t, heartbeat.DebuggingCategory, hh[0].Category)
+		require.NotNil(t, hh[0].ProjectSettings)
+		assert.Equal(t, "00000000-0000-4000-8000-000000000000", hh[0].ProjectSettings.APIKey)
+		assert.Equal(t, []string{`(?i)/vendor/`, `(?i)\.min\.js$`}, patternStrings(hh[0].ProjectSettings.Exclude))
+		assert.Equal(t, []string{`(?i)/vendor/wakatime/`}, patternStrings(hh[0].ProjectSettings.Include))
+		assert.Equal(t, []string{".*"}, patternStrings(hh[0].ProjectSettings.HideFileNames))
+
+		// category set by the plugin is kept
+		assert.Equal(t, heartbeat.BuildingCategory, hh[1].Category)
+		assert.NotNil(t, hh[1].ProjectSettings)
+
+		// file outside of the project folder
+		assert.Equal(t, heartbeat.CodingCategory, hh[2].Category)
+		assert.Nil(t, hh[2].ProjectSettings)
+
+		// non file entity
+		assert.Equal(t, heartbeat.CodingCategory, hh[3].Category)
+		assert.Nil(t, hh[3].ProjectSettings)
+
+		// coding category set by the plugin is kept
+		assert.Equal(t, heartbeat.CodingCategory, hh[4].Category)
+
+		return nil, nil
+	})
+
+	_, err = handle(context.Background(), []heartbeat.Heartbeat{
+		{Entity: entity, EntityType: heartbeat.FileType},
+		{Entity: entity, EntityType: heartbeat.FileType, Category: heartbeat.BuildingCategory, IsCategorySet: true},
+		{Entity: other, EntityType: heartbeat.FileType},
+		{Entity: "wakatime.com", EntityType: heartbeat.DomainType},
+		{Entity: entity, EntityType: heartbeat.FileType, IsCategorySet: true},
+	})
+	require.NoError(t, err)
+}
+
+func patternStrings(patterns []regex.Regex) []string {
+	var s []string
+
+	for _, p := range patterns {
+		s = append(s, p.String())
+	}
+
+	return s
+}
@@ -30,6 +30,13 @@ func WithFiltering(config FilterConfig) heartbeat.HandleOption {
 				err := Filter(h, config)
 				if err != nil {
 					logger.Debugln(err.Error())
+					heartbeat.RecordDecision(ctx, &h, heartbeat.Decision{
+						Stage:     "project filter",
+						Rule:      "exclude unknown project",
+						ConfigKey: "settings.exclude_unknown_project",
+						Message:   "skipped because project is unknown",
+					})
+					heartbeat.RecordDropped(ctx, h, err.Error())
 
 					if h.LocalFileNeedsCleanup {
 						err = os.Remove(h.LocalFile)
@@ -0,0 +1,214 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// Fossil contains fossil data.
+type Fossil struct {
+	// Filepath contains the entity path.
+	Filepath string
+}
+
+// Detect gets information about the fossil project for a given file.
+// The branch is read from the repository database of the checkout and
+// otherwise from the manifest.tags file, which fossil writes when the
+// manifest setting includes tags.
+func (f Fossil) Detect(ctx context.Context) (Result, bool, error) {
+	var fp string
+
+	// Take only the directory
+	if fileOrDirExists(f.Filepath) {
+		fp = filepath.Dir(f.Filepath)
+	}
+
+	// Find for checkout database, which is named _FOSSIL_ on windows
+	checkoutFile, found := FindFileOrDirectory(ctx, fp, ".fslckout")
+	if !found {
+		checkoutFile, found = FindFileOrDirectory(ctx, fp, "_FOSSIL_")
+		if !found {
+			return Result{}, false, nil
+		}
+	}
+
+	logger := log.Extract(ctx)
+	folder := filepath.Dir(checkoutFile)
+
+	branch, err := readFossilBranch(checkoutFile)
+	if err != nil {
+		logger.Debugf("failed to read fossil branch from %q: %s", checkoutFile, err)
+
+		branch, err = findFossilBranch(ctx, folder)
+	}
+
+	if err != nil {
+		logger.Errorf(
+			"error finding for branch name from %q: %s",
+			folder,
+			err,
+		)
+	}
+
+	return Result{
+		Project: filepath.Base(folder),
+		Branch:  branch,
+		Folder:  folder,
+	}, true, nil
+}
+
+// readFossilBranch reads the branch of the checkout from the repository
+// database, which is stored in the vvar table of the checkout database.
+func readFossilBranch(checkoutFile string) (string, error) {
+	checkout, repository, err := readFossilCheckout(checkoutFile)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(repository) {
+		repository = filepath.Join(filepath.Dir(checkoutFile), repository)
+	}
+
+	db, err := openSqliteFile(repository)
+	if err != nil {
+		return "", fmt.Errorf("failed to open fossil repository %q: %s", repository, err)
+	}
+
+	defer db.Close()
+
+	tag, err := db.tableRootPage("tag")
+	if err != nil {
+		return "", err
+	}
+
+	var branchTagID int64
+
+	// columns of the tag table are tagid and tagname, where tagid is the rowid
+	err = db.scanTable(tag, func(rowid int64, values []any) bool {
+		if len(values) < 2 || values[1] != "branch" {
+			return true
+		}
+
+		branchTagID = rowid
+
+		return false
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read fossil tags: %s", err)
+	}
+
+	if branchTagID == 0 {
+		return "", errors.New("branch tag not found")
+	}
+
+	tagxref, err := db.tableRootPage("tagxref")
+	if err != nil {
+		return "", err
+	}
+
+	var branch string
+
+	// columns of the tagxref table are tagid, tagtype, srcid, origid, value,
+	// mtime and rid, where tagtype 0 marks a cancelled tag
+	err = db.scanTable(tagxref, func(_ int64, values []any) bool {
+		if len(values) < 7 || values[0] != branchTagID || values[6] != checkout {
+			return true
+		}
+
+		if tagtype, ok := values[1].(int64); !ok || tagtype <= 0 {
+			return true
+		}
+
+		branch, _ = values[4].(string)
+
+		return false
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read fossil tag references: %s", err)
+	}
+
+	return branch, nil
+}
+
+// readFossilCheckout reads the id of the checked out version and the path of
+// the repository database from the checkout database.
+func readFossilCheckout(fp string) (int64, string, error) {
+	db, err := openSqliteFile(fp)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to open fossil checkout %q: %s", fp, err)
+	}
+
+	defer db.Close()
+
+	vvar, err := db.tableRootPage("vvar")
+	if err != nil {
+		return 0, "", err
+	}
+
+	var (
+		checkout   int64
+		repository string
+	)
+
+	// columns of the vvar table are name and value, integers may be stored as text
+	err = db.scanTable(vvar, func(_ int64, values []any) bool {
+		if len(values) < 2 {
+			return true
+		}
+
+		switch values[0] {
+		case "checkout":
+			switch value := values[1].(type) {
+			case int64:
+				checkout = value
+			case string:
+				checkout, _ = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			}
+		case "repository":
+			repository, _ = values[1].(string)
+		}
+
+		return true
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read fossil checkout variables: %s", err)
+	}
+
+	if checkout == 0 || repository == "" {
+		return 0, "", errors.New("checkout or repository not found")
+	}
+
+	return checkout, repository, nil
+}
+
+// findFossilBranch reads the branch from the manifest.tags file.
+func findFossilBranch(ctx context.Context, fp string) (string, error) {
+	p := filepath.Join(fp, "manifest.tags")
+	if !fileOrDirExists(p) {
+		return "", nil
+	}
+
+	lines, err := ReadFile(ctx, p, maxMetadataLines)
+	if err != nil {
+		return "", fmt.Errorf("failed while opening file %q: %s", p, err)
+	}
+
+	for _, line := range lines {
+		if branch, ok := strings.CutPrefix(line, "branch "); ok {
+			return strings.TrimSpace(branch), nil
+		}
+	}
+
+	return "", nil
+}
+
+// ID returns its id.
+func (Fossil) ID() DetectorID {
+	return FossilDetector
+}
@@ -0,0 +1,94 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFossil_Detect(t *testing.T) {
+	fp := setupTestFossil(t, ".fslckout")
+
+	copyFile(t, "testdata/fossil/manifest.tags", filepath.Join(fp, "wakatime-cli/manifest.tags"))
+
+	f := project.Fossil{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := f.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "feature/billing",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestFossil_Detect_CheckoutDatabase(t *testing.T) {
+	fp := setupTestFossil(t, ".fslckout")
+
+	copyFile(t, "testdata/fossil/fslckout", filepath.Join(fp, "wakatime-cli/.fslckout"))
+	copyFile(t, "testdata/fossil/wakatime-cli.fossil", filepath.Join(fp, "wakatime-cli.fossil"))
+
+	f := project.Fossil{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := f.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "feature/billing",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestFossil_Detect_Windows(t *testing.T) {
+	fp := setupTestFossil(t, "_FOSSIL_")
+
+	f := project.Fossil{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := f.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestFossil_ID(t *testing.T) {
+	f := project.Fossil{}
+
+	assert.Equal(t, project.FossilDetector, f.ID())
+}
+
+func setupTestFossil(t *testing.T, checkoutFile string) (fp string) {
+	tmpDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/src/pkg"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	tmpFile, err := os.Create(filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"))
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	err = os.WriteFile(filepath.Join(tmpDir, "wakatime-cli", checkoutFile), []byte("SQLite format 3"), 0600)
+	require.NoError(t, err)
+
+	return tmpDir
+}
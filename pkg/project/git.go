@@ -3,23 +3,40 @@ package project
 import (
 	"context"
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/log"
 	"github.com/wakatime/wakatime-cli/pkg/regex"
 )
 
+var gitCommitRegex = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
 // Git contains git data.
 type Git struct {
 	// Filepath contains the entity path.
 	Filepath string
+	// DetectRemoteURL when enabled sets the url of the preferred git remote in the result.
+	DetectRemoteURL bool
 	// ProjectFromGitRemote when enabled uses the git remote as the project name instead of local git folder.
 	ProjectFromGitRemote bool
+	// RemoteAliases maps lowercased git remotes, e.g. the remote of a fork, to a
+	// canonical project name.
+	RemoteAliases map[string]string
+	// RemoteNames are the names of the preferred git remotes in order of priority,
+	// e.g. upstream for forks. Defaults to origin or the first remote found.
+	RemoteNames []string
 	// SubmoduleDisabledPatterns will be matched against the submodule path and if matching, will skip it.
 	SubmoduleDisabledPatterns []regex.Regex
 	// SubmoduleProjectMapPatterns will be matched against the submodule path and if matching, will use the project map.
 	SubmoduleProjectMapPatterns []MapPattern
+	// WorkTrees contains git directories outside of their work tree, in addition
+	// to the GIT_DIR and GIT_WORK_TREE environment variables.
+	WorkTrees []GitWorkTree
 }
 
 // Detect gets information about the git project for a given file.
@@ -40,31 +57,57 @@ func (g Git) Detect(ctx context.Context) (Result, bool, error) {
 	}
 
 	if ok {
-		project := projectOrRemote(ctx, filepath.Base(gitdirSubmodule), g.ProjectFromGitRemote, gitdirSubmodule)
+		branch, err := findGitBranch(ctx, filepath.Join(gitdirSubmodule, "HEAD"))
+		if err != nil {
+			logger.Errorf(
+				"error finding branch from %q: %s",
+				filepath.Join(filepath.Dir(gitdirSubmodule), "HEAD"),
+				err,
+			)
+		}
+
+		project, remoteURL := g.projectOrRemote(ctx, filepath.Base(gitdirSubmodule), gitdirSubmodule, gitdirSubmodule, branch)
 
 		// If submodule has a project map, then use it.
 		if result, ok := matchPattern(ctx, gitdirSubmodule, g.SubmoduleProjectMapPatterns); ok {
 			project = result
 		}
 
-		branch, err := findGitBranch(ctx, filepath.Join(gitdirSubmodule, "HEAD"))
+		return Result{
+			Project:   project,
+			Branch:    branch,
+			Folder:    filepath.Dir(gitdirSubmodule),
+			RemoteURL: remoteURL,
+		}, true, nil
+	}
+
+	// Find for external git dir, e.g. a bare repository managing dotfiles
+	workTree, isWorkTree := findGitWorkTree(ctx, fp, g.WorkTrees)
+
+	// Find for .git file or directory
+	dotGit, found := FindFileOrDirectory(ctx, fp, ".git")
+
+	// A repository nested within the work tree takes precedence
+	if isWorkTree && (!found || len(filepath.Dir(dotGit)) <= len(workTree.WorkTree)) {
+		branch, err := findGitBranch(ctx, filepath.Join(workTree.GitDir, "HEAD"))
 		if err != nil {
 			logger.Errorf(
 				"error finding branch from %q: %s",
-				filepath.Join(filepath.Dir(gitdirSubmodule), "HEAD"),
+				filepath.Join(workTree.GitDir, "HEAD"),
 				err,
 			)
 		}
 
+		project, remoteURL := g.projectOrRemote(ctx, gitWorkTreeProjectName(workTree), workTree.GitDir, workTree.GitDir, branch)
+
 		return Result{
-			Project: project,
-			Branch:  branch,
-			Folder:  filepath.Dir(gitdirSubmodule),
+			Project:   project,
+			Branch:    branch,
+			Folder:    workTree.WorkTree,
+			RemoteURL: remoteURL,
 		}, true, nil
 	}
 
-	// Find for .git file or directory
-	dotGit, found := FindFileOrDirectory(ctx, fp, ".git")
 	if !found {
 		return Result{}, false, nil
 	}
@@ -95,8 +138,6 @@ func (g Git) Detect(ctx context.Context) (Result, bool, error) {
 			dir = commondir
 		}
 
-		project := projectOrRemote(ctx, filepath.Base(dir), g.ProjectFromGitRemote, commondir)
-
 		branch, err := findGitBranch(ctx, filepath.Join(gitdir, "HEAD"))
 		if err != nil {
 			logger.Errorf(
@@ -106,17 +147,18 @@ func (g Git) Detect(ctx context.Context) (Result, bool, error) {
 			)
 		}
 
+		project, remoteURL := g.projectOrRemote(ctx, filepath.Base(dir), commondir, gitdir, branch)
+
 		return Result{
-			Project: project,
-			Branch:  branch,
-			Folder:  dir,
+			Project:   project,
+			Branch:    branch,
+			Folder:    dir,
+			RemoteURL: remoteURL,
 		}, true, nil
 	}
 
 	// Otherwise it's only a plain .git file and not a submodule
 	if gitdir != "" && !strings.Contains(gitdir, "modules") {
-		project := projectOrRemote(ctx, filepath.Base(filepath.Join(dotGit, "..")), g.ProjectFromGitRemote, gitdir)
-
 		branch, err := findGitBranch(ctx, filepath.Join(gitdir, "HEAD"))
 		if err != nil {
 			logger.Errorf(
@@ -126,10 +168,13 @@ func (g Git) Detect(ctx context.Context) (Result, bool, error) {
 			)
 		}
 
+		project, remoteURL := g.projectOrRemote(ctx, filepath.Base(filepath.Join(dotGit, "..")), gitdir, gitdir, branch)
+
 		return Result{
-			Project: project,
-			Branch:  branch,
-			Folder:  filepath.Join(gitdir, ".."),
+			Project:   project,
+			Branch:    branch,
+			Folder:    filepath.Join(gitdir, ".."),
+			RemoteURL: remoteURL,
 		}, true, nil
 	}
 
@@ -149,12 +194,13 @@ func (g Git) Detect(ctx context.Context) (Result, bool, error) {
 			)
 		}
 
-		project := projectOrRemote(ctx, filepath.Base(projectDir), g.ProjectFromGitRemote, gitDir)
+		project, remoteURL := g.projectOrRemote(ctx, filepath.Base(projectDir), gitDir, gitDir, branch)
 
 		return Result{
-			Project: project,
-			Branch:  branch,
-			Folder:  projectDir,
+			Project:   project,
+			Branch:    branch,
+			Folder:    projectDir,
+			RemoteURL: remoteURL,
 		}, true, nil
 	}
 
@@ -260,26 +306,45 @@ func resolveCommondir(ctx context.Context, fp string) (string, bool, error) {
 	return gitdir, true, nil
 }
 
-func projectOrRemote(ctx context.Context, projectName string, projectFromGitRemote bool, dotGitFolder string) string {
-	if !projectFromGitRemote {
-		return projectName
+// projectOrRemote returns the remote of the git config in dotGitFolder as project name,
+// if enabled. The gitdir and branch are used to evaluate conditional includes.
+// The remote url is returned as well, if enabled.
+func (g Git) projectOrRemote(ctx context.Context, projectName, dotGitFolder, gitdir, branch string) (string, string) {
+	if !g.ProjectFromGitRemote && !g.DetectRemoteURL {
+		return projectName, ""
 	}
 
 	logger := log.Extract(ctx)
 	configFile := filepath.Join(dotGitFolder, "config")
 
-	remote, err := findGitRemote(ctx, configFile)
+	rawURL, err := findGitRemote(ctx, configFile, gitdir, branch, g.RemoteNames)
 	if err != nil {
 		logger.Errorf("error finding git remote from %q: %s", configFile, err)
 
-		return projectName
+		return projectName, ""
 	}
 
-	if remote != "" {
-		return remote
+	var remoteURL string
+	if g.DetectRemoteURL {
+		remoteURL = rawURL
 	}
 
-	return projectName
+	if !g.ProjectFromGitRemote || rawURL == "" {
+		return projectName, remoteURL
+	}
+
+	remote := normalizeGitRemote(rawURL)
+	if remote == "" {
+		return projectName, remoteURL
+	}
+
+	if alias, ok := g.RemoteAliases[strings.ToLower(remote)]; ok {
+		logger.Debugf("using project alias %q of git remote %q", alias, remote)
+
+		return alias, remoteURL
+	}
+
+	return remote, remoteURL
 }
 
 func findGitBranch(ctx context.Context, fp string) (string, error) {
@@ -305,54 +370,198 @@ func findGitBranch(ctx context.Context, fp string) (string, error) {
 		return strings.TrimSpace(strings.SplitN(lines[0], "/", 3)[2]), nil
 	}
 
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	// HEAD is detached, e.g. while rebasing, bisecting or on a checked out commit
+	return findGitDetachedBranch(ctx, filepath.Dir(fp), strings.TrimSpace(lines[0]))
+}
+
+// findGitDetachedBranch returns the branch for a detached HEAD. While rebasing
+// or bisecting it's the branch which was checked out before. Otherwise it's
+// the first local branch, tag or remote branch pointing to the commit.
+func findGitDetachedBranch(ctx context.Context, gitdir, commit string) (string, error) {
+	for _, fp := range []string{
+		filepath.Join(gitdir, "rebase-merge", "head-name"),
+		filepath.Join(gitdir, "rebase-apply", "head-name"),
+		filepath.Join(gitdir, "BISECT_START"),
+	} {
+		branch, err := readGitBranchFile(ctx, fp)
+		if err != nil {
+			return "", err
+		}
+
+		if branch != "" {
+			return branch, nil
+		}
+	}
+
+	if !gitCommitRegex.MatchString(commit) {
+		return "", nil
+	}
+
+	// refs of worktrees are stored in the common git folder
+	refsDir := gitdir
+
+	if fileOrDirExists(filepath.Join(gitdir, "commondir")) {
+		commondir, ok, err := resolveCommondir(ctx, gitdir)
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			refsDir = commondir
+		}
+	}
+
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/remotes/"} {
+		names, err := findGitRefsAt(ctx, refsDir, prefix, commit)
+		if err != nil {
+			return "", err
+		}
+
+		for _, name := range names {
+			if prefix == "refs/remotes/" && strings.HasSuffix(name, "/HEAD") {
+				continue
+			}
+
+			return name, nil
+		}
+	}
+
 	return "", nil
 }
 
-func findGitRemote(ctx context.Context, fp string) (string, error) {
+// readGitBranchFile reads a branch name from a file like rebase-merge/head-name.
+// Returns an empty string, if the file does not exist or contains no branch name.
+func readGitBranchFile(ctx context.Context, fp string) (string, error) {
 	if !fileOrDirExists(fp) {
 		return "", nil
 	}
 
-	lines, err := ReadFile(ctx, fp, 1000)
+	lines, err := ReadFile(ctx, fp, 1)
 	if err != nil {
 		return "", fmt.Errorf("failed while opening file %q: %s", fp, err)
 	}
 
-	for i, line := range lines {
-		if strings.Trim(line, "\n\r\t") != "[remote \"origin\"]" {
-			continue
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	branch := strings.TrimPrefix(strings.TrimSpace(lines[0]), "refs/heads/")
+
+	// rebasing or bisecting started on a detached HEAD
+	if branch == "detached HEAD" || gitCommitRegex.MatchString(branch) {
+		return "", nil
+	}
+
+	return branch, nil
+}
+
+// findGitRefsAt returns the sorted names, without the passed in prefix, of all
+// refs with the prefix pointing to the passed in commit. Refs are read from loose
+// and packed refs. Loose annotated tags are not resolved, as they point to a tag object.
+func findGitRefsAt(ctx context.Context, gitdir, prefix, commit string) ([]string, error) {
+	found := map[string]struct{}{}
+
+	refsDir := filepath.Join(gitdir, filepath.FromSlash(prefix))
+
+	err := filepath.WalkDir(refsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+
+			return err
 		}
 
-		if i >= len(lines) {
-			continue
+		if d.IsDir() {
+			return nil
 		}
 
-		for _, subline := range lines[i+1:] {
-			if strings.HasPrefix(subline, "[") {
-				break
+		lines, err := ReadFile(ctx, p, 1)
+		if err != nil || len(lines) == 0 {
+			return err
+		}
+
+		if strings.TrimSpace(lines[0]) == commit {
+			name, err := filepath.Rel(refsDir, p)
+			if err != nil {
+				return err
 			}
 
-			if strings.HasPrefix(strings.TrimSpace(subline), "url = ") {
-				remote := strings.Trim(subline, "\n\r\t")
+			found[filepath.ToSlash(name)] = struct{}{}
+		}
 
-				parts := strings.SplitN(remote, "=", 2)
-				if len(parts) != 2 {
-					return "", fmt.Errorf("invalid origin url from %q: %s", fp, subline)
-				}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read loose refs: %s", err)
+	}
+
+	packedRefs := filepath.Join(gitdir, "packed-refs")
+	if fileOrDirExists(packedRefs) {
+		lines, err := ReadFile(ctx, packedRefs, maxMetadataLines)
+		if err != nil {
+			return nil, fmt.Errorf("failed while opening file %q: %s", packedRefs, err)
+		}
+
+		var lastRef string
 
-				remote = parts[1]
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
 
-				parts = strings.SplitN(remote, ":", 2)
-				if len(parts) != 2 {
-					return "", fmt.Errorf("invalid origin url from %q: %s", fp, subline)
+			// peeled annotated tag, pointing to the commit of the previous ref
+			if peeled, ok := strings.CutPrefix(line, "^"); ok {
+				if peeled == commit {
+					if name, ok := strings.CutPrefix(lastRef, prefix); ok {
+						found[name] = struct{}{}
+					}
 				}
 
-				return strings.TrimSpace(strings.TrimSuffix(parts[1], ".git")), nil
+				continue
+			}
+
+			hash, ref, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+
+			lastRef = ref
+
+			if hash != commit {
+				continue
+			}
+
+			if name, ok := strings.CutPrefix(ref, prefix); ok {
+				found[name] = struct{}{}
 			}
 		}
 	}
 
-	return "", nil
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// findGitRemote returns the url of the preferred remote of the git config fp.
+func findGitRemote(ctx context.Context, fp, gitdir, branch string, preferred []string) (string, error) {
+	if !fileOrDirExists(fp) {
+		return "", nil
+	}
+
+	config, err := parseGitConfig(ctx, fp, gitdir, branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse git config %q: %s", fp, err)
+	}
+
+	return config.remoteURL(preferred), nil
 }
 
 // ID returns its id.
@@ -75,6 +75,364 @@ func TestGit_Detect_DetachedHead(t *testing.T) {
 	}, result)
 }
 
+func TestGit_Detect_DetachedHeadBranch(t *testing.T) {
+	tests := map[string]struct {
+		Files    map[string]string
+		Expected string
+	}{
+		"rebase merge": {
+			Files: map[string]string{
+				"rebase-merge/head-name": "refs/heads/feature/billing\n",
+			},
+			Expected: "feature/billing",
+		},
+		"rebase apply": {
+			Files: map[string]string{
+				"rebase-apply/head-name": "refs/heads/billing\n",
+			},
+			Expected: "billing",
+		},
+		"rebase on detached head": {
+			Files: map[string]string{
+				"rebase-merge/head-name": "detached HEAD\n",
+			},
+			Expected: "",
+		},
+		"bisect": {
+			Files: map[string]string{
+				"BISECT_START": "master\n",
+			},
+			Expected: "master",
+		},
+		"local branch": {
+			Files: map[string]string{
+				"refs/heads/billing": "f4f242d698fa07c298592a66d6546ac9b6b34d1e\n",
+			},
+			Expected: "billing",
+		},
+		"tag": {
+			Files: map[string]string{
+				"packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" +
+					"1111111111111111111111111111111111111111 refs/tags/v1.0.0\n" +
+					"^f4f242d698fa07c298592a66d6546ac9b6b34d1e\n",
+			},
+			Expected: "v1.0.0",
+		},
+		"remote branch": {
+			Files: map[string]string{
+				"refs/remotes/origin/HEAD": "f4f242d698fa07c298592a66d6546ac9b6b34d1e\n",
+				"refs/remotes/origin/main": "f4f242d698fa07c298592a66d6546ac9b6b34d1e\n",
+			},
+			Expected: "origin/main",
+		},
+		"local branch before tag": {
+			Files: map[string]string{
+				"refs/tags/v1.0.0": "f4f242d698fa07c298592a66d6546ac9b6b34d1e\n",
+				"packed-refs":      "f4f242d698fa07c298592a66d6546ac9b6b34d1e refs/heads/release\n",
+			},
+			Expected: "release",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fp := setupTestGitBasicDetachedHead(t)
+
+			for file, content := range test.Files {
+				dest := filepath.Join(fp, "wakatime-cli/.git", file)
+
+				err := os.MkdirAll(filepath.Dir(dest), os.FileMode(int(0700)))
+				require.NoError(t, err)
+
+				err = os.WriteFile(dest, []byte(content), 0600)
+				require.NoError(t, err)
+			}
+
+			g := project.Git{
+				Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+			}
+
+			result, detected, err := g.Detect(context.Background())
+			require.NoError(t, err)
+
+			assert.True(t, detected)
+			assert.Equal(t, test.Expected, result.Branch)
+		})
+	}
+}
+
+func TestGit_Detect_GitRemote(t *testing.T) {
+	tests := map[string]struct {
+		Files         map[string]string
+		RemoteNames   []string
+		RemoteAliases map[string]string
+		Expected      string
+	}{
+		"https": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = https://github.com/wakatime/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"ssh with port": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = ssh://git@gitlab.com:2222/wakatime/wakatime-cli.git/\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"git protocol": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = git://github.com/wakatime/wakatime-cli\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"subgroups": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = git@gitlab.com:wakatime/cli/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/cli/wakatime-cli",
+		},
+		"local path": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = /srv/git/wakatime/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"quoted url with comment": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"] ; primary\n\tURL = \"git@github.com:wakatime/wakatime-cli.git\" # ssh\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"origin after other remote": {
+			Files: map[string]string{
+				".git/config": "[remote \"fork\"]\n\turl = git@github.com:alan/wakatime-cli.git\n" +
+					"[remote \"origin\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"first remote without origin": {
+			Files: map[string]string{
+				".git/config": "[remote \"upstream\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n" +
+					"[remote \"fork\"]\n\turl = git@github.com:alan/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"preferred remote name": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = git@github.com:alan/wakatime-cli.git\n" +
+					"[remote \"upstream\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+			},
+			RemoteNames: []string{"upstream"},
+			Expected:    "wakatime/wakatime-cli",
+		},
+		"remote priority list": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = git@github.com:alan/wakatime-cli.git\n" +
+					"[remote \"company\"]\n\turl = git@github.com:acme/wakatime-cli.git\n" +
+					"[remote \"upstream\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+			},
+			RemoteNames: []string{"mirror", "upstream", "company"},
+			Expected:    "wakatime/wakatime-cli",
+		},
+		"preferred remote missing falls back to origin": {
+			Files: map[string]string{
+				".git/config": "[remote \"fork\"]\n\turl = git@github.com:alan/wakatime-cli.git\n" +
+					"[remote \"origin\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+			},
+			RemoteNames: []string{"upstream"},
+			Expected:    "wakatime/wakatime-cli",
+		},
+		"remote alias": {
+			Files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = git@github.com:Alan/wakatime-cli.git\n",
+			},
+			RemoteAliases: map[string]string{
+				"alan/wakatime-cli": "wakatime/wakatime-cli",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"insteadOf": {
+			Files: map[string]string{
+				".git/config": "[url \"git@github.com:\"]\n\tinsteadOf = gh:\n" +
+					"[url \"git@github.com:wakatime/\"]\n\tinsteadOf = gh:waka/\n" +
+					"[remote \"origin\"]\n\turl = gh:waka/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"insteadOf from global config": {
+			Files: map[string]string{
+				"home/.gitconfig": "[url \"https://github.com/\"]\n\tinsteadOf = gh:\n",
+				".git/config":     "[remote \"origin\"]\n\turl = gh:wakatime/wakatime-cli\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"include": {
+			Files: map[string]string{
+				".git/config": "[include]\n\tpath = ../remotes.inc\n",
+				"remotes.inc": "[remote \"origin\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"includeIf gitdir": {
+			Files: map[string]string{
+				".git/config": "[includeIf \"gitdir:wakatime-cli/\"]\n\tpath = ../remotes.inc\n" +
+					"[includeIf \"gitdir:other/\"]\n\tpath = ../other.inc\n",
+				"remotes.inc": "[remote \"origin\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+				"other.inc":   "[remote \"origin\"]\n\turl = git@github.com:alan/other.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"includeIf onbranch": {
+			Files: map[string]string{
+				".git/config": "[includeIf \"onbranch:master\"]\n\tpath = ../remotes.inc\n" +
+					"[includeIf \"onbranch:feature/\"]\n\tpath = ../other.inc\n",
+				"remotes.inc": "[remote \"origin\"]\n\turl = git@github.com:wakatime/wakatime-cli.git\n",
+				"other.inc":   "[remote \"origin\"]\n\turl = git@github.com:alan/other.git\n",
+			},
+			Expected: "wakatime/wakatime-cli",
+		},
+		"no remote": {
+			Files: map[string]string{
+				".git/config": "[core]\n\tbare = false\n",
+			},
+			Expected: "wakatime-cli",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fp := setupTestGitBasic(t)
+
+			home := filepath.Join(fp, "home")
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+			t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+			for file, content := range test.Files {
+				dest := filepath.Join(fp, "wakatime-cli", file)
+				if filepath.Dir(file) == "home" {
+					dest = filepath.Join(fp, file)
+				}
+
+				err := os.MkdirAll(filepath.Dir(dest), os.FileMode(int(0700)))
+				require.NoError(t, err)
+
+				err = os.WriteFile(dest, []byte(content), 0600)
+				require.NoError(t, err)
+			}
+
+			g := project.Git{
+				Filepath:             filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+				ProjectFromGitRemote: true,
+				RemoteAliases:        test.RemoteAliases,
+				RemoteNames:          test.RemoteNames,
+			}
+
+			result, detected, err := g.Detect(context.Background())
+			require.NoError(t, err)
+
+			assert.True(t, detected)
+			assert.Equal(t, test.Expected, result.Project)
+		})
+	}
+}
+
+func TestGit_Detect_GitWorkTree(t *testing.T) {
+	tests := map[string]struct {
+		Env       map[string]string
+		WorkTrees func(root string) []project.GitWorkTree
+		Filepath  string
+		Expected  project.Result
+	}{
+		"GIT_DIR and GIT_WORK_TREE": {
+			Env: map[string]string{
+				"GIT_DIR":       ".dotfiles",
+				"GIT_WORK_TREE": ".",
+			},
+			Filepath: ".config/nvim/init.lua",
+			Expected: project.Result{Project: "dotfiles", Branch: "main", Folder: "."},
+		},
+		"GIT_DIR with core.worktree": {
+			Env: map[string]string{
+				"GIT_DIR": "dotfiles.git",
+			},
+			Filepath: ".config/nvim/init.lua",
+			Expected: project.Result{Project: "dotfiles", Branch: "feature/env", Folder: "."},
+		},
+		"configured work tree": {
+			WorkTrees: func(root string) []project.GitWorkTree {
+				return []project.GitWorkTree{{WorkTree: root, GitDir: filepath.Join(root, ".dotfiles")}}
+			},
+			Filepath: ".config/nvim/init.lua",
+			Expected: project.Result{Project: "dotfiles", Branch: "main", Folder: "."},
+		},
+		"innermost configured work tree": {
+			WorkTrees: func(root string) []project.GitWorkTree {
+				return []project.GitWorkTree{
+					{WorkTree: root, GitDir: filepath.Join(root, ".dotfiles")},
+					{WorkTree: filepath.Join(root, ".config"), GitDir: filepath.Join(root, "dotfiles.git")},
+				}
+			},
+			Filepath: ".config/nvim/init.lua",
+			Expected: project.Result{Project: "dotfiles", Branch: "feature/env", Folder: ".config"},
+		},
+		"nested repository takes precedence": {
+			Env: map[string]string{
+				"GIT_DIR":       ".dotfiles",
+				"GIT_WORK_TREE": ".",
+			},
+			Filepath: "projects/wakatime-cli/main.go",
+			Expected: project.Result{Project: "wakatime-cli", Branch: "master", Folder: "projects/wakatime-cli"},
+		},
+		"outside of work tree": {
+			Env: map[string]string{
+				"GIT_DIR":       ".dotfiles",
+				"GIT_WORK_TREE": ".config",
+			},
+			Filepath: "notes/todo.txt",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := setupTestGitWorkTree(t)
+
+			t.Setenv("GIT_DIR", "")
+			t.Setenv("GIT_WORK_TREE", "")
+
+			for key, value := range test.Env {
+				t.Setenv(key, filepath.Join(root, value))
+			}
+
+			var workTrees []project.GitWorkTree
+			if test.WorkTrees != nil {
+				workTrees = test.WorkTrees(root)
+			}
+
+			g := project.Git{
+				Filepath:  filepath.Join(root, test.Filepath),
+				WorkTrees: workTrees,
+			}
+
+			result, detected, err := g.Detect(context.Background())
+			require.NoError(t, err)
+
+			if test.Expected.Project == "" {
+				assert.False(t, detected)
+				return
+			}
+
+			assert.True(t, detected)
+			assert.Equal(t, project.Result{
+				Project: test.Expected.Project,
+				Branch:  test.Expected.Branch,
+				Folder:  filepath.Join(root, test.Expected.Folder),
+			}, result)
+		})
+	}
+}
+
 func TestGit_Detect_GitConfigFile_File(t *testing.T) {
 	fp := setupTestGitFile(t)
 
@@ -561,6 +919,35 @@ func setupTestGitWorktreeBareRepo(t *testing.T) (fp string) {
 	return tmpDir
 }
 
+func setupTestGitWorkTree(t *testing.T) (fp string) {
+	tmpDir, err := realpath.Realpath(t.TempDir())
+	require.NoError(t, err)
+
+	files := map[string]string{
+		".dotfiles/HEAD":                    "ref: refs/heads/main\n",
+		".dotfiles/config":                  "[core]\n\tbare = true\n",
+		"dotfiles.git/HEAD":                 "ref: refs/heads/feature/env\n",
+		"dotfiles.git/config":               "[core]\n\tbare = false\n\tworktree = ..\n",
+		".config/nvim/init.lua":             "",
+		"notes/todo.txt":                    "",
+		"projects/wakatime-cli/.git/HEAD":   "ref: refs/heads/master\n",
+		"projects/wakatime-cli/.git/config": "",
+		"projects/wakatime-cli/main.go":     "",
+	}
+
+	for file, content := range files {
+		dest := filepath.Join(tmpDir, file)
+
+		err := os.MkdirAll(filepath.Dir(dest), os.FileMode(int(0700)))
+		require.NoError(t, err)
+
+		err = os.WriteFile(dest, []byte(content), 0600)
+		require.NoError(t, err)
+	}
+
+	return tmpDir
+}
+
 func setupTestGitSubmodule(t *testing.T) (fp string) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,520 @@
+package project
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/glob"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// maxGitConfigIncludeDepth is the maximum depth of nested include files, same as git.
+const maxGitConfigIncludeDepth = 10
+
+// gitConfig contains the parsed variables of git config files. Keys are in the
+// canonical form section.subsection.key, with section and key lowercased.
+type gitConfig struct {
+	keys   []string
+	values map[string][]string
+}
+
+// gitConfigParser parses git config files and resolves include files.
+type gitConfigParser struct {
+	// gitdir is the git directory used to evaluate includeIf conditions.
+	gitdir string
+	// branch is the checked out branch used to evaluate includeIf conditions.
+	branch string
+	config *gitConfig
+}
+
+// parseGitConfig parses the git config file fp. Global config files are read
+// first, so variables of the repository config take precedence.
+func parseGitConfig(ctx context.Context, fp, gitdir, branch string) (*gitConfig, error) {
+	p := gitConfigParser{
+		gitdir: gitdir,
+		branch: branch,
+		config: &gitConfig{values: make(map[string][]string)},
+	}
+
+	for _, global := range globalGitConfigFiles() {
+		if err := p.parseFile(ctx, global, 0); err != nil {
+			log.Extract(ctx).Warnf("failed to parse global git config %q: %s", global, err)
+		}
+	}
+
+	if err := p.parseFile(ctx, fp, 0); err != nil {
+		return nil, err
+	}
+
+	return p.config, nil
+}
+
+// globalGitConfigFiles returns the paths of the user's global git config files.
+func globalGitConfigFiles() []string {
+	var files []string
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = ""
+	}
+
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+
+	if xdg != "" {
+		files = append(files, filepath.Join(xdg, "git", "config"))
+	}
+
+	if home != "" {
+		files = append(files, filepath.Join(home, ".gitconfig"))
+	}
+
+	return files
+}
+
+// Get returns the last value of a variable.
+func (c *gitConfig) Get(key string) (string, bool) {
+	values := c.values[key]
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[len(values)-1], true
+}
+
+// GetAll returns all values of a multi-valued variable.
+func (c *gitConfig) GetAll(key string) []string {
+	return c.values[key]
+}
+
+// Subsections returns the subsections of a section in order of appearance.
+func (c *gitConfig) Subsections(section string) []string {
+	var (
+		subsections []string
+		seen        = make(map[string]bool)
+	)
+
+	prefix := strings.ToLower(section) + "."
+
+	for _, key := range c.keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		rest := key[len(prefix):]
+
+		i := strings.LastIndex(rest, ".")
+		if i < 0 {
+			continue
+		}
+
+		if name := rest[:i]; !seen[name] {
+			seen[name] = true
+			subsections = append(subsections, name)
+		}
+	}
+
+	return subsections
+}
+
+func (c *gitConfig) add(key, value string) {
+	if _, ok := c.values[key]; !ok {
+		c.keys = append(c.keys, key)
+	}
+
+	c.values[key] = append(c.values[key], value)
+}
+
+func (p gitConfigParser) parseFile(ctx context.Context, fp string, depth int) error {
+	if depth > maxGitConfigIncludeDepth {
+		return fmt.Errorf("exceeded maximum include depth of %d at %q", maxGitConfigIncludeDepth, fp)
+	}
+
+	f, err := os.Open(fp) // nolint:gosec
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to open file %q: %s", fp, err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Extract(ctx).Debugf("failed to close file %q: %s", fp, err)
+		}
+	}()
+
+	var (
+		section    string
+		subsection string
+		hasSub     bool
+		lineNo     int
+	)
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		// continuation lines end with an unescaped backslash
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) && scanner.Scan() {
+			lineNo++
+			line = line[:len(line)-1] + scanner.Text()
+		}
+
+		line = strings.TrimSpace(line)
+
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			var rest string
+
+			section, subsection, hasSub, rest, err = parseGitConfigSection(line)
+			if err != nil {
+				return fmt.Errorf("invalid section at %s:%d: %s", fp, lineNo, err)
+			}
+
+			line = strings.TrimSpace(rest)
+			if line == "" || line[0] == '#' || line[0] == ';' {
+				continue
+			}
+		}
+
+		if section == "" {
+			return fmt.Errorf("variable outside of section at %s:%d", fp, lineNo)
+		}
+
+		name, value := parseGitConfigVariable(line)
+		if name == "" {
+			continue
+		}
+
+		key := section + "." + name
+		if hasSub {
+			key = section + "." + subsection + "." + name
+		}
+
+		p.config.add(key, value)
+
+		if name != "path" || value == "" {
+			continue
+		}
+
+		var include bool
+
+		switch {
+		case section == "include" && !hasSub:
+			include = true
+		case section == "includeif" && hasSub:
+			include = p.matchIncludeIf(subsection, fp)
+		}
+
+		if !include {
+			continue
+		}
+
+		if err := p.parseFile(ctx, resolveGitConfigPath(value, fp), depth+1); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read file %q: %s", fp, err)
+	}
+
+	return nil
+}
+
+// parseGitConfigSection parses a section header like [section "subsection"]
+// or the deprecated [section.subsection]. It returns the remainder of the line.
+func parseGitConfigSection(line string) (section, subsection string, hasSub bool, rest string, err error) {
+	end := strings.Index(line, "]")
+
+	if quote := strings.Index(line, `"`); quote >= 0 && quote < end {
+		section = strings.ToLower(strings.TrimSpace(line[1:quote]))
+
+		var b strings.Builder
+
+		i := quote + 1
+		for ; i < len(line) && line[i] != '"'; i++ {
+			if line[i] == '\\' && i+1 < len(line) {
+				i++
+			}
+
+			b.WriteByte(line[i])
+		}
+
+		if i >= len(line) || !strings.HasPrefix(line[i+1:], "]") {
+			return "", "", false, "", fmt.Errorf("unterminated subsection %q", line)
+		}
+
+		return section, b.String(), true, line[i+2:], nil
+	}
+
+	if end < 0 {
+		return "", "", false, "", fmt.Errorf("unterminated section %q", line)
+	}
+
+	name := strings.TrimSpace(line[1:end])
+	rest = line[end+1:]
+
+	if i := strings.Index(name, "."); i >= 0 {
+		return strings.ToLower(name[:i]), strings.ToLower(name[i+1:]), true, rest, nil
+	}
+
+	return strings.ToLower(name), "", false, rest, nil
+}
+
+// parseGitConfigVariable parses a line like `name = value`. A name without
+// value is a boolean true. Quotes, escape sequences and comments are resolved.
+func parseGitConfigVariable(line string) (string, string) {
+	name, raw, found := strings.Cut(line, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if !found {
+		if i := strings.IndexAny(name, "#;"); i >= 0 {
+			name = strings.TrimSpace(name[:i])
+		}
+
+		return name, "true"
+	}
+
+	var (
+		b       strings.Builder
+		quoted  bool
+		pending strings.Builder
+	)
+
+	raw = strings.TrimSpace(raw)
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+
+		switch {
+		case c == '\\' && i+1 < len(raw):
+			i++
+
+			b.WriteString(pending.String())
+			pending.Reset()
+
+			switch raw[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'b':
+				b.WriteByte('\b')
+			default:
+				b.WriteByte(raw[i])
+			}
+		case c == '"':
+			b.WriteString(pending.String())
+			pending.Reset()
+
+			quoted = !quoted
+		case !quoted && (c == '#' || c == ';'):
+			return name, b.String()
+		case !quoted && (c == ' ' || c == '\t'):
+			// whitespace is only kept between words
+			pending.WriteByte(c)
+		default:
+			b.WriteString(pending.String())
+			pending.Reset()
+			b.WriteByte(c)
+		}
+	}
+
+	return name, b.String()
+}
+
+// matchIncludeIf evaluates an includeIf condition like gitdir:~/work/ or onbranch:main.
+func (p gitConfigParser) matchIncludeIf(condition, fp string) bool {
+	kind, pattern, ok := strings.Cut(condition, ":")
+	if !ok {
+		return false
+	}
+
+	switch kind {
+	case "gitdir", "gitdir/i":
+		if p.gitdir == "" {
+			return false
+		}
+
+		return matchGitConfigGlob(gitdirPattern(pattern, fp), filepath.ToSlash(p.gitdir)+"/", kind == "gitdir/i")
+	case "onbranch":
+		if p.branch == "" {
+			return false
+		}
+
+		if strings.HasSuffix(pattern, "/") {
+			pattern += "**"
+		}
+
+		return matchGitConfigGlob(pattern, p.branch, false)
+	default:
+		return false
+	}
+}
+
+// gitdirPattern expands a gitdir condition pattern the same way git does.
+func gitdirPattern(pattern, fp string) string {
+	switch {
+	case strings.HasPrefix(pattern, "~/"):
+		pattern = expandHome(pattern)
+	case strings.HasPrefix(pattern, "./"):
+		pattern = filepath.Join(filepath.Dir(fp), pattern[2:])
+	case !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, "/"):
+		pattern = "**/" + pattern
+	}
+
+	pattern = filepath.ToSlash(pattern)
+
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	} else {
+		pattern += "/"
+	}
+
+	return pattern
+}
+
+// matchGitConfigGlob matches s against a wildmatch pattern, where ** matches across slashes.
+func matchGitConfigGlob(pattern, s string, ignoreCase bool) bool {
+	if !ignoreCase {
+		return glob.Match(pattern, s)
+	}
+
+	return glob.Match(strings.ToLower(pattern), strings.ToLower(s))
+}
+
+// resolveGitConfigPath resolves an include path relative to the including file.
+func resolveGitConfigPath(path, fp string) string {
+	path = expandHome(path)
+
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(fp), path)
+}
+
+// expandHome replaces a leading ~/ with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[1:])
+}
+
+// remoteURL returns the url of the first preferred remote found. It falls back
+// to origin and then to the first configured remote. Rewrite rules of
+// url.<base>.insteadOf are applied.
+func (c *gitConfig) remoteURL(preferred []string) string {
+	var names []string
+
+	for _, name := range preferred {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	names = append(names, "origin")
+	names = append(names, c.Subsections("remote")...)
+
+	for _, name := range names {
+		if u, ok := c.Get("remote." + name + ".url"); ok && u != "" {
+			return c.rewriteURL(u)
+		}
+	}
+
+	return ""
+}
+
+// rewriteURL applies the url.<base>.insteadOf rule with the longest matching prefix.
+func (c *gitConfig) rewriteURL(u string) string {
+	var (
+		base    string
+		longest int
+	)
+
+	bases := c.Subsections("url")
+	sort.Strings(bases)
+
+	for _, b := range bases {
+		for _, prefix := range c.GetAll("url." + b + ".insteadof") {
+			if strings.HasPrefix(u, prefix) && len(prefix) > longest {
+				base = b
+				longest = len(prefix)
+			}
+		}
+	}
+
+	if longest == 0 {
+		return u
+	}
+
+	return base + u[longest:]
+}
+
+var scpLikeURLRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// normalizeGitRemote converts a remote url like git@github.com:owner/repo.git,
+// https://github.com/owner/repo or ssh://git@host:22/owner/repo.git to owner/repo.
+func normalizeGitRemote(remote string) string {
+	remote = strings.TrimSpace(remote)
+
+	var path string
+
+	switch {
+	case strings.Contains(remote, "://"):
+		parsed, err := url.Parse(remote)
+		if err != nil {
+			return ""
+		}
+
+		path = parsed.Path
+
+		// local repositories have no owner, use the parent folder instead
+		if parsed.Scheme == "file" {
+			path = lastPathElements(path, 2)
+		}
+	case scpLikeURLRegex.MatchString(remote) && !filepath.IsAbs(remote):
+		path = scpLikeURLRegex.FindStringSubmatch(remote)[2]
+	default:
+		path = lastPathElements(filepath.ToSlash(remote), 2)
+	}
+
+	path = strings.Trim(path, "/")
+	path = strings.TrimSuffix(path, ".git")
+
+	return strings.Trim(path, "/")
+}
+
+// lastPathElements returns the last n elements of a slash separated path.
+func lastPathElements(path string, n int) string {
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, "/"), "/"), "/")
+	if len(parts) > n {
+		parts = parts[len(parts)-n:]
+	}
+
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,161 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// GitWorkTree maps a git directory outside of its work tree to the work tree,
+// e.g. a bare repository used to manage dotfiles.
+//
+// For example, configured in the ~/.wakatime.cfg file:
+//
+//	[git_work_trees]
+//	/home/user = /home/user/.dotfiles
+type GitWorkTree struct {
+	// WorkTree is the work tree folder. It's matched case insensitive.
+	WorkTree string
+	// GitDir is the git directory of the work tree.
+	GitDir string
+}
+
+// findGitWorkTree returns the external git work tree containing fp. Work trees
+// are read from the GIT_DIR and GIT_WORK_TREE environment variables and the
+// configured work trees. The innermost work tree takes precedence.
+func findGitWorkTree(ctx context.Context, fp string, workTrees []GitWorkTree) (GitWorkTree, bool) {
+	logger := log.Extract(ctx)
+
+	if env, ok := gitWorkTreeFromEnv(ctx); ok {
+		workTrees = append([]GitWorkTree{env}, workTrees...)
+	}
+
+	var (
+		found GitWorkTree
+		ok    bool
+	)
+
+	for _, wt := range workTrees {
+		folder, matched := matchGitWorkTree(fp, wt.WorkTree)
+		if !matched || (ok && len(folder) <= len(found.WorkTree)) {
+			continue
+		}
+
+		if !fileOrDirExists(filepath.Join(wt.GitDir, "HEAD")) {
+			logger.Debugf("skipping git work tree %q, no git dir found at %q", wt.WorkTree, wt.GitDir)
+			continue
+		}
+
+		found = GitWorkTree{
+			WorkTree: folder,
+			GitDir:   wt.GitDir,
+		}
+		ok = true
+	}
+
+	return found, ok
+}
+
+// gitWorkTreeFromEnv returns the work tree of the GIT_DIR environment variable.
+// The work tree is read from GIT_WORK_TREE or the core.worktree variable of
+// the git config, the same way git resolves it.
+func gitWorkTreeFromEnv(ctx context.Context) (GitWorkTree, bool) {
+	gitDir := os.Getenv("GIT_DIR")
+	if gitDir == "" {
+		return GitWorkTree{}, false
+	}
+
+	gitDir, err := filepath.Abs(gitDir)
+	if err != nil {
+		log.Extract(ctx).Warnf("failed to get absolute path of GIT_DIR %q: %s", gitDir, err)
+		return GitWorkTree{}, false
+	}
+
+	workTree := os.Getenv("GIT_WORK_TREE")
+	if workTree == "" {
+		workTree = findCoreWorkTree(ctx, gitDir)
+	}
+
+	if workTree == "" {
+		return GitWorkTree{}, false
+	}
+
+	workTree, err = filepath.Abs(workTree)
+	if err != nil {
+		log.Extract(ctx).Warnf("failed to get absolute path of GIT_WORK_TREE %q: %s", workTree, err)
+		return GitWorkTree{}, false
+	}
+
+	return GitWorkTree{
+		WorkTree: workTree,
+		GitDir:   gitDir,
+	}, true
+}
+
+// findCoreWorkTree returns the core.worktree variable of the git config in
+// gitDir. Relative paths are resolved against gitDir.
+func findCoreWorkTree(ctx context.Context, gitDir string) string {
+	configFile := filepath.Join(gitDir, "config")
+	if !fileOrDirExists(configFile) {
+		return ""
+	}
+
+	config, err := parseGitConfig(ctx, configFile, gitDir, "")
+	if err != nil {
+		log.Extract(ctx).Warnf("failed to parse git config %q: %s", configFile, err)
+		return ""
+	}
+
+	workTree, ok := config.Get("core.worktree")
+	if !ok || workTree == "" {
+		return ""
+	}
+
+	if !filepath.IsAbs(workTree) {
+		workTree = filepath.Join(gitDir, workTree)
+	}
+
+	return workTree
+}
+
+// matchGitWorkTree returns the work tree folder as found in fp, if fp is
+// within the work tree.
+func matchGitWorkTree(fp, workTree string) (string, bool) {
+	workTree = strings.TrimRight(workTree, `/\`)
+	if len(fp) < len(workTree) || !strings.EqualFold(fp[:len(workTree)], workTree) {
+		return "", false
+	}
+
+	rest := fp[len(workTree):]
+
+	// only match complete folder names
+	if rest != "" && rest[0] != '/' && rest[0] != '\\' {
+		return "", false
+	}
+
+	if workTree == "" {
+		// work tree is the root folder
+		return fp[:1], rest != ""
+	}
+
+	return fp[:len(workTree)], true
+}
+
+// gitWorkTreeProjectName returns the project name of an external git dir,
+// e.g. dotfiles for ~/.dotfiles or ~/dotfiles.git.
+func gitWorkTreeProjectName(wt GitWorkTree) string {
+	name := filepath.Base(filepath.Clean(wt.GitDir))
+	if name == ".git" {
+		name = filepath.Base(filepath.Dir(filepath.Clean(wt.GitDir)))
+	}
+
+	name = strings.TrimPrefix(strings.TrimSuffix(name, ".git"), ".")
+	if name == "" {
+		name = filepath.Base(wt.WorkTree)
+	}
+
+	return name
+}
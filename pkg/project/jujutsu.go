@@ -0,0 +1,145 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// Jujutsu contains jujutsu data.
+type Jujutsu struct {
+	// Filepath contains the entity path.
+	Filepath string
+}
+
+// Detect gets information about the jujutsu project for a given file.
+// Jujutsu stores its own metadata in a binary format, so the bookmark is
+// detected from the backing git repository. It's the bookmark pointing to
+// the commit checked out in git, which jujutsu keeps at the parent of the
+// working copy commit in colocated repositories.
+func (j Jujutsu) Detect(ctx context.Context) (Result, bool, error) {
+	var fp string
+
+	// Take only the directory
+	if fileOrDirExists(j.Filepath) {
+		fp = filepath.Dir(j.Filepath)
+	}
+
+	// Find for .jj folder
+	jjDirectory, found := FindFileOrDirectory(ctx, fp, ".jj")
+	if !found {
+		return Result{}, false, nil
+	}
+
+	logger := log.Extract(ctx)
+	folder := filepath.Dir(jjDirectory)
+
+	bookmark, err := findJujutsuBookmark(ctx, jjDirectory)
+	if err != nil {
+		logger.Errorf(
+			"error finding for bookmark name from %q: %s",
+			jjDirectory,
+			err,
+		)
+	}
+
+	return Result{
+		Project: filepath.Base(folder),
+		Branch:  bookmark,
+		Folder:  folder,
+	}, true, nil
+}
+
+func findJujutsuBookmark(ctx context.Context, fp string) (string, error) {
+	gitdir, err := findJujutsuGitdir(ctx, fp)
+	if err != nil {
+		return "", err
+	}
+
+	if gitdir == "" || !fileOrDirExists(filepath.Join(gitdir, "HEAD")) {
+		return "", nil
+	}
+
+	lines, err := ReadFile(ctx, filepath.Join(gitdir, "HEAD"), 1)
+	if err != nil {
+		return "", fmt.Errorf("failed while opening file %q: %s", gitdir, err)
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	head := strings.TrimSpace(lines[0])
+
+	if ref, ok := strings.CutPrefix(head, "ref: refs/heads/"); ok {
+		return ref, nil
+	}
+
+	if !gitCommitRegex.MatchString(head) {
+		return "", nil
+	}
+
+	bookmarks, err := findGitRefsAt(ctx, gitdir, "refs/heads/", head)
+	if err != nil {
+		return "", err
+	}
+
+	if len(bookmarks) == 0 {
+		return "", nil
+	}
+
+	return bookmarks[0], nil
+}
+
+// findJujutsuGitdir returns the git repository backing a jujutsu repository.
+// Returns an empty string, if the repository does not use the git backend.
+func findJujutsuGitdir(ctx context.Context, fp string) (string, error) {
+	repoDir := filepath.Join(fp, "repo")
+
+	// in secondary workspaces the repo file points to the main repo folder
+	if info, err := os.Stat(repoDir); err == nil && !info.IsDir() {
+		lines, err := ReadFile(ctx, repoDir, 1)
+		if err != nil {
+			return "", fmt.Errorf("failed while opening file %q: %s", repoDir, err)
+		}
+
+		if len(lines) == 0 {
+			return "", nil
+		}
+
+		repoDir = strings.TrimSpace(lines[0])
+		if !filepath.IsAbs(repoDir) {
+			repoDir = filepath.Join(fp, repoDir)
+		}
+	}
+
+	target := filepath.Join(repoDir, "store", "git_target")
+	if !fileOrDirExists(target) {
+		return "", nil
+	}
+
+	lines, err := ReadFile(ctx, target, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed while opening file %q: %s", target, err)
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+
+	gitdir := strings.TrimSpace(lines[0])
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(repoDir, "store", gitdir)
+	}
+
+	return filepath.Clean(gitdir), nil
+}
+
+// ID returns its id.
+func (Jujutsu) ID() DetectorID {
+	return JujutsuDetector
+}
@@ -0,0 +1,115 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJujutsu_Detect_Colocated(t *testing.T) {
+	fp := setupTestJujutsuColocated(t)
+
+	j := project.Jujutsu{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := j.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "feature/billing",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestJujutsu_Detect_LooseRef(t *testing.T) {
+	fp := setupTestJujutsuColocated(t)
+
+	err := os.MkdirAll(filepath.Join(fp, "wakatime-cli/.git/refs/heads"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	copyFile(t, "testdata/jj/HEAD", filepath.Join(fp, "wakatime-cli/.git/refs/heads/billing"))
+
+	j := project.Jujutsu{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := j.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, "billing", result.Branch)
+}
+
+func TestJujutsu_Detect_NoGitBackend(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/src/pkg"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	tmpFile, err := os.Create(filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"))
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	err = os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/.jj/repo/store"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	j := project.Jujutsu{
+		Filepath: filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := j.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Folder:  filepath.Join(tmpDir, "wakatime-cli"),
+	}, result)
+}
+
+func TestJujutsu_ID(t *testing.T) {
+	j := project.Jujutsu{}
+
+	assert.Equal(t, project.JujutsuDetector, j.ID())
+}
+
+func setupTestJujutsuColocated(t *testing.T) (fp string) {
+	tmpDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/src/pkg"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	tmpFile, err := os.Create(filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"))
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	err = os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/.jj/repo/store"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	err = os.WriteFile(
+		filepath.Join(tmpDir, "wakatime-cli/.jj/repo/store/git_target"),
+		[]byte("../../../.git"),
+		0600,
+	)
+	require.NoError(t, err)
+
+	err = os.Mkdir(filepath.Join(tmpDir, "wakatime-cli/.git"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	copyFile(t, "testdata/git_basic/config", filepath.Join(tmpDir, "wakatime-cli/.git/config"))
+	copyFile(t, "testdata/jj/HEAD", filepath.Join(tmpDir, "wakatime-cli/.git/HEAD"))
+	copyFile(t, "testdata/jj/packed-refs", filepath.Join(tmpDir, "wakatime-cli/.git/packed-refs"))
+
+	return tmpDir
+}
@@ -0,0 +1,159 @@
+package project
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
+
+	"github.com/slongfield/pyfmt"
+)
+
+// MapRules contains map rules data.
+type MapRules struct {
+	// Filepath contains the entity path.
+	Filepath string
+	// Branch is the detected branch name.
+	Branch string
+	// Hostname is the machine hostname.
+	Hostname string
+	// Project is the detected project name.
+	Project string
+	// RemoteURL is the url of the detected git remote.
+	RemoteURL string
+	// Rules are matched in order and the first matching rule is used.
+	Rules []MapRule
+}
+
+// Detect use the ~/.wakatime.cfg file to set custom project names by matching
+// the entity path, git remote url, branch name, hostname or environment
+// variables with regex patterns. Each rule goes under its own
+// [projectmap_rules.<name>] config section.
+//
+// For example:
+//
+//	[projectmap_rules.acme]
+//	remote = github\.com[:/]acme/([^/]+?)(\.git)?$
+//	env.ci_project_namespace = ^acme$
+//	project = acme-{remote[0]}
+//
+// Will result in any clone of 'github.com/acme/billing' to have project name
+// 'acme-billing', regardless of where it's located. Captured groups are
+// available as {path[n]}, {remote[n]}, {branch[n]}, {hostname[n]} and
+// {env.<name>[n]}, with n starting at 0. The detected project name is
+// available as {project}.
+func (m MapRules) Detect(ctx context.Context) (Result, bool, error) {
+	logger := log.Extract(ctx)
+
+	for _, rule := range m.Rules {
+		fields, ok := m.match(ctx, rule)
+		if !ok {
+			continue
+		}
+
+		fields["project"] = m.Project
+
+		project, err := pyfmt.Fmt(rule.Project, fields)
+		if err != nil {
+			logger.Errorf("error formatting project map rule %q: %s", rule.Name, err)
+			continue
+		}
+
+		if project == "" {
+			continue
+		}
+
+		return Result{
+			Project: project,
+		}, true, nil
+	}
+
+	return Result{}, false, nil
+}
+
+// match returns the captured groups of all conditions of the rule, if all of
+// them match. Rules without any condition never match.
+func (m MapRules) match(ctx context.Context, rule MapRule) (map[string]any, bool) {
+	conditions := []struct {
+		Field string
+		Regex regex.Regex
+		Value string
+	}{
+		{Field: "path", Regex: rule.Path, Value: m.Filepath},
+		{Field: "remote", Regex: rule.Remote, Value: m.RemoteURL},
+		{Field: "branch", Regex: rule.Branch, Value: m.Branch},
+		{Field: "hostname", Regex: rule.Hostname, Value: m.Hostname},
+	}
+
+	var (
+		fields  = make(map[string]any)
+		env     = make(map[string]any)
+		matched bool
+	)
+
+	for _, c := range conditions {
+		if c.Regex == nil {
+			fields[c.Field] = []string{}
+			continue
+		}
+
+		groups, ok := matchRuleCondition(ctx, c.Regex, c.Value)
+		if !ok {
+			return nil, false
+		}
+
+		fields[c.Field] = groups
+		matched = true
+	}
+
+	for name, rgx := range rule.Env {
+		groups, ok := matchRuleCondition(ctx, rgx, lookupEnvFold(name))
+		if !ok {
+			return nil, false
+		}
+
+		env[name] = groups
+		matched = true
+	}
+
+	fields["env"] = env
+
+	return fields, matched
+}
+
+// matchRuleCondition returns the captured groups of the regex matching value.
+func matchRuleCondition(ctx context.Context, rgx regex.Regex, value string) ([]string, bool) {
+	if !rgx.MatchString(ctx, value) {
+		return nil, false
+	}
+
+	matches := rgx.FindStringSubmatch(ctx, value)
+	if len(matches) == 0 {
+		return nil, false
+	}
+
+	return matches[1:], true
+}
+
+// lookupEnvFold returns the value of the environment variable with case
+// insensitive name, because config keys are lowercased.
+func lookupEnvFold(name string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+
+	for _, e := range os.Environ() {
+		if k, v, ok := strings.Cut(e, "="); ok && strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// ID returns its id.
+func (MapRules) ID() DetectorID {
+	return MapRuleDetector
+}
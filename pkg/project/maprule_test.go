@@ -0,0 +1,136 @@
+package project_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+	"github.com/wakatime/wakatime-cli/pkg/regex"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapRules_Detect(t *testing.T) {
+	tests := map[string]struct {
+		Rules    []project.MapRule
+		Env      map[string]string
+		Expected string
+	}{
+		"remote": {
+			Rules: []project.MapRule{
+				{
+					Project: "acme/{remote[0]}",
+					Remote:  regex.MustCompile(`(?i)github\.com[:/]acme/([^/]+?)(\.git)?$`),
+				},
+			},
+			Expected: "acme/billing",
+		},
+		"path and branch": {
+			Rules: []project.MapRule{
+				{
+					Project: "{path[0]}-{branch[0]}",
+					Path:    regex.MustCompile(`/home/user/([^/]+)/`),
+					Branch:  regex.MustCompile(`^release/(.+)$`),
+				},
+			},
+			Expected: "projects-2.0",
+		},
+		"hostname and detected project": {
+			Rules: []project.MapRule{
+				{
+					Project:  "{project} ({hostname[0]})",
+					Hostname: regex.MustCompile(`^(work)-laptop$`),
+				},
+			},
+			Expected: "billing (work)",
+		},
+		"environment variable": {
+			Rules: []project.MapRule{
+				{
+					Project: "{env.ci_project_namespace[0]}/{project}",
+					Env: map[string]regex.Regex{
+						"ci_project_namespace": regex.MustCompile(`^(acme)$`),
+					},
+				},
+			},
+			Env: map[string]string{
+				"CI_PROJECT_NAMESPACE": "acme",
+			},
+			Expected: "acme/billing",
+		},
+		"first matching rule": {
+			Rules: []project.MapRule{
+				{
+					Project: "other",
+					Remote:  regex.MustCompile(`gitlab\.com`),
+				},
+				{
+					Project: "acme",
+					Remote:  regex.MustCompile(`github\.com`),
+				},
+				{
+					Project: "last",
+					Remote:  regex.MustCompile(`github\.com`),
+				},
+			},
+			Expected: "acme",
+		},
+		"all conditions must match": {
+			Rules: []project.MapRule{
+				{
+					Project: "acme",
+					Remote:  regex.MustCompile(`github\.com`),
+					Branch:  regex.MustCompile(`^main$`),
+				},
+			},
+		},
+		"rule without conditions": {
+			Rules: []project.MapRule{
+				{
+					Project: "acme",
+				},
+			},
+		},
+		"invalid template": {
+			Rules: []project.MapRule{
+				{
+					Project: "{remote[3]}",
+					Remote:  regex.MustCompile(`github\.com`),
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range test.Env {
+				t.Setenv(k, v)
+			}
+
+			m := project.MapRules{
+				Filepath:  "/home/user/projects/billing/main.go",
+				Branch:    "release/2.0",
+				Hostname:  "work-laptop",
+				Project:   "billing",
+				RemoteURL: "git@github.com:acme/billing.git",
+				Rules:     test.Rules,
+			}
+
+			result, detected, err := m.Detect(context.Background())
+			require.NoError(t, err)
+
+			if test.Expected == "" {
+				assert.False(t, detected)
+				return
+			}
+
+			assert.True(t, detected)
+			assert.Equal(t, project.Result{Project: test.Expected}, result)
+		})
+	}
+}
+
+func TestMapRules_ID(t *testing.T) {
+	assert.Equal(t, project.MapRuleDetector, project.MapRules{}.ID())
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+var pijulChannelRegex = regexp.MustCompile(`^\s*current_channel\s*=\s*"(.+)"\s*$`)
+
+// Pijul contains pijul data.
+type Pijul struct {
+	// Filepath contains the entity path.
+	Filepath string
+}
+
+// Detect gets information about the pijul project for a given file.
+func (p Pijul) Detect(ctx context.Context) (Result, bool, error) {
+	var fp string
+
+	// Take only the directory
+	if fileOrDirExists(p.Filepath) {
+		fp = filepath.Dir(p.Filepath)
+	}
+
+	// Find for .pijul folder
+	pijulDirectory, found := FindFileOrDirectory(ctx, fp, ".pijul")
+	if !found {
+		return Result{}, false, nil
+	}
+
+	logger := log.Extract(ctx)
+	folder := filepath.Dir(pijulDirectory)
+
+	channel, err := findPijulChannel(ctx, pijulDirectory)
+	if err != nil {
+		logger.Errorf(
+			"error finding for channel name from %q: %s",
+			pijulDirectory,
+			err,
+		)
+	}
+
+	return Result{
+		Project: filepath.Base(folder),
+		Branch:  channel,
+		Folder:  folder,
+	}, true, nil
+}
+
+func findPijulChannel(ctx context.Context, fp string) (string, error) {
+	p := filepath.Join(fp, "config")
+	if !fileOrDirExists(p) {
+		return "main", nil
+	}
+
+	lines, err := ReadFile(ctx, p, maxMetadataLines)
+	if err != nil {
+		return "", fmt.Errorf("failed while opening file %q: %s", p, err)
+	}
+
+	for _, line := range lines {
+		if match := pijulChannelRegex.FindStringSubmatch(line); len(match) == 2 {
+			return match[1], nil
+		}
+	}
+
+	return "main", nil
+}
+
+// ID returns its id.
+func (Pijul) ID() DetectorID {
+	return PijulDetector
+}
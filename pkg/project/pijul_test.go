@@ -0,0 +1,74 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPijul_Detect(t *testing.T) {
+	fp := setupTestPijul(t)
+
+	copyFile(t, "testdata/pijul/config", filepath.Join(fp, "wakatime-cli/.pijul/config"))
+
+	p := project.Pijul{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := p.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "billing",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestPijul_Detect_DefaultChannel(t *testing.T) {
+	fp := setupTestPijul(t)
+
+	p := project.Pijul{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+	}
+
+	result, detected, err := p.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "main",
+		Folder:  filepath.Join(fp, "wakatime-cli"),
+	}, result)
+}
+
+func TestPijul_ID(t *testing.T) {
+	p := project.Pijul{}
+
+	assert.Equal(t, project.PijulDetector, p.ID())
+}
+
+func setupTestPijul(t *testing.T) (fp string) {
+	tmpDir := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(tmpDir, "wakatime-cli/src/pkg"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	tmpFile, err := os.Create(filepath.Join(tmpDir, "wakatime-cli/src/pkg/file.go"))
+	require.NoError(t, err)
+
+	defer tmpFile.Close()
+
+	err = os.Mkdir(filepath.Join(tmpDir, "wakatime-cli/.pijul"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	return tmpDir
+}
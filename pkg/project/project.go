@@ -32,6 +32,8 @@ const (
 	WakaTimeProjectFile = ".wakatime-project"
 	// maxRecursiveIteration limits the number of a func will be called recursively.
 	maxRecursiveIteration = 500
+	// maxMetadataLines limits the number of lines read from rev control metadata files.
+	maxMetadataLines = 100000
 )
 
 // DetectorID represents a detector ID.
@@ -52,15 +54,39 @@ const (
 	SubversionDetector
 	// TfvcDetector is the detector ID for tfvc detector.
 	TfvcDetector
+	// FossilDetector is the detector ID for fossil detector.
+	FossilDetector
+	// PijulDetector is the detector ID for pijul detector.
+	PijulDetector
+	// BazaarDetector is the detector ID for bazaar detector.
+	BazaarDetector
+	// DarcsDetector is the detector ID for darcs detector.
+	DarcsDetector
+	// JujutsuDetector is the detector ID for jujutsu detector.
+	JujutsuDetector
+	// WorkspaceDetector is the detector ID for monorepo workspace detector.
+	WorkspaceDetector
+	// DevContainerDetector is the detector ID for devcontainer detector.
+	DevContainerDetector
+	// MapRuleDetector is the detector ID for project map rule detector.
+	MapRuleDetector
 )
 
 const (
-	fileDetectorString       = "project-file-detector"
-	mapDetectorString        = "project-map-detector"
-	gitDetectorString        = "git-detector"
-	mercurialDetectorString  = "mercurial-detector"
-	subversionDetectorString = "svn-detector"
-	tfvcDetectorString       = "tfvc-detector"
+	fileDetectorString         = "project-file-detector"
+	mapDetectorString          = "project-map-detector"
+	gitDetectorString          = "git-detector"
+	mercurialDetectorString    = "mercurial-detector"
+	subversionDetectorString   = "svn-detector"
+	tfvcDetectorString         = "tfvc-detector"
+	fossilDetectorString       = "fossil-detector"
+	pijulDetectorString        = "pijul-detector"
+	bazaarDetectorString       = "bazaar-detector"
+	darcsDetectorString        = "darcs-detector"
+	jujutsuDetectorString      = "jujutsu-detector"
+	workspaceDetectorString    = "workspace-detector"
+	devContainerDetectorString = "devcontainer-detector"
+	mapRuleDetectorString      = "project-map-rule-detector"
 )
 
 // String implements fmt.Stringer interface.
@@ -78,6 +104,22 @@ func (d DetectorID) String() string {
 		return subversionDetectorString
 	case TfvcDetector:
 		return tfvcDetectorString
+	case FossilDetector:
+		return fossilDetectorString
+	case PijulDetector:
+		return pijulDetectorString
+	case BazaarDetector:
+		return bazaarDetectorString
+	case DarcsDetector:
+		return darcsDetectorString
+	case JujutsuDetector:
+		return jujutsuDetectorString
+	case WorkspaceDetector:
+		return workspaceDetectorString
+	case DevContainerDetector:
+		return devContainerDetectorString
+	case MapRuleDetector:
+		return mapRuleDetectorString
 	default:
 		return ""
 	}
@@ -101,18 +143,40 @@ type (
 		Project string
 		Branch  string
 		Folder  string
+		// RemoteURL is the url of the git remote, only set when requested.
+		RemoteURL string
 	}
 
 	// Config contains project detection configurations.
 	Config struct {
+		// GitRemoteAliases maps lowercased git remotes to a canonical project name
+		// when ProjectFromGitRemote is enabled.
+		GitRemoteAliases map[string]string
+		// GitRemoteNames are the names of the preferred git remotes in order of
+		// priority used when ProjectFromGitRemote is enabled.
+		GitRemoteNames []string
+		// GitWorkTrees contains git directories outside of their work tree.
+		GitWorkTrees []GitWorkTree
 		// HideProjectNames determines if the project name should be obfuscated by matching its path.
 		HideProjectNames []regex.Regex
+		// Hostname is the machine hostname matched by project map rules.
+		Hostname string
 		// Patterns contains the overridden project name per path.
 		MapPatterns []MapPattern
+		// MapRules contains the project map rules matched after revision control detection.
+		MapRules []MapRule
+		// PreferJujutsu when enabled detects jujutsu before git, so a .jj folder takes
+		// precedence over a colocated .git folder.
+		PreferJujutsu bool
 		// ProjectFromGitRemote when enabled uses the git remote as the project name instead of local git folder.
 		ProjectFromGitRemote bool
 		// Submodule contains the submodule configurations.
 		Submodule Submodule
+		// WorkspaceDetection when enabled uses the monorepo workspace package
+		// containing the entity as project name.
+		WorkspaceDetection bool
+		// WorkspaceProjectFormat is the pyfmt template for workspace project names.
+		WorkspaceProjectFormat string
 	}
 
 	// MapPattern contains the project name and regular expression for a specific path.
@@ -123,6 +187,26 @@ type (
 		Regex regex.Regex
 	}
 
+	// MapRule contains the project name template and the conditions of a project
+	// map rule. All conditions set must match.
+	MapRule struct {
+		// Name is the name of the rule.
+		Name string
+		// Project is the pyfmt template of the project name.
+		Project string
+		// Path is the regular expression matched against the entity path.
+		Path regex.Regex
+		// Remote is the regular expression matched against the git remote url.
+		Remote regex.Regex
+		// Branch is the regular expression matched against the branch name.
+		Branch regex.Regex
+		// Hostname is the regular expression matched against the hostname.
+		Hostname regex.Regex
+		// Env contains regular expressions matched against environment variables
+		// by lowercased name.
+		Env map[string]regex.Regex
+	}
+
 	// Submodule contains the submodule configurations.
 	Submodule struct {
 		// DisabledPatterns contains the paths to match against submodules
@@ -135,8 +219,8 @@ type (
 
 // WithDetection finds the current project and branch.
 // First looks for a .wakatime-project file or project map. Second, uses the
-// --project arg. Third, try to auto-detect using a revision control repository.
-// Last, uses the --alternate-project arg.
+// --project arg. Third, try to auto-detect using a revision control repository,
+// which project map rules can override. Last, uses the --alternate-project arg.
 func WithDetection(config Config) heartbeat.HandleOption {
 	return func(next heartbeat.Handle) heartbeat.Handle {
 		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
@@ -153,25 +237,86 @@ func WithDetection(config Config) heartbeat.HandleOption {
 					DetecterArg{Filepath: h.ProjectPathOverride, ShouldRun: true},
 				)
 
+				// keep track of what set the project and branch names
+				projectSource := newDetectorDecision(detector, "project")
+				branchSource := newDetectorDecision(detector, "branch")
+
 				// second, use project override
 				if result.Project == "" && h.ProjectOverride != "" {
 					result.Project = h.ProjectOverride
 					result.Folder = h.ProjectPathOverride
+					projectSource = heartbeat.Decision{Stage: "project", Rule: "project override", ConfigKey: "project"}
 				}
 
 				// third, autodetect with revision control with entity path.
 				// Then, autodetect with project folder. This tries to use the same project name
 				// across all IDEs instead of sometimes using alternate project when file is unsaved
 				if result.Project == "" || result.Branch == "" || result.Folder == "" {
-					revControlResult := DetectWithRevControl(
+					revControlResult, revControlDetector := DetectWithRevControl(
 						ctx,
-						config.Submodule.DisabledPatterns,
-						config.Submodule.MapPatterns,
-						config.ProjectFromGitRemote,
+						config,
 						DetecterArg{Filepath: h.Entity, ShouldRun: h.EntityType == heartbeat.FileType},
 						DetecterArg{Filepath: h.ProjectPathOverride, ShouldRun: true},
 					)
 
+					projectDetector := revControlDetector
+
+					// within a monorepo, use the workspace package as project
+					if config.WorkspaceDetection && h.EntityType == heartbeat.FileType && revControlResult.Folder != "" {
+						workspace := Workspace{
+							Filepath:      h.Entity,
+							Folder:        revControlResult.Folder,
+							Project:       revControlResult.Project,
+							ProjectFormat: config.WorkspaceProjectFormat,
+						}
+
+						workspaceResult, detected, err := workspace.Detect(ctx)
+						if err != nil {
+							logger.Errorf("unexpected error occurred at %q: %s", workspace.ID().String(), err)
+						}
+
+						if detected {
+							revControlResult.Project = workspaceResult.Project
+							revControlResult.Folder = workspaceResult.Folder
+							projectDetector = workspace.ID()
+						}
+					}
+
+					// project map rules can match on the detected git remote and branch
+					if len(config.MapRules) > 0 && result.Project == "" {
+						rulePath := h.ProjectPathOverride
+						if h.EntityType == heartbeat.FileType {
+							rulePath = h.Entity
+						}
+
+						mapRules := MapRules{
+							Filepath:  rulePath,
+							Branch:    firstNonEmptyString(result.Branch, revControlResult.Branch),
+							Hostname:  config.Hostname,
+							Project:   revControlResult.Project,
+							RemoteURL: revControlResult.RemoteURL,
+							Rules:     config.MapRules,
+						}
+
+						mapRulesResult, detected, err := mapRules.Detect(ctx)
+						if err != nil {
+							logger.Errorf("unexpected error occurred at %q: %s", mapRules.ID().String(), err)
+						}
+
+						if detected {
+							revControlResult.Project = mapRulesResult.Project
+							projectDetector = mapRules.ID()
+						}
+					}
+
+					if result.Project == "" && revControlResult.Project != "" {
+						projectSource = newDetectorDecision(projectDetector, "project")
+					}
+
+					if result.Branch == "" && revControlResult.Branch != "" {
+						branchSource = newDetectorDecision(revControlDetector, "branch")
+					}
+
 					result.Project = firstNonEmptyString(result.Project, revControlResult.Project)
 					result.Branch = firstNonEmptyString(result.Branch, revControlResult.Branch)
 					result.Folder = firstNonEmptyString(result.Folder, revControlResult.Folder)
@@ -181,11 +326,13 @@ func WithDetection(config Config) heartbeat.HandleOption {
 				if result.Project == "" && h.ProjectAlternate != "" {
 					result.Project = h.ProjectAlternate
 					result.Folder = firstNonEmptyString(h.ProjectPathOverride, result.Folder)
+					projectSource = heartbeat.Decision{Stage: "project", Rule: "alternate project", ConfigKey: "alternate-project"}
 				}
 
 				// fifth, use alternate branch
 				if result.Branch == "" && h.BranchAlternate != "" {
 					result.Branch = h.BranchAlternate
+					branchSource = heartbeat.Decision{Stage: "project", Rule: "alternate branch", ConfigKey: "alternate-branch"}
 				}
 
 				// sixth, use project folder found or entity's path
@@ -208,6 +355,11 @@ func WithDetection(config Config) heartbeat.HandleOption {
 					ProjectPathOverride: h.ProjectPathOverride,
 				}) && result.Project != "" && detector != FileDetector {
 					result.Project = obfuscateProjectName(ctx, result.Folder)
+					projectSource = heartbeat.Decision{
+						Stage:     "project",
+						Rule:      "hide project names",
+						ConfigKey: "settings.hide_project_names",
+					}
 				}
 
 				result.Folder = FormatProjectFolder(ctx, result.Folder)
@@ -220,6 +372,22 @@ func WithDetection(config Config) heartbeat.HandleOption {
 					}
 				}
 
+				if result.Project != "" {
+					projectSource.Field = "project"
+					projectSource.Value = result.Project
+					projectSource.Message = fmt.Sprintf("project set by %s", projectSource.Rule)
+
+					heartbeat.RecordDecision(ctx, &hh[n], projectSource)
+				}
+
+				if result.Branch != "" {
+					branchSource.Field = "branch"
+					branchSource.Value = result.Branch
+					branchSource.Message = fmt.Sprintf("branch set by %s", branchSource.Rule)
+
+					heartbeat.RecordDecision(ctx, &hh[n], branchSource)
+				}
+
 				hh[n].Project = &result.Project
 				hh[n].Branch = &result.Branch
 				hh[n].ProjectPath = result.Folder
@@ -230,6 +398,31 @@ func WithDetection(config Config) heartbeat.HandleOption {
 	}
 }
 
+// newDetectorDecision returns a project stage decision for the passed in
+// detector setting field, which is either project or branch. The projectmap
+// config key only applies to project names.
+func newDetectorDecision(detector DetectorID, field string) heartbeat.Decision {
+	d := heartbeat.Decision{
+		Stage: "project",
+		Rule:  detector.String(),
+	}
+
+	switch detector {
+	case FileDetector:
+		d.ConfigKey = WakaTimeProjectFile
+	case MapDetector:
+		if field == "project" {
+			d.ConfigKey = "projectmap"
+		}
+	case WorkspaceDetector:
+		d.ConfigKey = "workspace.enabled"
+	case MapRuleDetector:
+		d.ConfigKey = "projectmap_rules"
+	}
+
+	return d
+}
+
 // Detect finds the current project and branch from config plugins.
 func Detect(ctx context.Context, patterns []MapPattern, args ...DetecterArg) (Result, DetectorID) {
 	logger := log.Extract(ctx)
@@ -247,6 +440,9 @@ func Detect(ctx context.Context, patterns []MapPattern, args ...DetecterArg) (Re
 				Filepath: arg.Filepath,
 				Patterns: patterns,
 			},
+			DevContainer{
+				Filepath: arg.Filepath,
+			},
 		}
 
 		for _, p := range configPlugins {
@@ -268,12 +464,8 @@ func Detect(ctx context.Context, patterns []MapPattern, args ...DetecterArg) (Re
 }
 
 // DetectWithRevControl finds the current project and branch from rev control.
-func DetectWithRevControl(
-	ctx context.Context,
-	submoduleDisabledPatterns []regex.Regex,
-	submoduleProjectMapPatterns []MapPattern,
-	projectFromGitRemote bool,
-	args ...DetecterArg) Result {
+// The git remote url is only detected if project map rules are configured.
+func DetectWithRevControl(ctx context.Context, config Config, args ...DetecterArg) (Result, DetectorID) {
 	logger := log.Extract(ctx)
 
 	for _, arg := range args {
@@ -281,12 +473,27 @@ func DetectWithRevControl(
 			continue
 		}
 
-		var revControlPlugins = []Detecter{
+		jujutsu := Jujutsu{
+			Filepath: arg.Filepath,
+		}
+
+		var revControlPlugins []Detecter
+
+		// jujutsu repositories are often colocated with git
+		if config.PreferJujutsu {
+			revControlPlugins = append(revControlPlugins, jujutsu)
+		}
+
+		revControlPlugins = append(revControlPlugins,
 			Git{
 				Filepath:                    arg.Filepath,
-				ProjectFromGitRemote:        projectFromGitRemote,
-				SubmoduleDisabledPatterns:   submoduleDisabledPatterns,
-				SubmoduleProjectMapPatterns: submoduleProjectMapPatterns,
+				DetectRemoteURL:             len(config.MapRules) > 0,
+				ProjectFromGitRemote:        config.ProjectFromGitRemote,
+				RemoteAliases:               config.GitRemoteAliases,
+				RemoteNames:                 config.GitRemoteNames,
+				SubmoduleDisabledPatterns:   config.Submodule.DisabledPatterns,
+				SubmoduleProjectMapPatterns: config.Submodule.MapPatterns,
+				WorkTrees:                   config.GitWorkTrees,
 			},
 			Mercurial{
 				Filepath: arg.Filepath,
@@ -297,6 +504,22 @@ func DetectWithRevControl(
 			Tfvc{
 				Filepath: arg.Filepath,
 			},
+			Fossil{
+				Filepath: arg.Filepath,
+			},
+			Pijul{
+				Filepath: arg.Filepath,
+			},
+			Bazaar{
+				Filepath: arg.Filepath,
+			},
+			Darcs{
+				Filepath: arg.Filepath,
+			},
+		)
+
+		if !config.PreferJujutsu {
+			revControlPlugins = append(revControlPlugins, jujutsu)
 		}
 
 		for _, p := range revControlPlugins {
@@ -310,15 +533,16 @@ func DetectWithRevControl(
 
 			if detected {
 				return Result{
-					Project: result.Project,
-					Branch:  result.Branch,
-					Folder:  result.Folder,
-				}
+					Project:   result.Project,
+					Branch:    result.Branch,
+					Folder:    result.Folder,
+					RemoteURL: result.RemoteURL,
+				}, p.ID()
 			}
 		}
 	}
 
-	return Result{}
+	return Result{}, UnknownDetector
 }
 
 func obfuscateProjectName(ctx context.Context, folder string) string {
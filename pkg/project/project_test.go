@@ -146,6 +146,123 @@ func TestWithDetection_WakatimeProjectTakesPrecedence(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWithDetection_WorkspacePackage(t *testing.T) {
+	fp := setupTestGitBasic(t)
+
+	ctx := context.Background()
+
+	err := os.MkdirAll(filepath.Join(fp, "wakatime-cli/apps/web/src"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(fp, "wakatime-cli/pnpm-workspace.yaml"), []byte("packages:\n  - 'apps/*'\n"), 0600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(fp, "wakatime-cli/apps/web/package.json"), []byte(`{"name": "web"}`), 0600)
+	require.NoError(t, err)
+
+	entity := filepath.Join(fp, "wakatime-cli/apps/web/src/index.ts")
+	projectPath := filepath.Join(fp, "wakatime-cli/apps/web")
+	projectPath = project.FormatProjectFolder(ctx, projectPath)
+
+	if runtime.GOOS == "windows" {
+		entity = windows.FormatFilePath(entity)
+	}
+
+	opts := []heartbeat.HandleOption{
+		heartbeat.WithFormatting(),
+		project.WithDetection(project.Config{
+			WorkspaceDetection:     true,
+			WorkspaceProjectFormat: "{project}/{package}",
+		}),
+	}
+
+	sender := mockSender{
+		SendHeartbeatsFn: func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			assert.Equal(t, []heartbeat.Heartbeat{
+				{
+					Branch:           heartbeat.PointerTo("master"),
+					Entity:           entity,
+					EntityType:       heartbeat.FileType,
+					Project:          heartbeat.PointerTo("wakatime-cli/web"),
+					ProjectPath:      projectPath,
+					ProjectRootCount: heartbeat.PointerTo(project.CountSlashesInProjectFolder(projectPath)),
+				},
+			}, hh)
+
+			return nil, nil
+		},
+	}
+
+	handle := heartbeat.NewHandle(&sender, opts...)
+
+	_, err = handle(ctx, []heartbeat.Heartbeat{
+		{
+			EntityType: heartbeat.FileType,
+			Entity:     entity,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestWithDetection_MapRules(t *testing.T) {
+	fp := setupTestGitBasic(t)
+
+	ctx := context.Background()
+
+	entity := filepath.Join(fp, "wakatime-cli/src/pkg/file.go")
+	projectPath := project.FormatProjectFolder(ctx, filepath.Join(fp, "wakatime-cli"))
+
+	if runtime.GOOS == "windows" {
+		entity = windows.FormatFilePath(entity)
+	}
+
+	opts := []heartbeat.HandleOption{
+		heartbeat.WithFormatting(),
+		project.WithDetection(project.Config{
+			MapRules: []project.MapRule{
+				{
+					Name:    "fork",
+					Project: "fork",
+					Remote:  regex.MustCompile(`github\.com[:/]alan/`),
+				},
+				{
+					Name:    "wakatime",
+					Project: "{remote[0]}-{branch[0]}",
+					Remote:  regex.MustCompile(`github\.com[:/]wakatime/([^/]+?)(\.git)?$`),
+					Branch:  regex.MustCompile(`^(master|main)$`),
+				},
+			},
+		}),
+	}
+
+	sender := mockSender{
+		SendHeartbeatsFn: func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			assert.Equal(t, []heartbeat.Heartbeat{
+				{
+					Branch:           heartbeat.PointerTo("master"),
+					Entity:           entity,
+					EntityType:       heartbeat.FileType,
+					Project:          heartbeat.PointerTo("wakatime-cli-master"),
+					ProjectPath:      projectPath,
+					ProjectRootCount: heartbeat.PointerTo(project.CountSlashesInProjectFolder(projectPath)),
+				},
+			}, hh)
+
+			return nil, nil
+		},
+	}
+
+	handle := heartbeat.NewHandle(&sender, opts...)
+
+	_, err := handle(ctx, []heartbeat.Heartbeat{
+		{
+			EntityType: heartbeat.FileType,
+			Entity:     entity,
+		},
+	})
+	require.NoError(t, err)
+}
+
 func TestWithDetection_OverrideTakesPrecedence(t *testing.T) {
 	fp := setupTestGitBasic(t)
 
@@ -579,11 +696,9 @@ func TestDetect_MapDetected(t *testing.T) {
 func TestDetectWithRevControl_GitDetected(t *testing.T) {
 	fp := setupTestGitBasic(t)
 
-	result := project.DetectWithRevControl(
+	result, detector := project.DetectWithRevControl(
 		context.Background(),
-		[]regex.Regex{},
-		[]project.MapPattern{},
-		false,
+		project.Config{},
 		project.DetecterArg{
 			Filepath:  filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
 			ShouldRun: true,
@@ -596,16 +711,15 @@ func TestDetectWithRevControl_GitDetected(t *testing.T) {
 		Folder:  result.Folder,
 		Branch:  "master",
 	}, result)
+	assert.Equal(t, project.GitDetector, detector)
 }
 
 func TestDetectWithRevControl_GitRemoteDetected(t *testing.T) {
 	fp := setupTestGitBasic(t)
 
-	result := project.DetectWithRevControl(
+	result, detector := project.DetectWithRevControl(
 		context.Background(),
-		[]regex.Regex{},
-		[]project.MapPattern{},
-		true,
+		project.Config{ProjectFromGitRemote: true},
 		project.DetecterArg{
 			Filepath:  filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
 			ShouldRun: true,
@@ -618,6 +732,40 @@ func TestDetectWithRevControl_GitRemoteDetected(t *testing.T) {
 		Folder:  result.Folder,
 		Branch:  "master",
 	}, result)
+	assert.Equal(t, project.GitDetector, detector)
+}
+
+func TestDetectWithRevControl_JujutsuColocated(t *testing.T) {
+	fp := setupTestJujutsuColocated(t)
+
+	tests := map[string]struct {
+		PreferJujutsu bool
+		Expected      project.DetectorID
+	}{
+		"git first": {
+			Expected: project.GitDetector,
+		},
+		"jujutsu preferred": {
+			PreferJujutsu: true,
+			Expected:      project.JujutsuDetector,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, detector := project.DetectWithRevControl(
+				context.Background(),
+				project.Config{PreferJujutsu: test.PreferJujutsu},
+				project.DetecterArg{
+					Filepath:  filepath.Join(fp, "wakatime-cli/src/pkg/file.go"),
+					ShouldRun: true,
+				},
+			)
+
+			assert.Equal(t, "wakatime-cli", result.Project)
+			assert.Equal(t, test.Expected, detector)
+		})
+	}
 }
 
 func TestDetect_NoProjectDetected(t *testing.T) {
@@ -695,12 +843,20 @@ func TestCountSlashesInProjectFolder(t *testing.T) {
 
 func detectorIDTests() map[string]project.DetectorID {
 	return map[string]project.DetectorID{
-		"project-file-detector": project.FileDetector,
-		"project-map-detector":  project.MapDetector,
-		"git-detector":          project.GitDetector,
-		"mercurial-detector":    project.MercurialDetector,
-		"svn-detector":          project.SubversionDetector,
-		"tfvc-detector":         project.TfvcDetector,
+		"project-file-detector":     project.FileDetector,
+		"project-map-detector":      project.MapDetector,
+		"git-detector":              project.GitDetector,
+		"mercurial-detector":        project.MercurialDetector,
+		"svn-detector":              project.SubversionDetector,
+		"tfvc-detector":             project.TfvcDetector,
+		"fossil-detector":           project.FossilDetector,
+		"pijul-detector":            project.PijulDetector,
+		"bazaar-detector":           project.BazaarDetector,
+		"darcs-detector":            project.DarcsDetector,
+		"jujutsu-detector":          project.JujutsuDetector,
+		"workspace-detector":        project.WorkspaceDetector,
+		"devcontainer-detector":     project.DevContainerDetector,
+		"project-map-rule-detector": project.MapRuleDetector,
 	}
 }
 
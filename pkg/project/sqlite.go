@@ -0,0 +1,406 @@
+package project
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	// sqliteHeaderSize is the size of the database file header at the start of page 1.
+	sqliteHeaderSize = 100
+	// sqliteMagic is the header string of SQLite database files.
+	sqliteMagic = "SQLite format 3\x00"
+	// maxSqlitePayload is the maximum payload size of a row read.
+	maxSqlitePayload = 1 << 26
+	// maxSqliteTreeDepth is the maximum depth of a table b-tree, protecting
+	// against cycles in malformed database files.
+	maxSqliteTreeDepth = 32
+)
+
+// sqliteFile is a minimal read-only reader of SQLite database files, which
+// supports scanning the rows of rowid tables. It's used to read metadata
+// databases of revision control systems without requiring their binaries.
+type sqliteFile struct {
+	file       *os.File
+	pageSize   int
+	usableSize int
+	pageCount  uint32
+}
+
+// openSqliteFile opens the SQLite database file fp.
+func openSqliteFile(fp string) (*sqliteFile, error) {
+	f, err := os.Open(fp) // nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %s", err)
+	}
+
+	header := make([]byte, sqliteHeaderSize)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to read header: %s", err)
+	}
+
+	if string(header[:16]) != sqliteMagic {
+		_ = f.Close()
+		return nil, errors.New("not a sqlite database file")
+	}
+
+	// rows of write-ahead log databases may not be in the database file yet
+	if header[18] == 2 || header[19] == 2 {
+		_ = f.Close()
+		return nil, errors.New("write-ahead log databases are not supported")
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(header[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		_ = f.Close()
+		return nil, fmt.Errorf("invalid page size %d", pageSize)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("failed to stat file: %s", err)
+	}
+
+	return &sqliteFile{
+		file:       f,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(header[20]),
+		pageCount:  uint32(info.Size() / int64(pageSize)),
+	}, nil
+}
+
+// Close closes the database file.
+func (db *sqliteFile) Close() error {
+	return db.file.Close()
+}
+
+// tableRootPage returns the root page of the table name from the schema table.
+func (db *sqliteFile) tableRootPage(name string) (uint32, error) {
+	var (
+		root  uint32
+		found bool
+	)
+
+	// columns of the schema table are type, name, tbl_name, rootpage and sql
+	err := db.scanTable(1, func(_ int64, values []any) bool {
+		if len(values) < 4 || values[0] != "table" {
+			return true
+		}
+
+		if n, ok := values[1].(string); !ok || !strings.EqualFold(n, name) {
+			return true
+		}
+
+		if page, ok := values[3].(int64); ok && page > 0 && page <= math.MaxUint32 {
+			root = uint32(page)
+			found = true
+		}
+
+		return false
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !found {
+		return 0, fmt.Errorf("table %q not found", name)
+	}
+
+	return root, nil
+}
+
+// scanTable calls fn for each row of the rowid table with root page in rowid
+// order, until fn returns false.
+func (db *sqliteFile) scanTable(root uint32, fn func(rowid int64, values []any) bool) error {
+	_, err := db.scanPage(root, fn, 0)
+
+	return err
+}
+
+// scanPage scans the rows of a table b-tree page and returns false when the
+// scan was stopped.
+func (db *sqliteFile) scanPage(pageNumber uint32, fn func(rowid int64, values []any) bool, depth int) (bool, error) {
+	if depth > maxSqliteTreeDepth {
+		return false, errors.New("maximum table depth exceeded")
+	}
+
+	page, err := db.readPage(pageNumber)
+	if err != nil {
+		return false, err
+	}
+
+	offset := 0
+	if pageNumber == 1 {
+		offset = sqliteHeaderSize
+	}
+
+	if len(page) < offset+12 {
+		return false, fmt.Errorf("page %d is too small", pageNumber)
+	}
+
+	pageType := page[offset]
+	cellCount := int(binary.BigEndian.Uint16(page[offset+3 : offset+5]))
+
+	pointers := offset + 8
+	if pageType == 0x05 {
+		pointers = offset + 12
+	}
+
+	if pointers+cellCount*2 > len(page) {
+		return false, fmt.Errorf("invalid cell count on page %d", pageNumber)
+	}
+
+	for i := 0; i < cellCount; i++ {
+		cell := int(binary.BigEndian.Uint16(page[pointers+i*2:]))
+		if cell >= len(page) {
+			return false, fmt.Errorf("invalid cell pointer on page %d", pageNumber)
+		}
+
+		switch pageType {
+		case 0x05:
+			// interior table page, cell starts with the left child page number
+			if cell+4 > len(page) {
+				return false, fmt.Errorf("invalid interior cell on page %d", pageNumber)
+			}
+
+			next, err := db.scanPage(binary.BigEndian.Uint32(page[cell:]), fn, depth+1)
+			if err != nil || !next {
+				return next, err
+			}
+		case 0x0d:
+			// leaf table page, cell contains payload size, rowid and payload
+			size, n := readSqliteVarint(page[cell:])
+			if n == 0 {
+				return false, fmt.Errorf("invalid payload size on page %d", pageNumber)
+			}
+
+			rowid, m := readSqliteVarint(page[cell+n:])
+			if m == 0 {
+				return false, fmt.Errorf("invalid rowid on page %d", pageNumber)
+			}
+
+			payload, err := db.readPayload(page, cell+n+m, size)
+			if err != nil {
+				return false, fmt.Errorf("failed to read payload on page %d: %s", pageNumber, err)
+			}
+
+			values, err := decodeSqliteRecord(payload)
+			if err != nil {
+				return false, fmt.Errorf("failed to decode record on page %d: %s", pageNumber, err)
+			}
+
+			if !fn(int64(rowid), values) {
+				return false, nil
+			}
+		default:
+			return false, fmt.Errorf("unexpected page type %d of page %d", pageType, pageNumber)
+		}
+	}
+
+	if pageType == 0x05 {
+		// right-most child page
+		return db.scanPage(binary.BigEndian.Uint32(page[offset+8:]), fn, depth+1)
+	}
+
+	return true, nil
+}
+
+// readPage reads the page with 1-based page number n.
+func (db *sqliteFile) readPage(n uint32) ([]byte, error) {
+	if n == 0 || n > db.pageCount {
+		return nil, fmt.Errorf("invalid page number %d", n)
+	}
+
+	page := make([]byte, db.pageSize)
+	if _, err := db.file.ReadAt(page, int64(n-1)*int64(db.pageSize)); err != nil {
+		return nil, fmt.Errorf("failed to read page %d: %s", n, err)
+	}
+
+	return page, nil
+}
+
+// readPayload reads a payload of size bytes starting at offset of page,
+// following overflow pages if the payload doesn't fit into the page.
+func (db *sqliteFile) readPayload(page []byte, offset int, size uint64) ([]byte, error) {
+	if size > maxSqlitePayload {
+		return nil, fmt.Errorf("payload size %d exceeds maximum", size)
+	}
+
+	var (
+		total    = int(size)
+		usable   = db.usableSize
+		maxLocal = usable - 35
+	)
+
+	if total <= maxLocal {
+		if offset+total > len(page) {
+			return nil, errors.New("payload exceeds page")
+		}
+
+		return page[offset : offset+total], nil
+	}
+
+	minLocal := (usable-12)*32/255 - 23
+
+	local := minLocal + (total-minLocal)%(usable-4)
+	if local > maxLocal {
+		local = minLocal
+	}
+
+	if offset+local+4 > len(page) {
+		return nil, errors.New("payload exceeds page")
+	}
+
+	data := make([]byte, 0, total)
+	data = append(data, page[offset:offset+local]...)
+
+	next := binary.BigEndian.Uint32(page[offset+local:])
+
+	for len(data) < total {
+		if next == 0 {
+			return nil, errors.New("payload overflow pages are missing")
+		}
+
+		overflow, err := db.readPage(next)
+		if err != nil {
+			return nil, err
+		}
+
+		n := total - len(data)
+		if n > usable-4 {
+			n = usable - 4
+		}
+
+		data = append(data, overflow[4:4+n]...)
+		next = binary.BigEndian.Uint32(overflow[:4])
+	}
+
+	return data, nil
+}
+
+// decodeSqliteRecord decodes the column values of a record. Values are nil,
+// int64, float64, string or []byte.
+func decodeSqliteRecord(data []byte) ([]any, error) {
+	headerSize, n := readSqliteVarint(data)
+	if n == 0 || headerSize > uint64(len(data)) {
+		return nil, errors.New("invalid record header size")
+	}
+
+	var (
+		values []any
+		pos    = n
+		body   = int(headerSize)
+	)
+
+	for pos < int(headerSize) {
+		serialType, n := readSqliteVarint(data[pos:headerSize])
+		if n == 0 {
+			return nil, errors.New("invalid serial type")
+		}
+
+		pos += n
+
+		size, err := sqliteSerialSize(serialType)
+		if err != nil {
+			return nil, err
+		}
+
+		if body+size > len(data) {
+			return nil, errors.New("record value exceeds payload")
+		}
+
+		value := data[body : body+size]
+		body += size
+
+		switch {
+		case serialType == 0:
+			values = append(values, nil)
+		case serialType <= 6:
+			values = append(values, readSqliteInt(value))
+		case serialType == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serialType == 8:
+			values = append(values, int64(0))
+		case serialType == 9:
+			values = append(values, int64(1))
+		case serialType%2 == 0:
+			values = append(values, append([]byte{}, value...))
+		default:
+			values = append(values, string(value))
+		}
+	}
+
+	return values, nil
+}
+
+// sqliteSerialSize returns the size in bytes of a value of the serial type.
+func sqliteSerialSize(serialType uint64) (int, error) {
+	switch serialType {
+	case 0, 8, 9:
+		return 0, nil
+	case 1, 2, 3, 4:
+		return int(serialType), nil
+	case 5:
+		return 6, nil
+	case 6, 7:
+		return 8, nil
+	case 10, 11:
+		return 0, fmt.Errorf("reserved serial type %d", serialType)
+	}
+
+	if serialType > maxSqlitePayload*2+13 {
+		return 0, fmt.Errorf("invalid serial type %d", serialType)
+	}
+
+	if serialType%2 == 0 {
+		return int(serialType-12) / 2, nil
+	}
+
+	return int(serialType-13) / 2, nil
+}
+
+// readSqliteInt reads a big-endian two's complement integer.
+func readSqliteInt(b []byte) int64 {
+	var v int64
+
+	for i, c := range b {
+		if i == 0 {
+			v = int64(int8(c))
+			continue
+		}
+
+		v = v<<8 | int64(c)
+	}
+
+	return v
+}
+
+// readSqliteVarint reads a variable-length integer of up to 9 bytes and returns
+// it with the number of bytes read. It returns 0 bytes read if b is too short.
+func readSqliteVarint(b []byte) (uint64, int) {
+	var v uint64
+
+	for i := 0; i < 9 && i < len(b); i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+
+		v = v<<7 | uint64(b[i]&0x7f)
+
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+
+	return 0, 0
+}
@@ -2,10 +2,12 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/wakatime/wakatime-cli/pkg/log"
@@ -17,16 +19,13 @@ type Subversion struct {
 	Filepath string
 }
 
-// Detect gets information about the svn project for a given file.
+// Detect gets information about the svn project for a given file. The working
+// copy metadata is read from the .svn/wc.db database, or the .svn/entries file
+// of working copies before svn 1.7. It falls back to the svn binary if the
+// metadata cannot be read.
 func (s Subversion) Detect(ctx context.Context) (Result, bool, error) {
 	logger := log.Extract(ctx)
 
-	binary, ok := findSvnBinary(ctx)
-	if !ok {
-		logger.Debugln("svn binary not found")
-		return Result{}, false, nil
-	}
-
 	var fp string
 
 	// Take only the directory
@@ -34,28 +33,181 @@ func (s Subversion) Detect(ctx context.Context) (Result, bool, error) {
 		fp = filepath.Dir(s.Filepath)
 	}
 
-	// Find for .svn/wc.db file
-	svnConfigFile, found := FindFileOrDirectory(ctx, fp, filepath.Join(".svn", "wc.db"))
+	// Find for .svn/wc.db or .svn/entries file
+	svnDir, found := findSvnDir(ctx, fp)
 	if !found {
 		return Result{}, false, nil
 	}
 
-	info, ok, err := svnInfo(filepath.Join(svnConfigFile, "..", ".."), binary)
+	info, err := readSvnWorkingCopy(ctx, svnDir)
 	if err != nil {
-		return Result{}, false, fmt.Errorf("failed to get svn info: %s", err)
-	}
+		logger.Debugf("failed to read svn working copy %q, falling back to svn binary: %s", svnDir, err)
 
-	if !ok {
-		return Result{}, false, nil
+		binary, ok := findSvnBinary(ctx)
+		if !ok {
+			logger.Debugln("svn binary not found")
+			return Result{}, false, nil
+		}
+
+		info, ok, err = svnInfo(filepath.Dir(svnDir), binary)
+		if err != nil {
+			return Result{}, false, fmt.Errorf("failed to get svn info: %s", err)
+		}
+
+		if !ok {
+			return Result{}, false, nil
+		}
 	}
 
 	return Result{
 		Project: resolveSvnInfo(info, "Repository Root"),
-		Branch:  resolveSvnInfo(info, "URL"),
+		Branch:  resolveSvnBranch(info),
 		Folder:  strings.ReplaceAll(info["Repository Root"], "\r", ""),
 	}, true, nil
 }
 
+// findSvnDir finds the .svn folder of the working copy containing fp.
+func findSvnDir(ctx context.Context, fp string) (string, bool) {
+	if found, ok := FindFileOrDirectory(ctx, fp, filepath.Join(".svn", "wc.db")); ok {
+		return filepath.Dir(found), true
+	}
+
+	if found, ok := FindFileOrDirectory(ctx, fp, filepath.Join(".svn", "entries")); ok {
+		return filepath.Dir(found), true
+	}
+
+	return "", false
+}
+
+// readSvnWorkingCopy reads the repository root, url and relative url of the
+// working copy in the same format as svn info.
+func readSvnWorkingCopy(ctx context.Context, svnDir string) (map[string]string, error) {
+	var (
+		root, reposPath string
+		err             error
+	)
+
+	if wcdb := filepath.Join(svnDir, "wc.db"); fileOrDirExists(wcdb) {
+		root, reposPath, err = readSvnWcDB(wcdb)
+	} else {
+		root, reposPath, err = readSvnEntries(ctx, filepath.Join(svnDir, "entries"))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	root = strings.TrimRight(root, "/")
+	reposPath = strings.Trim(reposPath, "/")
+
+	url := root
+	if reposPath != "" {
+		url += "/" + reposPath
+	}
+
+	return map[string]string{
+		"Repository Root": root,
+		"URL":             url,
+		"Relative URL":    "^/" + reposPath,
+	}, nil
+}
+
+// readSvnWcDB reads the repository root and repository path of the working
+// copy root from the wc.db database used since svn 1.7.
+func readSvnWcDB(fp string) (string, string, error) {
+	db, err := openSqliteFile(fp)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open svn database %q: %s", fp, err)
+	}
+
+	defer db.Close()
+
+	nodes, err := db.tableRootPage("NODES")
+	if err != nil {
+		return "", "", err
+	}
+
+	var (
+		reposID   int64
+		reposPath string
+		found     bool
+	)
+
+	// columns of the NODES table start with wc_id, local_relpath, op_depth,
+	// parent_relpath, repos_id and repos_path
+	err = db.scanTable(nodes, func(_ int64, values []any) bool {
+		if len(values) < 6 || values[1] != "" || values[2] != int64(0) {
+			return true
+		}
+
+		reposID, found = values[4].(int64)
+		reposPath, _ = values[5].(string)
+
+		return false
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read svn nodes: %s", err)
+	}
+
+	if !found {
+		return "", "", errors.New("working copy root node not found")
+	}
+
+	repository, err := db.tableRootPage("REPOSITORY")
+	if err != nil {
+		return "", "", err
+	}
+
+	var root string
+
+	// columns of the REPOSITORY table are id, root and uuid, where id is the rowid
+	err = db.scanTable(repository, func(rowid int64, values []any) bool {
+		if rowid != reposID || len(values) < 2 {
+			return true
+		}
+
+		root, _ = values[1].(string)
+
+		return false
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read svn repository: %s", err)
+	}
+
+	if root == "" {
+		return "", "", fmt.Errorf("repository %d not found", reposID)
+	}
+
+	return root, reposPath, nil
+}
+
+// readSvnEntries reads the repository root and repository path of the folder
+// from the entries file used before svn 1.7. The entry of the folder itself
+// starts with the lines name, kind, revision, url and repository root.
+func readSvnEntries(ctx context.Context, fp string) (string, string, error) {
+	lines, err := ReadFile(ctx, fp, 6)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read svn entries %q: %s", fp, err)
+	}
+
+	if len(lines) < 6 {
+		return "", "", fmt.Errorf("unsupported svn entries format in %q", fp)
+	}
+
+	if format, err := strconv.Atoi(strings.TrimSpace(lines[0])); err != nil || format < 8 {
+		return "", "", fmt.Errorf("unsupported svn entries format %q in %q", lines[0], fp)
+	}
+
+	url := strings.TrimSpace(lines[4])
+	root := strings.TrimSpace(lines[5])
+
+	if root == "" || !strings.HasPrefix(url, root) {
+		return "", "", fmt.Errorf("invalid repository root %q of url %q in %q", root, url, fp)
+	}
+
+	return root, strings.TrimPrefix(url, root), nil
+}
+
 func svnInfo(fp string, binary string) (map[string]string, bool, error) {
 	if runtime.GOOS == "darwin" && !hasXcodeTools() {
 		return nil, false, nil
@@ -123,6 +275,25 @@ func resolveSvnInfo(info map[string]string, key string) string {
 	return ""
 }
 
+// resolveSvnBranch returns the branch name of the relative url, using the
+// trunk, branches and tags folders of the standard repository layout. It falls
+// back to the last folder of the url.
+func resolveSvnBranch(info map[string]string) string {
+	relative := strings.TrimPrefix(strings.ReplaceAll(info["Relative URL"], "\r", ""), "^")
+
+	parts := strings.Split(strings.Trim(relative, "/"), "/")
+	for i, part := range parts {
+		switch {
+		case part == "trunk":
+			return part
+		case (part == "branches" || part == "tags") && i+1 < len(parts):
+			return parts[i+1]
+		}
+	}
+
+	return resolveSvnInfo(info, "URL")
+}
+
 // ID returns its id.
 func (Subversion) ID() DetectorID {
 	return SubversionDetector
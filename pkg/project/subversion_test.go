@@ -14,8 +14,6 @@ import (
 )
 
 func TestSubversion_Detect(t *testing.T) {
-	skipIfBinaryNotFound(t)
-
 	fp := setupTestSvn(t)
 
 	s := project.Subversion{
@@ -34,8 +32,6 @@ func TestSubversion_Detect(t *testing.T) {
 }
 
 func TestSubversion_Detect_Branch(t *testing.T) {
-	skipIfBinaryNotFound(t)
-
 	fp := setupTestSvnBranch(t)
 
 	s := project.Subversion{
@@ -53,6 +49,113 @@ func TestSubversion_Detect_Branch(t *testing.T) {
 	}, result)
 }
 
+func TestSubversion_Detect_LargeDatabase(t *testing.T) {
+	fp := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(fp, "wakatime-cli/src"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(fp, "wakatime-cli/src/file.go"), []byte{}, 0600)
+	require.NoError(t, err)
+
+	copyDir(t, "testdata/svn_large", filepath.Join(fp, "wakatime-cli/.svn"))
+
+	s := project.Subversion{
+		Filepath: filepath.Join(fp, "wakatime-cli/src/file.go"),
+	}
+
+	result, detected, err := s.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, detected)
+	assert.Equal(t, project.Result{
+		Project: "wakatime-cli",
+		Branch:  "release-2.0",
+		Folder:  "file:///D:/temp/SVN/wakatime-cli",
+	}, result)
+}
+
+func TestSubversion_Detect_Entries(t *testing.T) {
+	tests := map[string]struct {
+		URL      string
+		Expected string
+	}{
+		"trunk": {
+			URL:      "https://svn.example.com/repos/wakatime-cli/trunk/src",
+			Expected: "trunk",
+		},
+		"branch": {
+			URL:      "https://svn.example.com/repos/wakatime-cli/branches/billing/src",
+			Expected: "billing",
+		},
+		"tag": {
+			URL:      "https://svn.example.com/repos/wakatime-cli/tags/v1.0.0",
+			Expected: "v1.0.0",
+		},
+		"non standard layout": {
+			URL:      "https://svn.example.com/repos/wakatime-cli/src",
+			Expected: "src",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fp := t.TempDir()
+
+			err := os.MkdirAll(filepath.Join(fp, "src/.svn"), os.FileMode(int(0700)))
+			require.NoError(t, err)
+
+			entries := "10\n\ndir\n42\n" + test.URL + "\nhttps://svn.example.com/repos/wakatime-cli\n\n\n"
+
+			err = os.WriteFile(filepath.Join(fp, "src/.svn/entries"), []byte(entries), 0600)
+			require.NoError(t, err)
+
+			err = os.WriteFile(filepath.Join(fp, "src/file.go"), []byte{}, 0600)
+			require.NoError(t, err)
+
+			s := project.Subversion{
+				Filepath: filepath.Join(fp, "src/file.go"),
+			}
+
+			result, detected, err := s.Detect(context.Background())
+			require.NoError(t, err)
+
+			assert.True(t, detected)
+			assert.Equal(t, project.Result{
+				Project: "wakatime-cli",
+				Branch:  test.Expected,
+				Folder:  "https://svn.example.com/repos/wakatime-cli",
+			}, result)
+		})
+	}
+}
+
+func TestSubversion_Detect_InvalidDatabase(t *testing.T) {
+	if _, found := findSvnBinary(); found {
+		t.Skip("Skipping because svn binary is installed in this machine.")
+	}
+
+	fp := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(fp, "wakatime-cli/.svn"), os.FileMode(int(0700)))
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(fp, "wakatime-cli/.svn/wc.db"), []byte("invalid"), 0600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(fp, "wakatime-cli/file.go"), []byte{}, 0600)
+	require.NoError(t, err)
+
+	s := project.Subversion{
+		Filepath: filepath.Join(fp, "wakatime-cli/file.go"),
+	}
+
+	_, detected, err := s.Detect(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, detected)
+}
+
 func TestSubversion_ID(t *testing.T) {
 	s := project.Subversion{}
 
@@ -157,10 +260,3 @@ func findSvnBinary() (string, bool) {
 
 	return "", false
 }
-
-func skipIfBinaryNotFound(t *testing.T) {
-	_, found := findSvnBinary()
-	if !found {
-		t.Skip("Skipping because svn binary is not installed in this machine.")
-	}
-}
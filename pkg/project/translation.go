@@ -0,0 +1,119 @@
+package project
+
+import (
+	"context"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+)
+
+// PathTranslation translates paths starting with a prefix as seen inside a
+// container or WSL to the corresponding host path, so a project has the same
+// name and folder on both sides.
+//
+// For example, configured in the ~/.wakatime.cfg file:
+//
+//	[path_translations]
+//	/workspaces/foo = /home/user/projects/foo
+//	/mnt/c/Users/user = C:\Users\user
+type PathTranslation struct {
+	// From is the path prefix to translate. It's matched case insensitive, as
+	// the keys of the config file are lowercased when loaded.
+	From string
+	// To is the path prefix it's translated to.
+	To string
+}
+
+// WithPathTranslation initializes and returns a heartbeat handle option, which
+// can be used in a heartbeat processing pipeline to translate the entity and
+// project path override of file heartbeats from container or WSL paths to host
+// paths. It runs before filtering and detection, which then see the host path.
+// The translated entity is sent, so files are named the same on both sides.
+func WithPathTranslation(translations []PathTranslation) heartbeat.HandleOption {
+	return func(next heartbeat.Handle) heartbeat.Handle {
+		return func(ctx context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+			if len(translations) == 0 {
+				return next(ctx, hh)
+			}
+
+			log.Extract(ctx).Debugln("execute path translation")
+
+			for n := range hh {
+				translatePaths(ctx, &hh[n], translations)
+			}
+
+			return next(ctx, hh)
+		}
+	}
+}
+
+// translatePaths translates the entity and project path override of a file
+// heartbeat to host paths.
+func translatePaths(ctx context.Context, h *heartbeat.Heartbeat, translations []PathTranslation) {
+	if h.EntityType != heartbeat.FileType {
+		return
+	}
+
+	if translated, ok := TranslatePath(h.Entity, translations); ok {
+		h.Entity = translated
+
+		heartbeat.RecordDecision(ctx, h, heartbeat.Decision{
+			Stage:     "path translation",
+			Rule:      "path translation",
+			ConfigKey: "path_translations",
+			Field:     "entity",
+			Value:     translated,
+			Message:   "entity path translated",
+		})
+	}
+
+	if translated, ok := TranslatePath(h.ProjectPathOverride, translations); ok {
+		h.ProjectPathOverride = translated
+	}
+}
+
+// TranslatePath translates fp with the first matching path translation. It
+// returns false if no path translation matched.
+func TranslatePath(fp string, translations []PathTranslation) (string, bool) {
+	if fp == "" {
+		return fp, false
+	}
+
+	for _, t := range translations {
+		from := strings.TrimRight(t.From, `/\`)
+		if from == "" || t.To == "" || len(fp) < len(from) {
+			continue
+		}
+
+		if !strings.EqualFold(fp[:len(from)], from) {
+			continue
+		}
+
+		rest := fp[len(from):]
+
+		// only match complete folder names
+		if rest != "" && rest[0] != '/' && rest[0] != '\\' {
+			continue
+		}
+
+		to := strings.TrimRight(t.To, `/\`)
+
+		// use the path separator of the translated path
+		if strings.Contains(to, `\`) {
+			rest = strings.ReplaceAll(rest, "/", `\`)
+		} else {
+			rest = strings.ReplaceAll(rest, `\`, "/")
+		}
+
+		if to == "" {
+			// translated to the root folder
+			to = "/"
+			rest = strings.TrimPrefix(rest, "/")
+		}
+
+		return to + rest, true
+	}
+
+	return fp, false
+}
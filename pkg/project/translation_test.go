@@ -0,0 +1,116 @@
+package project_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/heartbeat"
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPathTranslation(t *testing.T) {
+	opt := project.WithPathTranslation([]project.PathTranslation{
+		{From: "/workspaces", To: "/home/user/projects"},
+	})
+
+	handle := opt(func(_ context.Context, hh []heartbeat.Heartbeat) ([]heartbeat.Result, error) {
+		assert.Equal(t, []heartbeat.Heartbeat{
+			{
+				Entity:              "/home/user/projects/wakatime-cli/main.go",
+				EntityType:          heartbeat.FileType,
+				ProjectPathOverride: "/home/user/projects/wakatime-cli",
+			},
+			{
+				Entity:     "/workspaces/wakatime-cli",
+				EntityType: heartbeat.AppType,
+			},
+		}, hh)
+
+		return nil, nil
+	})
+
+	_, err := handle(context.Background(), []heartbeat.Heartbeat{
+		{
+			Entity:              "/workspaces/wakatime-cli/main.go",
+			EntityType:          heartbeat.FileType,
+			ProjectPathOverride: "/workspaces/wakatime-cli",
+		},
+		{
+			Entity:     "/workspaces/wakatime-cli",
+			EntityType: heartbeat.AppType,
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestTranslatePath(t *testing.T) {
+	translations := []project.PathTranslation{
+		{From: "/workspaces/wakatime-cli", To: "/home/user/projects/wakatime-cli"},
+		{From: "/mnt/c/users/user/", To: `C:\Users\user\`},
+		{From: `\\wsl$\Ubuntu\home`, To: "/home"},
+		{From: "/workspaces", To: "/home/user/projects"},
+	}
+
+	tests := map[string]struct {
+		Filepath   string
+		Expected   string
+		Translated bool
+	}{
+		"devcontainer": {
+			Filepath:   "/workspaces/wakatime-cli/cmd/run.go",
+			Expected:   "/home/user/projects/wakatime-cli/cmd/run.go",
+			Translated: true,
+		},
+		"folder itself": {
+			Filepath:   "/workspaces/wakatime-cli",
+			Expected:   "/home/user/projects/wakatime-cli",
+			Translated: true,
+		},
+		"first matching rule": {
+			Filepath:   "/workspaces/billing/main.go",
+			Expected:   "/home/user/projects/billing/main.go",
+			Translated: true,
+		},
+		"wsl to windows": {
+			Filepath:   "/mnt/c/Users/user/projects/main.go",
+			Expected:   `C:\Users\user\projects\main.go`,
+			Translated: true,
+		},
+		"windows to wsl": {
+			Filepath:   `\\wsl$\Ubuntu\home\user\main.go`,
+			Expected:   "/home/user/main.go",
+			Translated: true,
+		},
+		"longer folder name": {
+			Filepath:   "/workspaces/wakatime-cli-fork/main.go",
+			Expected:   "/home/user/projects/wakatime-cli-fork/main.go",
+			Translated: true,
+		},
+		"not matching": {
+			Filepath: "/tmp/workspaces/main.go",
+			Expected: "/tmp/workspaces/main.go",
+		},
+		"empty": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			translated, ok := project.TranslatePath(test.Filepath, translations)
+
+			assert.Equal(t, test.Translated, ok)
+			assert.Equal(t, test.Expected, translated)
+		})
+	}
+}
+
+func TestTranslatePath_PartialFolderName(t *testing.T) {
+	translated, ok := project.TranslatePath("/workspaces-old/main.go", []project.PathTranslation{
+		{From: "/workspaces", To: "/home/user/projects"},
+	})
+
+	assert.False(t, ok)
+	assert.Equal(t, "/workspaces-old/main.go", translated)
+}
@@ -0,0 +1,365 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/wakatime/wakatime-cli/pkg/glob"
+	"github.com/wakatime/wakatime-cli/pkg/log"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/slongfield/pyfmt"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultWorkspaceProjectFormat is the project name format used for workspace packages.
+const defaultWorkspaceProjectFormat = "{package}"
+
+// Workspace contains workspace data.
+type Workspace struct {
+	// Filepath contains the entity path.
+	Filepath string
+	// Folder is the root folder of the monorepo, e.g. the git root folder.
+	Folder string
+	// Project is the project name of the root folder.
+	Project string
+	// ProjectFormat is the pyfmt template for the project name. Supports the
+	// {project}, {package} and {path} fields. Defaults to {package}.
+	ProjectFormat string
+}
+
+// workspace contains the packages declared by the manifests of a monorepo.
+type workspace struct {
+	// patterns are glob patterns of package folders relative to the root folder.
+	patterns []workspacePattern
+	// excludes are glob patterns of folders which are never packages.
+	excludes []string
+	// markers are file names which make any folder a package, e.g. bazel BUILD files.
+	markers []string
+}
+
+// workspacePattern is a glob pattern of package folders. If manifest is set,
+// only matching folders containing the manifest file are packages, the same
+// way package managers resolve patterns like packages/**.
+type workspacePattern struct {
+	glob     string
+	manifest string
+}
+
+// Detect finds the workspace package of the entity within a monorepo. Packages
+// are declared by go.work, pnpm-workspace.yaml, package.json workspaces, Cargo
+// workspaces, lerna.json, Nx project.json files or Bazel BUILD files.
+//
+// For example, in a pnpm monorepo named acme with:
+//
+//	packages:
+//	  - 'apps/*'
+//
+// the file 'apps/web/src/index.ts' has project name 'web', or 'acme/web' when
+// the project format is '{project}/{package}'.
+func (w Workspace) Detect(ctx context.Context) (Result, bool, error) {
+	if w.Folder == "" || w.Filepath == "" {
+		return Result{}, false, nil
+	}
+
+	ws, ok := findWorkspace(ctx, w.Folder)
+	if !ok {
+		return Result{}, false, nil
+	}
+
+	rel, err := filepath.Rel(w.Folder, filepath.Dir(w.Filepath))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return Result{}, false, nil
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	// the innermost package takes precedence
+	for dir := rel; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if !ws.isPackage(filepath.Join(w.Folder, filepath.FromSlash(dir)), dir) {
+			continue
+		}
+
+		format := w.ProjectFormat
+		if format == "" {
+			format = defaultWorkspaceProjectFormat
+		}
+
+		project, err := pyfmt.Fmt(format, map[string]string{
+			"project": w.Project,
+			"package": path.Base(dir),
+			"path":    dir,
+		})
+		if err != nil {
+			return Result{}, false, fmt.Errorf("failed to format workspace project name %q: %s", format, err)
+		}
+
+		return Result{
+			Project: project,
+			Folder:  filepath.Join(w.Folder, filepath.FromSlash(dir)),
+		}, true, nil
+	}
+
+	return Result{}, false, nil
+}
+
+// isPackage returns true if the folder fp, with path rel relative to the
+// workspace root, is a workspace package.
+func (w workspace) isPackage(fp, rel string) bool {
+	for _, pattern := range w.excludes {
+		if glob.Match(pattern, rel) {
+			return false
+		}
+	}
+
+	for _, pattern := range w.patterns {
+		if !glob.Match(pattern.glob, rel) {
+			continue
+		}
+
+		if pattern.manifest == "" || fileOrDirExists(filepath.Join(fp, pattern.manifest)) {
+			return true
+		}
+	}
+
+	for _, marker := range w.markers {
+		if fileOrDirExists(filepath.Join(fp, marker)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findWorkspace reads the workspace manifests of the root folder.
+func findWorkspace(ctx context.Context, root string) (workspace, bool) {
+	logger := log.Extract(ctx)
+
+	var ws workspace
+
+	readers := []struct {
+		Filename string
+		Read     func(data string, ws *workspace) error
+	}{
+		{Filename: "go.work", Read: readGoWork},
+		{Filename: "pnpm-workspace.yaml", Read: readPnpmWorkspace},
+		{Filename: "package.json", Read: readPackageJSONWorkspaces},
+		{Filename: "Cargo.toml", Read: readCargoWorkspace},
+		{Filename: "lerna.json", Read: readLernaJSON},
+		{Filename: "workspace.json", Read: readNxWorkspaceJSON},
+	}
+
+	for _, r := range readers {
+		fp := filepath.Join(root, r.Filename)
+		if !fileOrDirExists(fp) {
+			continue
+		}
+
+		lines, err := ReadFile(ctx, fp, maxMetadataLines)
+		if err != nil {
+			logger.Warnf("failed to read workspace manifest %q: %s", fp, err)
+			continue
+		}
+
+		if err := r.Read(strings.Join(lines, "\n"), &ws); err != nil {
+			logger.Warnf("failed to parse workspace manifest %q: %s", fp, err)
+		}
+	}
+
+	if fileOrDirExists(filepath.Join(root, "nx.json")) {
+		ws.markers = append(ws.markers, "project.json")
+	}
+
+	for _, name := range []string{"WORKSPACE", "WORKSPACE.bazel", "MODULE.bazel"} {
+		if fileOrDirExists(filepath.Join(root, name)) {
+			ws.markers = append(ws.markers, "BUILD", "BUILD.bazel")
+			break
+		}
+	}
+
+	return ws, len(ws.patterns) > 0 || len(ws.markers) > 0
+}
+
+// readGoWork reads the use directives of a go.work file.
+func readGoWork(data string, ws *workspace) error {
+	var block bool
+
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+
+		line = strings.TrimSpace(line)
+
+		switch {
+		case block && line == ")":
+			block = false
+		case block:
+			ws.addPattern(strings.Trim(line, `"`), "go.mod")
+		case line == "use (":
+			block = true
+		case strings.HasPrefix(line, "use "):
+			ws.addPattern(strings.Trim(strings.TrimSpace(line[4:]), `"`), "go.mod")
+		}
+	}
+
+	return nil
+}
+
+// readPnpmWorkspace reads the packages of a pnpm-workspace.yaml file.
+func readPnpmWorkspace(data string, ws *workspace) error {
+	var manifest struct {
+		Packages []string `yaml:"packages"`
+	}
+
+	if err := yaml.Unmarshal([]byte(data), &manifest); err != nil {
+		return err
+	}
+
+	for _, p := range manifest.Packages {
+		ws.addPattern(p, "package.json")
+	}
+
+	return nil
+}
+
+// readPackageJSONWorkspaces reads the workspaces of a package.json file, which
+// are either a list of patterns or an object with a packages field.
+func readPackageJSONWorkspaces(data string, ws *workspace) error {
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+
+	if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+		return err
+	}
+
+	if len(manifest.Workspaces) == 0 {
+		return nil
+	}
+
+	var patterns []string
+
+	if err := json.Unmarshal(manifest.Workspaces, &patterns); err != nil {
+		var workspaces struct {
+			Packages []string `json:"packages"`
+		}
+
+		if err := json.Unmarshal(manifest.Workspaces, &workspaces); err != nil {
+			return err
+		}
+
+		patterns = workspaces.Packages
+	}
+
+	for _, p := range patterns {
+		ws.addPattern(p, "package.json")
+	}
+
+	return nil
+}
+
+// readCargoWorkspace reads the workspace members of a Cargo.toml file.
+func readCargoWorkspace(data string, ws *workspace) error {
+	var manifest struct {
+		Workspace struct {
+			Members []string `toml:"members"`
+			Exclude []string `toml:"exclude"`
+		} `toml:"workspace"`
+	}
+
+	if err := toml.Unmarshal([]byte(data), &manifest); err != nil {
+		return err
+	}
+
+	for _, p := range manifest.Workspace.Members {
+		ws.addPattern(p, "Cargo.toml")
+	}
+
+	for _, p := range manifest.Workspace.Exclude {
+		ws.addPattern("!"+p, "")
+	}
+
+	return nil
+}
+
+// readLernaJSON reads the packages of a lerna.json file.
+func readLernaJSON(data string, ws *workspace) error {
+	var manifest struct {
+		Packages []string `json:"packages"`
+	}
+
+	if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+		return err
+	}
+
+	if len(manifest.Packages) == 0 {
+		manifest.Packages = []string{"packages/*"}
+	}
+
+	for _, p := range manifest.Packages {
+		ws.addPattern(p, "package.json")
+	}
+
+	return nil
+}
+
+// readNxWorkspaceJSON reads the project folders of a Nx workspace.json file.
+// Projects are either a path or an object with a root field.
+func readNxWorkspaceJSON(data string, ws *workspace) error {
+	var manifest struct {
+		Projects map[string]json.RawMessage `json:"projects"`
+	}
+
+	if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+		return err
+	}
+
+	for _, raw := range manifest.Projects {
+		var root string
+
+		if err := json.Unmarshal(raw, &root); err != nil {
+			var project struct {
+				Root string `json:"root"`
+			}
+
+			if err := json.Unmarshal(raw, &project); err != nil {
+				return err
+			}
+
+			root = project.Root
+		}
+
+		ws.addPattern(root, "")
+	}
+
+	return nil
+}
+
+// addPattern adds a package pattern. Patterns starting with ! are excludes.
+func (w *workspace) addPattern(pattern, manifest string) {
+	exclude := strings.HasPrefix(pattern, "!")
+
+	pattern = strings.TrimPrefix(pattern, "!")
+	pattern = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(pattern)), "/")
+
+	if pattern == "" || pattern == "." {
+		return
+	}
+
+	if exclude {
+		w.excludes = append(w.excludes, pattern)
+		return
+	}
+
+	w.patterns = append(w.patterns, workspacePattern{glob: pattern, manifest: manifest})
+}
+
+// ID returns its id.
+func (Workspace) ID() DetectorID {
+	return WorkspaceDetector
+}
@@ -0,0 +1,185 @@
+package project_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wakatime/wakatime-cli/pkg/project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspace_Detect(t *testing.T) {
+	tests := map[string]struct {
+		Files         map[string]string
+		Entity        string
+		ProjectFormat string
+		Expected      project.Result
+	}{
+		"go work": {
+			Files: map[string]string{
+				"go.work":             "go 1.22\n\nuse (\n\t./services/api // api\n\t./tools\n)\n\nuse ./libs/auth\n",
+				"services/api/go.mod": "module example.com/api\n",
+			},
+			Entity:   "services/api/cmd/main.go",
+			Expected: project.Result{Project: "api", Folder: "services/api"},
+		},
+		"go work single use": {
+			Files: map[string]string{
+				"go.work":          "go 1.22\n\nuse ./libs/auth\n",
+				"libs/auth/go.mod": "module example.com/auth\n",
+			},
+			Entity:   "libs/auth/auth.go",
+			Expected: project.Result{Project: "auth", Folder: "libs/auth"},
+		},
+		"pnpm workspace": {
+			Files: map[string]string{
+				"pnpm-workspace.yaml":         "packages:\n  - 'packages/*'\n  - 'apps/**'\n  - '!**/test/**'\n",
+				"apps/web/admin/package.json": `{"name": "admin"}`,
+			},
+			Entity:   "apps/web/admin/src/index.ts",
+			Expected: project.Result{Project: "admin", Folder: "apps/web/admin"},
+		},
+		"pnpm workspace excluded": {
+			Files: map[string]string{
+				"pnpm-workspace.yaml":          "packages:\n  - 'packages/*'\n  - '!packages/legacy'\n",
+				"packages/legacy/package.json": `{"name": "legacy"}`,
+			},
+			Entity: "packages/legacy/index.js",
+		},
+		"package json workspaces": {
+			Files: map[string]string{
+				"package.json":             `{"name": "acme", "workspaces": ["packages/*"]}`,
+				"packages/ui/package.json": `{"name": "@acme/ui"}`,
+			},
+			Entity:   "packages/ui/src/button.tsx",
+			Expected: project.Result{Project: "ui", Folder: "packages/ui"},
+		},
+		"package json workspaces object": {
+			Files: map[string]string{
+				"package.json":              `{"workspaces": {"packages": ["modules/*"], "nohoist": ["**/react"]}}`,
+				"modules/core/package.json": `{"name": "core"}`,
+			},
+			Entity:   "modules/core/index.js",
+			Expected: project.Result{Project: "core", Folder: "modules/core"},
+		},
+		"cargo workspace": {
+			Files: map[string]string{
+				"Cargo.toml":               "[workspace]\nmembers = [\n  \"crates/*\",\n]\nexclude = [\"crates/bench\"]\n",
+				"crates/parser/Cargo.toml": "[package]\nname = \"parser\"\n",
+			},
+			Entity:   "crates/parser/src/lib.rs",
+			Expected: project.Result{Project: "parser", Folder: "crates/parser"},
+		},
+		"lerna": {
+			Files: map[string]string{
+				"lerna.json":                `{"version": "1.0.0"}`,
+				"packages/cli/package.json": `{"name": "cli"}`,
+			},
+			Entity:   "packages/cli/bin/cli.js",
+			Expected: project.Result{Project: "cli", Folder: "packages/cli"},
+		},
+		"nx project json": {
+			Files: map[string]string{
+				"nx.json":                 `{}`,
+				"apps/store/project.json": `{"name": "store"}`,
+			},
+			Entity:   "apps/store/src/main.ts",
+			Expected: project.Result{Project: "store", Folder: "apps/store"},
+		},
+		"nx workspace json": {
+			Files: map[string]string{
+				"workspace.json": `{"projects": {"shop": "apps/shop", "api": {"root": "apps/api"}}}`,
+			},
+			Entity:   "apps/api/src/main.ts",
+			Expected: project.Result{Project: "api", Folder: "apps/api"},
+		},
+		"bazel": {
+			Files: map[string]string{
+				"MODULE.bazel":                 "module(name = \"acme\")\n",
+				"services/billing/BUILD.bazel": "go_library(name = \"billing\")\n",
+			},
+			Entity:   "services/billing/internal/invoice.go",
+			Expected: project.Result{Project: "billing", Folder: "services/billing"},
+		},
+		"innermost package": {
+			Files: map[string]string{
+				"WORKSPACE":             "",
+				"services/BUILD":        "",
+				"services/search/BUILD": "",
+			},
+			Entity:   "services/search/index.go",
+			Expected: project.Result{Project: "search", Folder: "services/search"},
+		},
+		"project format": {
+			Files: map[string]string{
+				"go.work":             "use ./services/api\n",
+				"services/api/go.mod": "module example.com/api\n",
+			},
+			Entity:        "services/api/main.go",
+			ProjectFormat: "{project}/{path}",
+			Expected:      project.Result{Project: "wakatime-cli/services/api", Folder: "services/api"},
+		},
+		"outside of packages": {
+			Files: map[string]string{
+				"go.work": "use ./services/api\n",
+			},
+			Entity: "scripts/deploy.go",
+		},
+		"root file": {
+			Files: map[string]string{
+				"go.work": "use ./services/api\n",
+			},
+			Entity: "main.go",
+		},
+		"no workspace": {
+			Entity: "services/api/main.go",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			for file, content := range test.Files {
+				dest := filepath.Join(tmpDir, file)
+
+				err := os.MkdirAll(filepath.Dir(dest), os.FileMode(int(0700)))
+				require.NoError(t, err)
+
+				err = os.WriteFile(dest, []byte(content), 0600)
+				require.NoError(t, err)
+			}
+
+			w := project.Workspace{
+				Filepath:      filepath.Join(tmpDir, test.Entity),
+				Folder:        tmpDir,
+				Project:       "wakatime-cli",
+				ProjectFormat: test.ProjectFormat,
+			}
+
+			result, detected, err := w.Detect(context.Background())
+			require.NoError(t, err)
+
+			if test.Expected.Project == "" {
+				assert.False(t, detected)
+				return
+			}
+
+			assert.True(t, detected)
+			assert.Equal(t, project.Result{
+				Project: test.Expected.Project,
+				Folder:  filepath.Join(tmpDir, test.Expected.Folder),
+			}, result)
+		})
+	}
+}
+
+func TestWorkspace_ID(t *testing.T) {
+	w := project.Workspace{}
+
+	assert.Equal(t, project.WorkspaceDetector, w.ID())
+}
@@ -64,12 +64,15 @@ func WithDetection() heartbeat.HandleOption {
 				tmpFile, err := os.CreateTemp("", fmt.Sprintf("*_%s", filepath.Base(h.Entity)))
 				if err != nil {
 					logger.Errorf("failed to create temporary file: %s", err)
+					heartbeat.RecordFailed(ctx, h, fmt.Sprintf("failed to create temporary file: %s", err))
+
 					continue
 				}
 
 				c, err := NewClient(ctx, h.Entity)
 				if err != nil {
 					logger.Errorf("failed to create new remote client: %s", err)
+					heartbeat.RecordFailed(ctx, h, fmt.Sprintf("failed to create new remote client: %s", err))
 
 					deleteLocalFile(ctx, tmpFile.Name())
 
@@ -85,6 +88,8 @@ func WithDetection() heartbeat.HandleOption {
 						logger.Errorf("failed to download remote file using fallback option: %s", err)
 					}
 
+					heartbeat.RecordFailed(ctx, h, "failed to download remote file")
+
 					deleteLocalFile(ctx, tmpFile.Name())
 
 					continue
@@ -360,7 +360,7 @@ func TestWithDetection_Filtered(t *testing.T) {
 
 	opts := []heartbeat.HandleOption{
 		filter.WithFiltering(filter.Config{
-			Exclude:                    []regex.Regex{regex.NewRegexpWrap(regexp.MustCompile(".*"))},
+			Exclude:                    []filter.Pattern{{Regex: regex.NewRegexpWrap(regexp.MustCompile(".*"))}},
 			Include:                    nil,
 			IncludeOnlyWithProjectFile: true,
 		}),
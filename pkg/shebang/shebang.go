@@ -10,6 +10,7 @@ import (
 var (
 	splitPathRe    = regexp.MustCompile(`[/\\ ]`)
 	shebangPattern = `(?i)^%s(\.(exe|cmd|bat|bin))?$`
+	executableExt  = regexp.MustCompile(`(?i)\.(exe|cmd|bat|bin)$`)
 )
 
 // MatchString check if the given regular expression matches the last part of the
@@ -41,3 +42,50 @@ func MatchString(text string, pattern string) (bool, error) {
 
 	return shebangRe.MatchString(lastPart), nil
 }
+
+// Interpreter returns the lowercase name of the interpreter of the shebang, if
+// one exists. Interpreters run via env are resolved, skipping its options and
+// variable assignments, e.g. node for:
+//
+//	#!/usr/bin/env -S NODE_ENV=production node --experimental-modules
+func Interpreter(text string) (string, bool) {
+	firstLine := strings.ToLower(strings.Split(text, "\n")[0])
+	if !strings.HasPrefix(firstLine, "#!") {
+		return "", false
+	}
+
+	args := strings.Fields(firstLine[2:])
+	if len(args) == 0 {
+		return "", false
+	}
+
+	name := programName(args[0])
+	if name != "env" {
+		return name, name != ""
+	}
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "-u" || arg == "-c" || arg == "--unset" || arg == "--chdir":
+			// options with a separate value
+			i++
+		case strings.HasPrefix(arg, "-"), strings.Contains(arg, "="):
+			continue
+		default:
+			name := programName(arg)
+			return name, name != ""
+		}
+	}
+
+	return "", false
+}
+
+// programName returns the file name of the program path without executable
+// extension.
+func programName(fp string) string {
+	parts := splitPathRe.Split(fp, -1)
+
+	return executableExt.ReplaceAllString(parts[len(parts)-1], "")
+}
@@ -63,3 +63,64 @@ func TestShebang_MatchString(t *testing.T) {
 		})
 	}
 }
+
+func TestShebang_Interpreter(t *testing.T) {
+	tests := map[string]struct {
+		Text     string
+		Expected string
+	}{
+		"path": {
+			Text:     "#!/bin/bash -e\necho hello\n",
+			Expected: "bash",
+		},
+		"env": {
+			Text:     "#!/usr/bin/env python3.12",
+			Expected: "python3.12",
+		},
+		"env split string": {
+			Text:     "#!/usr/bin/env -S node --experimental-modules",
+			Expected: "node",
+		},
+		"env variable assignment": {
+			Text:     "#!/usr/bin/env -S RUBYOPT=-W0 ruby2.7",
+			Expected: "ruby2.7",
+		},
+		"env unset": {
+			Text:     "#!/usr/bin/env -u PYTHONPATH python",
+			Expected: "python",
+		},
+		"windows path": {
+			Text:     "#!C:\\Python\\Python.exe",
+			Expected: "python",
+		},
+		"space after shebang": {
+			Text:     "#! /usr/bin/perl -w",
+			Expected: "perl",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			interpreter, ok := shebang.Interpreter(test.Text)
+			require.True(t, ok)
+
+			assert.Equal(t, test.Expected, interpreter)
+		})
+	}
+}
+
+func TestShebang_Interpreter_NotFound(t *testing.T) {
+	tests := map[string]string{
+		"no shebang":    "echo hello",
+		"only shebang":  "#!",
+		"env only":      "#!/usr/bin/env",
+		"env only args": "#!/usr/bin/env -S -i",
+	}
+
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, ok := shebang.Interpreter(text)
+			assert.False(t, ok)
+		})
+	}
+}